@@ -11,6 +11,7 @@ import (
 	k8sclient "github.com/monitoring-engine/monitoring-tool/internal/collector"
 	"github.com/monitoring-engine/monitoring-tool/internal/config"
 	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
 	"github.com/monitoring-engine/monitoring-tool/internal/notifier"
 	"github.com/monitoring-engine/monitoring-tool/internal/processor"
 	alertservice "github.com/monitoring-engine/monitoring-tool/internal/service"
@@ -65,17 +66,73 @@ func runMigrations(cfg config.PostgresConfig) error {
 	return nil
 }
 
-// initAlertService initializes the alert service with all its dependencies
-func initAlertService(postgresDB *gorm.DB) alertservice.AlertService {
+// initAlertRepo initializes the alert repository
+func initAlertRepo(postgresDB *gorm.DB) alertrepo.AlertRepo {
 	alertRepo := alertrepo.NewPostgresAlertRepo(postgresDB)
 	logger.Info().Msg("Alert repository initialized (PostgreSQL)")
+	return alertRepo
+}
 
-	alertService := alertservice.NewAlertService(alertRepo)
+// initAlertService initializes the alert service with all its dependencies
+func initAlertService(alertRepo alertrepo.AlertRepo, stateManager *processor.AlertStateManager) alertservice.AlertService {
+	alertService := alertservice.NewAlertService(alertRepo, stateManager)
 	logger.Info().Msg("Alert service initialized")
 
 	return alertService
 }
 
+// initNotificationLog initializes the notification log that records every delivery and the service reading it
+func initNotificationLog(postgresDB *gorm.DB, alertRepo alertrepo.AlertRepo) (alertrepo.NotificationRepo, alertservice.NotificationService) {
+	notificationRepo := alertrepo.NewPostgresNotificationRepo(postgresDB)
+	notificationService := alertservice.NewNotificationService(notificationRepo, alertRepo)
+	logger.Info().Msg("Notification log initialized")
+	return notificationRepo, notificationService
+}
+
+// initOutbox initializes the outbox that queues notifications with alert changes and delivers them with retries
+func initOutbox(postgresDB *gorm.DB, alertRepo alertrepo.AlertRepo, notifications alertrepo.NotificationRepo, cfg config.DeliveryConfig) *processor.Outbox {
+	transactor := alertrepo.NewPostgresTransactor(postgresDB)
+	return processor.NewOutbox(transactor, notifications, alertRepo, cfg)
+}
+
+// initSilences initializes the silence repository and service and registers the
+// silencer with the state manager so matching alerts are stored but not notified
+func initSilences(postgresDB *gorm.DB, stateManager *processor.AlertStateManager) alertservice.SilenceService {
+	silenceRepo := alertrepo.NewPostgresSilenceRepo(postgresDB)
+	stateManager.AddSuppressor(processor.NewSilencer(silenceRepo))
+	silenceService := alertservice.NewSilenceService(silenceRepo)
+	logger.Info().Msg("Silence service initialized")
+	return silenceService
+}
+
+// initMaintenance initializes maintenance windows and registers the maintenance suppressor
+// with the state manager; the suppressor is also given to the node watcher to track cordons
+func initMaintenance(postgresDB *gorm.DB, stateManager *processor.AlertStateManager) (alertservice.MaintenanceService, *processor.MaintenanceSuppressor) {
+	maintenanceRepo := alertrepo.NewPostgresMaintenanceRepo(postgresDB)
+	maintenance := processor.NewMaintenanceSuppressor(maintenanceRepo)
+	stateManager.AddSuppressor(maintenance)
+	maintenanceService := alertservice.NewMaintenanceService(maintenanceRepo)
+	logger.Info().Msg("Maintenance service initialized")
+	return maintenanceService, maintenance
+}
+
+// initInhibitor registers the inhibitor with the state manager so alerts whose cause is
+// already alerting are stored but not notified
+func initInhibitor(alertRepo alertrepo.AlertRepo, stateManager *processor.AlertStateManager, rules []config.InhibitRuleConfig) error {
+	if len(rules) == 0 {
+		logger.Info().Msg("No inhibit rules configured")
+		return nil
+	}
+
+	inhibitor, err := processor.NewInhibitor(alertRepo, rules)
+	if err != nil {
+		return err
+	}
+	stateManager.AddSuppressor(inhibitor)
+	logger.Info().Int("rules", len(rules)).Msg("Inhibitor initialized")
+	return nil
+}
+
 // initK8sClient initializes the Kubernetes client
 func initK8sClient(ctx context.Context) (*k8sclient.K8sClient, error) {
 	k8sClient, err := k8sclient.NewK8sClient()
@@ -105,58 +162,224 @@ func initWebSocketHub(ctx context.Context, eventBus *processor.EventBus) *websoc
 }
 
 // initEmailDispatcher initializes the email notification dispatcher if configured
-func initEmailDispatcher(cfg config.EmailConfig, eventBus *processor.EventBus) {
+func initEmailDispatcher(cfg config.EmailConfig, outbox *processor.Outbox) error {
 	if !cfg.Enabled {
 		logger.Info().Msg("Email notifications disabled in configuration")
+		return nil
+	}
+
+	if cfg.SMTPHost == "" || cfg.Username == "" {
+		logger.Warn().Msg("Email configuration incomplete - notifications disabled")
+		return nil
+	}
+
+	emailDispatcher, err := notifier.NewEmailDispatcher(cfg)
+	if err != nil {
+		return err
+	}
+	outbox.Register(models.ChannelTypeEmail, "", emailDispatcher)
+	logger.Info().
+		Str("smtp_host", cfg.SMTPHost).
+		Strs("to", cfg.To).
+		Bool("digest", cfg.Digest.Enabled).
+		Msg("Email dispatcher enabled")
+	return nil
+}
+
+// initSlackDispatcher initializes the Slack notification dispatcher if configured
+func initSlackDispatcher(cfg config.SlackConfig, outbox *processor.Outbox) {
+	if !cfg.Enabled {
+		logger.Info().Msg("Slack notifications disabled in configuration")
 		return
 	}
 
-	if cfg.SMTPHost != "" && cfg.Username != "" {
-		emailDispatcher := notifier.NewEmailDispatcher(cfg)
-		eventBus.Subscribe(emailDispatcher)
+	if cfg.WebhookURL != "" || len(cfg.SeverityWebhooks) > 0 || cfg.BotToken != "" {
+		slackDispatcher := notifier.NewSlackDispatcher(cfg)
+		outbox.Register(models.ChannelTypeSlack, "", slackDispatcher)
 		logger.Info().
-			Str("smtp_host", cfg.SMTPHost).
-			Strs("to", cfg.To).
-			Msg("Email dispatcher enabled")
+			Bool("bot_token", cfg.BotToken != "").
+			Msg("Slack dispatcher enabled")
 	} else {
-		logger.Warn().Msg("Email configuration incomplete - notifications disabled")
+		logger.Warn().Msg("Slack configuration incomplete - notifications disabled")
+	}
+}
+
+// initWebhookDispatcher initializes the generic webhook dispatcher if endpoints are configured
+func initWebhookDispatcher(cfg config.WebhooksConfig, outbox *processor.Outbox) error {
+	if !cfg.Enabled {
+		logger.Info().Msg("Webhook notifications disabled in configuration")
+		return nil
+	}
+
+	if len(cfg.Endpoints) == 0 {
+		logger.Warn().Msg("No webhook endpoints configured - notifications disabled")
+		return nil
+	}
+
+	webhookDispatcher, err := notifier.NewWebhookDispatcher(cfg)
+	if err != nil {
+		return err
 	}
+	// Each endpoint is its own outbox target, so a failing endpoint is retried without resending to the others
+	for _, endpoint := range webhookDispatcher.Endpoints() {
+		outbox.Register(models.ChannelTypeWebhook, endpoint.Name(), endpoint)
+	}
+	logger.Info().Int("endpoints", len(cfg.Endpoints)).Msg("Webhook dispatcher enabled")
+	return nil
+}
+
+// initPagerDutyDispatcher initializes the PagerDuty dispatcher if configured
+func initPagerDutyDispatcher(cfg config.PagerDutyConfig, outbox *processor.Outbox) {
+	if !cfg.Enabled {
+		logger.Info().Msg("PagerDuty notifications disabled in configuration")
+		return
+	}
+
+	if cfg.RoutingKey != "" {
+		pagerDutyDispatcher := notifier.NewPagerDutyDispatcher(cfg)
+		outbox.Register(models.ChannelTypePagerDuty, "", pagerDutyDispatcher)
+		logger.Info().
+			Strs("severities", cfg.Severities).
+			Msg("PagerDuty dispatcher enabled")
+	} else {
+		logger.Warn().Msg("PagerDuty routing key missing - notifications disabled")
+	}
+}
+
+// initNotificationRouter routes alerts through the routing tree to its receivers. The outbox
+// queues a notification per receiver channel and retries each on its own.
+func initNotificationRouter(cfg *config.Config, outbox *processor.Outbox) error {
+	receivers, err := notifier.BuildReceivers(cfg)
+	if err != nil {
+		return err
+	}
+
+	router, err := notifier.NewRouter(cfg.Routing, receivers)
+	if err != nil {
+		return err
+	}
+	outbox.UseRouter(router)
+	logger.Info().
+		Int("receivers", len(receivers)).
+		Str("default_receiver", cfg.Routing.Route.Receiver).
+		Msg("Notification routing enabled")
+	return nil
+}
+
+// initRuleEngine builds the alert rules from the built-in defaults and the optional rules file
+func initRuleEngine(cfg config.AlertRulesConfig) (*processor.RuleEngine, error) {
+	rules := processor.DefaultRules(cfg)
+	if cfg.RulesFile != "" {
+		overrides, err := processor.LoadRulesFile(cfg.RulesFile)
+		if err != nil {
+			return nil, err
+		}
+		rules = processor.MergeRules(rules, overrides)
+	}
+
+	ruleEngine, err := processor.NewRuleEngine(rules)
+	if err != nil {
+		return nil, err
+	}
+	ruleEngine.SetNamespaceOverrides(cfg.Namespaces)
+	logger.Info().
+		Int("rules", len(rules)).
+		Str("rules_file", cfg.RulesFile).
+		Msg("Alert rule engine initialized")
+	return ruleEngine, nil
 }
 
 // initAlertEngine initializes the alert evaluator engine
-func initAlertEngine(ctx context.Context, alertRepo alertrepo.AlertRepo, eventBus *processor.EventBus) *processor.EvaluatorEngine {
-	alertEngine := processor.NewEvaluatorEngine(alertRepo, eventBus)
+func initAlertEngine(ctx context.Context, alertRepo alertrepo.AlertRepo, eventBus *processor.EventBus, ruleEngine *processor.RuleEngine) *processor.EvaluatorEngine {
+	alertEngine := processor.NewEvaluatorEngine(alertRepo, eventBus, ruleEngine)
 	alertEngine.Start(ctx)
 	logger.Info().Msg("Alert evaluator engine started")
 	return alertEngine
 }
 
-// initK8sWatchers initializes the Kubernetes pod, node, and metrics watchers
+// initK8sWatchers initializes the Kubernetes pod, node, workload, job, volume, and metrics watchers
 func initK8sWatchers(
 	ctx context.Context,
 	k8sClient *k8sclient.K8sClient,
 	alertEngine *processor.EvaluatorEngine,
-) (*k8sclient.PodWatcher, *k8sclient.NodeWatcher, *k8sclient.MetricsWatcher) {
-	// Get the state manager and worker pool from alert engine
+	maintenance *processor.MaintenanceSuppressor,
+	alertRules config.AlertRulesConfig,
+) (*k8sclient.PodWatcher, *k8sclient.NodeWatcher, *k8sclient.WorkloadWatcher, *k8sclient.JobWatcher, *k8sclient.VolumeWatcher, *k8sclient.MetricsWatcher) {
+	// Get the state manager, rule engine and worker pool from alert engine
 	stateManager := alertEngine.GetStateManager()
+	ruleEngine := alertEngine.GetRuleEngine()
 	workerPool := alertEngine.GetWorkerPool()
 
 	// Pod watcher
-	podWatcher := k8sclient.NewPodWatcher(k8sClient, stateManager, workerPool)
+	podWatcher := k8sclient.NewPodWatcher(k8sClient, stateManager, ruleEngine, workerPool)
 	podWatcher.Start(ctx)
 	logger.Info().Msg("Pod watcher started with worker pool")
 
 	// Node watcher
-	nodeWatcher := k8sclient.NewNodeWatcher(k8sClient, stateManager, workerPool)
+	nodeWatcher := k8sclient.NewNodeWatcher(k8sClient, stateManager, ruleEngine, maintenance, workerPool)
 	nodeWatcher.Start(ctx)
 	logger.Info().Msg("Node watcher started with worker pool")
 
+	// Workload watcher
+	workloadWatcher := k8sclient.NewWorkloadWatcher(k8sClient, stateManager, ruleEngine, workerPool)
+	workloadWatcher.Start(ctx)
+	logger.Info().Msg("Workload watcher started for Deployments, StatefulSets and DaemonSets")
+
+	// Job watcher
+	jobWatcher := k8sclient.NewJobWatcher(k8sClient, stateManager, alertRules, workerPool)
+	jobWatcher.Start(ctx)
+	logger.Info().Msg("Job watcher started for Jobs and CronJobs")
+
+	// Volume watcher
+	volumeWatcher := k8sclient.NewVolumeWatcher(k8sClient, stateManager, ruleEngine, alertRules, k8sClient.GetKubeletClient(), workerPool)
+	volumeWatcher.Start(ctx)
+	logger.Info().Msg("Volume watcher started for PersistentVolumeClaims and volume usage")
+
 	// Metrics watcher
-	metricsWatcher := k8sclient.NewMetricsWatcher(k8sClient, stateManager, workerPool)
+	metricsWatcher := k8sclient.NewMetricsWatcher(k8sClient, stateManager, ruleEngine, workerPool)
 	metricsWatcher.Start(ctx)
 	logger.Info().Msg("Metrics watcher started for CPU/memory monitoring")
 
-	return podWatcher, nodeWatcher, metricsWatcher
+	return podWatcher, nodeWatcher, workloadWatcher, jobWatcher, volumeWatcher, metricsWatcher
+}
+
+// initSummaryWatcher initializes the watcher reading container usage from the kubelet Summary API.
+// Usage is streamed to WebSocket clients and evaluated against container limits.
+func initSummaryWatcher(
+	ctx context.Context,
+	k8sClient *k8sclient.K8sClient,
+	alertEngine *processor.EvaluatorEngine,
+	wsHub *websocket.Hub,
+	alertRules config.AlertRulesConfig,
+) *k8sclient.SummaryWatcher {
+	summaryWatcher := k8sclient.NewSummaryWatcher(k8sClient, alertEngine.GetStateManager(), alertEngine.GetRuleEngine(),
+		k8sClient.GetKubeletClient(), wsHub, alertRules, alertEngine.GetWorkerPool())
+	summaryWatcher.Start(ctx)
+	logger.Info().Msg("Summary watcher started for container usage from the kubelets")
+	return summaryWatcher
+}
+
+// initEventWatcher initializes the Kubernetes Event watcher if enabled. Warning events are
+// streamed to WebSocket clients and alert per the configured reasons.
+func initEventWatcher(
+	ctx context.Context,
+	k8sClient *k8sclient.K8sClient,
+	alertEngine *processor.EvaluatorEngine,
+	wsHub *websocket.Hub,
+	cfg config.EventsConfig,
+) (*k8sclient.EventWatcher, error) {
+	if !cfg.Enabled {
+		logger.Info().Msg("Event watcher disabled")
+		return nil, nil
+	}
+
+	eventWatcher, err := k8sclient.NewEventWatcher(k8sClient, alertEngine.GetStateManager(), wsHub, cfg, alertEngine.GetWorkerPool())
+	if err != nil {
+		return nil, err
+	}
+	eventWatcher.Start(ctx)
+	logger.Info().Msg("Event watcher started for Warning events")
+	return eventWatcher, nil
 }
 
 // initDependencies creates and validates the dependencies container
@@ -164,6 +387,10 @@ func initDependencies(
 	postgresDB *gorm.DB,
 	k8sClient *k8sclient.K8sClient,
 	alertService alertservice.AlertService,
+	silenceService alertservice.SilenceService,
+	maintenanceService alertservice.MaintenanceService,
+	ruleService alertservice.RuleService,
+	notificationService alertservice.NotificationService,
 	eventBus *processor.EventBus,
 	wsHub *websocket.Hub,
 ) (*app.Dependencies, error) {
@@ -171,6 +398,10 @@ func initDependencies(
 	if err != nil {
 		return nil, err
 	}
+	deps.SilenceService = silenceService
+	deps.MaintenanceService = maintenanceService
+	deps.RuleService = ruleService
+	deps.NotificationService = notificationService
 	logger.Info().Msg("Dependencies container initialized")
 	return deps, nil
 }
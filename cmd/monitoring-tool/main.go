@@ -17,7 +17,6 @@ import (
 	"github.com/monitoring-engine/monitoring-tool/internal/logger"
 	"github.com/monitoring-engine/monitoring-tool/internal/processor"
 	"github.com/monitoring-engine/monitoring-tool/internal/service"
-	"github.com/monitoring-engine/monitoring-tool/internal/repository"
 	"github.com/monitoring-engine/monitoring-tool/internal/websocket"
 	"gorm.io/gorm"
 )
@@ -29,13 +28,21 @@ var (
 	appCancel    context.CancelFunc
 	postgresDB   *gorm.DB
 	alertService service.AlertService
+	silenceService service.SilenceService
+	maintenanceService service.MaintenanceService
 	k8sClient    *collector.K8sClient
 	eventBus     *processor.EventBus
+	outbox       *processor.Outbox
 	wsHub        *websocket.Hub
 	alertEngine    *processor.EvaluatorEngine
 	podWatcher     *collector.PodWatcher
 	nodeWatcher    *collector.NodeWatcher
+	workloadWatcher *collector.WorkloadWatcher
+	jobWatcher     *collector.JobWatcher
+	volumeWatcher  *collector.VolumeWatcher
+	eventWatcher   *collector.EventWatcher
 	metricsWatcher *collector.MetricsWatcher
+	summaryWatcher *collector.SummaryWatcher
 	deps           *app.Dependencies
 )
 
@@ -62,8 +69,10 @@ func init() {
 		logger.Fatal().Err(err).Msg("Failed to initialize PostgreSQL")
 	}
 
-	// 5. Initialize alert service (handler → service → repo architecture)
-	alertService = initAlertService(postgresDB)
+	// 5. Initialize alert repository (handler → service → repo architecture)
+	alertRepo := initAlertRepo(postgresDB)
+	notificationRepo, notificationService := initNotificationLog(postgresDB, alertRepo)
+	outbox = initOutbox(postgresDB, alertRepo, notificationRepo, cfg.Delivery)
 
 	// 6. Initialize K8s Monitoring & Alerting
 	logger.Info().Msg("Initializing K8s monitoring components...")
@@ -75,16 +84,49 @@ func init() {
 
 	eventBus = initEventBus(appCtx)
 	wsHub = initWebSocketHub(appCtx, eventBus)
-	initEmailDispatcher(cfg.Email, eventBus)
+	if cfg.Routing.Enabled {
+		if err := initNotificationRouter(cfg, outbox); err != nil {
+			logger.Fatal().Err(err).Msg("Failed to configure notification routing")
+		}
+	} else {
+		if err := initEmailDispatcher(cfg.Email, outbox); err != nil {
+			logger.Fatal().Err(err).Msg("Failed to configure email")
+		}
+		initSlackDispatcher(cfg.Slack, outbox)
+		initPagerDutyDispatcher(cfg.PagerDuty, outbox)
+		if err := initWebhookDispatcher(cfg.Webhooks, outbox); err != nil {
+			logger.Fatal().Err(err).Msg("Failed to configure webhooks")
+		}
+	}
 
-	alertRepo := repository.NewPostgresAlertRepo(postgresDB)
-	alertEngine = initAlertEngine(appCtx, alertRepo, eventBus)
-	podWatcher, nodeWatcher, metricsWatcher = initK8sWatchers(appCtx, k8sClient, alertEngine)
+	ruleEngine, err := initRuleEngine(cfg.AlertRules)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to load alert rules")
+	}
 
-	logger.Info().Msg("Monitoring system initialized: K8s observers + Metrics → Alerts → WebSocket + Email")
+	alertEngine = initAlertEngine(appCtx, alertRepo, eventBus, ruleEngine)
+	alertEngine.GetStateManager().UseOutbox(outbox)
+	outbox.Start(appCtx)
+	alertService = initAlertService(alertRepo, alertEngine.GetStateManager())
+	var maintenance *processor.MaintenanceSuppressor
+	maintenanceService, maintenance = initMaintenance(postgresDB, alertEngine.GetStateManager())
+	silenceService = initSilences(postgresDB, alertEngine.GetStateManager())
+	if err := initInhibitor(alertRepo, alertEngine.GetStateManager(), cfg.InhibitRules); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to configure inhibit rules")
+	}
+	podWatcher, nodeWatcher, workloadWatcher, jobWatcher, volumeWatcher, metricsWatcher = initK8sWatchers(appCtx, k8sClient, alertEngine, maintenance, cfg.AlertRules)
+	summaryWatcher = initSummaryWatcher(appCtx, k8sClient, alertEngine, wsHub, cfg.AlertRules)
+	eventWatcher, err = initEventWatcher(appCtx, k8sClient, alertEngine, wsHub, cfg.Events)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to configure event watcher")
+	}
+	// The watchers share informers, which start once all of them are registered
+	k8sClient.StartInformers()
+
+	logger.Info().Msg("Monitoring system initialized: K8s observers + Metrics → Alerts → WebSocket + Email + Slack + PagerDuty + Webhooks")
 
 	// 7. Create dependencies container
-	deps, err = initDependencies(postgresDB, k8sClient, alertService, eventBus, wsHub)
+	deps, err = initDependencies(postgresDB, k8sClient, alertService, silenceService, maintenanceService, service.NewRuleService(ruleEngine), notificationService, eventBus, wsHub)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Failed to create dependencies container")
 	}
@@ -155,10 +197,18 @@ func shutdown(srv *http.Server) {
 	// Stop all monitoring components in reverse order
 	logger.Info().Msg("Stopping monitoring components...")
 	metricsWatcher.Stop()
+	summaryWatcher.Stop()
 	podWatcher.Stop()
 	nodeWatcher.Stop()
+	workloadWatcher.Stop()
+	jobWatcher.Stop()
+	volumeWatcher.Stop()
+	if eventWatcher != nil {
+		eventWatcher.Stop()
+	}
 	alertEngine.Stop()
 	eventBus.Stop()
+	outbox.Stop()
 	k8sClient.Stop()
 	logger.Info().Msg("All monitoring components stopped")
 
@@ -1,13 +1,27 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
 	"github.com/monitoring-engine/monitoring-tool/internal/service"
 )
 
+// AlertActionRequest is the body of ack, unack and resolve requests
+type AlertActionRequest struct {
+	Actor   string `json:"actor" binding:"required"`
+	Comment string `json:"comment"`
+}
+
 // AlertHandler handles alert HTTP requests
 type AlertHandler struct {
 	service service.AlertService
@@ -40,6 +54,164 @@ func (h *AlertHandler) GetRecentAlerts(c *gin.Context) {
 	})
 }
 
+// ListAlerts handles GET /api/alerts. Filters: status, severity and source (comma-separated or
+// repeated), label=key=value (repeated), since and until (RFC3339, on triggered_at) and q (message
+// search). Ordering: sort (triggered_at or last_seen_at) and order (desc or asc). Pagination: limit
+// and cursor, the next_cursor of the previous page.
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	query, err := parseAlertQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.QueryAlerts(c.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"alerts": page.Alerts,
+		"count":  len(page.Alerts),
+	}
+	if page.NextCursor != "" {
+		response["next_cursor"] = page.NextCursor
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAlert handles GET /api/alerts/:id
+func (h *AlertHandler) GetAlert(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert id"})
+		return
+	}
+
+	alert, err := h.service.GetAlert(c.Request.Context(), id)
+	if err != nil {
+		writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
+
+// parseAlertQuery builds the alert query from the request parameters
+func parseAlertQuery(c *gin.Context) (*repository.AlertQuery, error) {
+	query := &repository.AlertQuery{
+		Severities: listParam(c, "severity"),
+		Sources:    listParam(c, "source"),
+		Search:     strings.TrimSpace(c.Query("q")),
+		Cursor:     c.Query("cursor"),
+	}
+
+	for _, status := range listParam(c, "status") {
+		switch alertStatus := models.AlertStatus(status); alertStatus {
+		case models.AlertStatusFiring, models.AlertStatusAcknowledged, models.AlertStatusResolved:
+			query.Statuses = append(query.Statuses, alertStatus)
+		default:
+			return nil, fmt.Errorf("invalid status %q", status)
+		}
+	}
+
+	for _, label := range c.QueryArray("label") {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", label)
+		}
+		if query.Labels == nil {
+			query.Labels = make(map[string]string)
+		}
+		query.Labels[key] = value
+	}
+
+	for param, target := range map[string]**time.Time{"since": &query.TriggeredAfter, "until": &query.TriggeredBefore} {
+		if value := c.Query(param); value != "" {
+			at, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s, expected RFC3339", param)
+			}
+			*target = &at
+		}
+	}
+
+	switch sortBy := c.DefaultQuery("sort", repository.AlertSortTriggeredAt); sortBy {
+	case repository.AlertSortTriggeredAt, repository.AlertSortLastSeenAt:
+		query.SortBy = sortBy
+	default:
+		return nil, fmt.Errorf("invalid sort %q", sortBy)
+	}
+	switch order := c.DefaultQuery("order", "desc"); order {
+	case "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return nil, fmt.Errorf("invalid order %q", order)
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	query.Limit = limit
+	return query, nil
+}
+
+// listParam returns the values of a repeated or comma-separated query parameter
+func listParam(c *gin.Context, name string) []string {
+	var values []string
+	for _, param := range c.QueryArray(name) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// GetAlertStats handles GET /api/alerts/stats?from=&to=&bucket=hour|day&group_by=severity
+func (h *AlertHandler) GetAlertStats(c *gin.Context) {
+	query := &repository.AlertStatsQuery{
+		Bucket:  c.DefaultQuery("bucket", repository.StatsBucketHour),
+		GroupBy: c.Query("group_by"),
+	}
+	for param, target := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		if value := c.Query(param); value != "" {
+			at, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid %s, expected RFC3339", param)})
+				return
+			}
+			*target = at
+		}
+	}
+
+	buckets, err := h.service.GetAlertStats(c.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidStatsQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":     query.From,
+		"to":       query.To,
+		"bucket":   query.Bucket,
+		"group_by": query.GroupBy,
+		"buckets":  buckets,
+	})
+}
+
 // GetAlertsCount handles GET /api/alerts/count
 func (h *AlertHandler) GetAlertsCount(c *gin.Context) {
 	count, err := h.service.GetTotalAlertsCount(c.Request.Context())
@@ -53,9 +225,9 @@ func (h *AlertHandler) GetAlertsCount(c *gin.Context) {
 	})
 }
 
-// GetFiringAlertsCount handles GET /api/alerts/active/count
-func (h *AlertHandler) GetFiringAlertsCount(c *gin.Context) {
-	count, err := h.service.GetFiringAlertsCount(c.Request.Context())
+// GetActiveAlertsCount handles GET /api/alerts/active/count
+func (h *AlertHandler) GetActiveAlertsCount(c *gin.Context) {
+	count, err := h.service.GetActiveAlertsCount(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -76,3 +248,75 @@ func (h *AlertHandler) GetSeverityCounts(c *gin.Context) {
 
 	c.JSON(http.StatusOK, counts)
 }
+
+// AcknowledgeAlert handles POST /api/alerts/:id/ack
+func (h *AlertHandler) AcknowledgeAlert(c *gin.Context) {
+	h.handleAlertAction(c, h.service.AcknowledgeAlert)
+}
+
+// UnacknowledgeAlert handles POST /api/alerts/:id/unack
+func (h *AlertHandler) UnacknowledgeAlert(c *gin.Context) {
+	h.handleAlertAction(c, h.service.UnacknowledgeAlert)
+}
+
+// ResolveAlert handles POST /api/alerts/:id/resolve
+func (h *AlertHandler) ResolveAlert(c *gin.Context) {
+	h.handleAlertAction(c, h.service.ResolveAlert)
+}
+
+// GetAlertActions handles GET /api/alerts/:id/actions
+func (h *AlertHandler) GetAlertActions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert id"})
+		return
+	}
+
+	actions, err := h.service.GetAlertActions(c.Request.Context(), id)
+	if err != nil {
+		writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"actions": actions,
+		"count":   len(actions),
+	})
+}
+
+type alertActionFunc func(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error)
+
+// handleAlertAction parses the alert ID and request body and applies an operator action
+func (h *AlertHandler) handleAlertAction(c *gin.Context, action alertActionFunc) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert id"})
+		return
+	}
+
+	var req AlertActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	alert, err := action(c.Request.Context(), id, req.Actor, req.Comment)
+	if err != nil {
+		writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
+
+// writeAlertError maps alert lifecycle errors to HTTP status codes
+func writeAlertError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrAlertNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -6,15 +6,18 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
 	"github.com/monitoring-engine/monitoring-tool/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"gorm.io/datatypes"
 )
 
@@ -36,12 +39,28 @@ func (m *MockAlertService) GetRecentAlerts(ctx context.Context, limit int) ([]*m
 	return args.Get(0).([]*models.Alert), args.Error(1)
 }
 
+func (m *MockAlertService) QueryAlerts(ctx context.Context, query *repository.AlertQuery) (*repository.AlertPage, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.AlertPage), args.Error(1)
+}
+
+func (m *MockAlertService) GetAlert(ctx context.Context, id uuid.UUID) (*models.Alert, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Alert), args.Error(1)
+}
+
 func (m *MockAlertService) GetTotalAlertsCount(ctx context.Context) (int64, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockAlertService) GetFiringAlertsCount(ctx context.Context) (int64, error) {
+func (m *MockAlertService) GetActiveAlertsCount(ctx context.Context) (int64, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
 }
@@ -54,6 +73,46 @@ func (m *MockAlertService) GetSeverityCounts(ctx context.Context) (*service.Seve
 	return args.Get(0).(*service.SeverityCounts), args.Error(1)
 }
 
+func (m *MockAlertService) GetAlertStats(ctx context.Context, query *repository.AlertStatsQuery) ([]*repository.AlertStatsBucket, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repository.AlertStatsBucket), args.Error(1)
+}
+
+func (m *MockAlertService) AcknowledgeAlert(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error) {
+	args := m.Called(ctx, id, actor, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Alert), args.Error(1)
+}
+
+func (m *MockAlertService) UnacknowledgeAlert(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error) {
+	args := m.Called(ctx, id, actor, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Alert), args.Error(1)
+}
+
+func (m *MockAlertService) ResolveAlert(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error) {
+	args := m.Called(ctx, id, actor, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Alert), args.Error(1)
+}
+
+func (m *MockAlertService) GetAlertActions(ctx context.Context, id uuid.UUID) ([]*models.AlertAction, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AlertAction), args.Error(1)
+}
+
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	return gin.Default()
@@ -164,6 +223,145 @@ func TestAlertHandler_GetRecentAlerts_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestAlertHandler_ListAlerts(t *testing.T) {
+	t.Run("should pass filters, order and pagination to the service", func(t *testing.T) {
+		mockService := new(MockAlertService)
+		alerts := []*models.Alert{{ID: uuid.New(), Status: models.AlertStatusFiring, Severity: "high", Source: "k8s_pod"}}
+		mockService.On("QueryAlerts", mock.Anything, mock.MatchedBy(func(query *repository.AlertQuery) bool {
+			return assert.ObjectsAreEqual([]models.AlertStatus{models.AlertStatusFiring, models.AlertStatusAcknowledged}, query.Statuses) &&
+				assert.ObjectsAreEqual([]string{"critical", "high"}, query.Severities) &&
+				assert.ObjectsAreEqual([]string{"k8s_pod"}, query.Sources) &&
+				assert.ObjectsAreEqual(map[string]string{"namespace": "default", "pod": "api-0"}, query.Labels) &&
+				query.TriggeredAfter != nil && query.TriggeredAfter.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) &&
+				query.TriggeredBefore == nil &&
+				query.Search == "crash" &&
+				query.SortBy == repository.AlertSortLastSeenAt && query.Ascending &&
+				query.Cursor == "abc" && query.Limit == 20
+		})).Return(&repository.AlertPage{Alerts: alerts, NextCursor: "next"}, nil)
+
+		handler := NewAlertHandler(mockService)
+		router := setupRouter()
+		router.GET("/alerts", handler.ListAlerts)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/alerts?status=firing,acknowledged&severity=critical&severity=high&source=k8s_pod"+
+			"&label=namespace=default&label=pod=api-0&since=2024-01-02T03:04:05Z&q=crash&sort=last_seen_at&order=asc&cursor=abc&limit=20", nil)
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, float64(1), response["count"])
+		assert.Equal(t, "next", response["next_cursor"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid parameters", func(t *testing.T) {
+		handler := NewAlertHandler(new(MockAlertService))
+		router := setupRouter()
+		router.GET("/alerts", handler.ListAlerts)
+
+		for _, params := range []string{"status=open", "label=namespace", "since=yesterday", "sort=severity", "order=up"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/alerts?"+params, nil)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, params)
+		}
+	})
+
+	t.Run("should return 400 for an invalid cursor", func(t *testing.T) {
+		mockService := new(MockAlertService)
+		mockService.On("QueryAlerts", mock.Anything, mock.Anything).Return(nil, repository.ErrInvalidCursor)
+
+		handler := NewAlertHandler(mockService)
+		router := setupRouter()
+		router.GET("/alerts", handler.ListAlerts)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/alerts?cursor=bogus", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestAlertHandler_GetAlert(t *testing.T) {
+	id := uuid.New()
+	mockService := new(MockAlertService)
+	mockService.On("GetAlert", mock.Anything, id).Return(&models.Alert{ID: id, Status: models.AlertStatusFiring, Severity: "critical"}, nil)
+	mockService.On("GetAlert", mock.Anything, mock.Anything).Return(nil, repository.ErrAlertNotFound)
+
+	handler := NewAlertHandler(mockService)
+	router := setupRouter()
+	router.GET("/alerts/:id", handler.GetAlert)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/alerts/"+id.String(), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, id.String(), response["id"])
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/alerts/"+uuid.New().String(), nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/alerts/not-a-uuid", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAlertHandler_GetAlertStats(t *testing.T) {
+	from := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+
+	t.Run("should return the buckets of the range", func(t *testing.T) {
+		mockService := new(MockAlertService)
+		mockService.On("GetAlertStats", mock.Anything, &repository.AlertStatsQuery{From: from, To: to, Bucket: "day", GroupBy: "severity"}).
+			Return([]*repository.AlertStatsBucket{{Start: from, Group: "critical", Count: 3}}, nil)
+
+		handler := NewAlertHandler(mockService)
+		router := setupRouter()
+		router.GET("/alerts/stats", handler.GetAlertStats)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/alerts/stats?from=2024-03-10T00:00:00Z&to=2024-03-12T00:00:00Z&bucket=day&group_by=severity", nil)
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Bucket  string                         `json:"bucket"`
+			GroupBy string                         `json:"group_by"`
+			Buckets []*repository.AlertStatsBucket `json:"buckets"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "day", response.Bucket)
+		assert.Equal(t, "severity", response.GroupBy)
+		require.Len(t, response.Buckets, 1)
+		assert.Equal(t, int64(3), response.Buckets[0].Count)
+		assert.Equal(t, "critical", response.Buckets[0].Group)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for invalid times and queries", func(t *testing.T) {
+		mockService := new(MockAlertService)
+		mockService.On("GetAlertStats", mock.Anything, mock.Anything).Return(nil, repository.ErrInvalidStatsQuery)
+
+		handler := NewAlertHandler(mockService)
+		router := setupRouter()
+		router.GET("/alerts/stats", handler.GetAlertStats)
+
+		for _, params := range []string{"from=yesterday", "bucket=week"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/alerts/stats?"+params, nil)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, params)
+		}
+	})
+}
+
 func TestAlertHandler_GetAlertsCount_Success(t *testing.T) {
 	mockService := new(MockAlertService)
 
@@ -203,6 +401,97 @@ func TestAlertHandler_GetAlertsCount_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestAlertHandler_AcknowledgeAlert_Success(t *testing.T) {
+	mockService := new(MockAlertService)
+	id := uuid.New()
+	acked := &models.Alert{ID: id, Status: models.AlertStatusAcknowledged, AcknowledgedBy: "alice", Comment: "looking"}
+	mockService.On("AcknowledgeAlert", mock.Anything, id, "alice", "looking").Return(acked, nil)
+
+	handler := NewAlertHandler(mockService)
+	router := setupRouter()
+	router.POST("/alerts/:id/ack", handler.AcknowledgeAlert)
+
+	w := httptest.NewRecorder()
+	body := strings.NewReader(`{"actor":"alice","comment":"looking"}`)
+	req, _ := http.NewRequest("POST", "/alerts/"+id.String()+"/ack", body)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "acknowledged", response["status"])
+	assert.Equal(t, "alice", response["acknowledged_by"])
+	mockService.AssertExpectations(t)
+}
+
+func TestAlertHandler_AlertActions_Errors(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		serviceErr error
+		expectCode int
+	}{
+		{"invalid id", "/alerts/not-a-uuid/resolve", `{"actor":"alice"}`, nil, http.StatusBadRequest},
+		{"missing actor", "/alerts/" + id.String() + "/resolve", `{"comment":"x"}`, nil, http.StatusBadRequest},
+		{"not found", "/alerts/" + id.String() + "/resolve", `{"actor":"alice"}`, repository.ErrAlertNotFound, http.StatusNotFound},
+		{"invalid transition", "/alerts/" + id.String() + "/resolve", `{"actor":"alice"}`, models.ErrInvalidTransition, http.StatusConflict},
+		{"service error", "/alerts/" + id.String() + "/resolve", `{"actor":"alice"}`, errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAlertService)
+			if tt.serviceErr != nil {
+				mockService.On("ResolveAlert", mock.Anything, id, "alice", "").Return(nil, tt.serviceErr)
+			}
+
+			handler := NewAlertHandler(mockService)
+			router := setupRouter()
+			router.POST("/alerts/:id/resolve", handler.ResolveAlert)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAlertHandler_GetAlertActions_Success(t *testing.T) {
+	mockService := new(MockAlertService)
+	id := uuid.New()
+	actions := []*models.AlertAction{
+		models.NewAlertAction(id, models.AlertActionAcknowledge, "alice", "looking"),
+		models.NewAlertAction(id, models.AlertActionUnacknowledge, "bob", ""),
+	}
+	mockService.On("GetAlertActions", mock.Anything, id).Return(actions, nil)
+
+	handler := NewAlertHandler(mockService)
+	router := setupRouter()
+	router.GET("/alerts/:id/actions", handler.GetAlertActions)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/alerts/"+id.String()+"/actions", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), response["count"])
+	mockService.AssertExpectations(t)
+}
+
 func TestNewAlertHandler(t *testing.T) {
 	mockService := new(MockAlertService)
 	handler := NewAlertHandler(mockService)
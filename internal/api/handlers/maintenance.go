@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+	"github.com/monitoring-engine/monitoring-tool/internal/service"
+	"gorm.io/datatypes"
+)
+
+// MaintenanceWindowRequest is the body of create and update maintenance window requests
+type MaintenanceWindowRequest struct {
+	Name            string           `json:"name" binding:"required"`
+	Nodes           []string         `json:"nodes"`
+	Namespaces      []string         `json:"namespaces"`
+	Matchers        []models.Matcher `json:"matchers"`
+	StartsAt        *time.Time       `json:"starts_at"` // defaults to now
+	EndsAt          *time.Time       `json:"ends_at"`
+	Schedule        string           `json:"schedule"`
+	DurationMinutes int              `json:"duration_minutes"`
+	Timezone        string           `json:"timezone"`
+	CreatedBy       string           `json:"created_by" binding:"required"`
+	Comment         string           `json:"comment"`
+}
+
+// toWindow builds a maintenance window from the request, starting it now if no start time is given
+func (r *MaintenanceWindowRequest) toWindow() *models.MaintenanceWindow {
+	startsAt := time.Now()
+	if r.StartsAt != nil {
+		startsAt = *r.StartsAt
+	}
+	return &models.MaintenanceWindow{
+		ID:              uuid.New(),
+		Name:            r.Name,
+		Nodes:           datatypes.NewJSONSlice(r.Nodes),
+		Namespaces:      datatypes.NewJSONSlice(r.Namespaces),
+		Matchers:        datatypes.NewJSONSlice(r.Matchers),
+		StartsAt:        startsAt,
+		EndsAt:          r.EndsAt,
+		Schedule:        r.Schedule,
+		DurationMinutes: r.DurationMinutes,
+		Timezone:        r.Timezone,
+		CreatedBy:       r.CreatedBy,
+		Comment:         r.Comment,
+	}
+}
+
+// MaintenanceHandler handles maintenance window HTTP requests
+type MaintenanceHandler struct {
+	service service.MaintenanceService
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(service service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		service: service,
+	}
+}
+
+// ListWindows handles GET /api/maintenance
+func (h *MaintenanceHandler) ListWindows(c *gin.Context) {
+	activeOnly := c.Query("active") == "true"
+
+	windows, err := h.service.ListWindows(c.Request.Context(), activeOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"windows": windows,
+		"count":   len(windows),
+	})
+}
+
+// GetWindow handles GET /api/maintenance/:id
+func (h *MaintenanceHandler) GetWindow(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid maintenance window id"})
+		return
+	}
+
+	window, err := h.service.GetWindow(c.Request.Context(), id)
+	if err != nil {
+		writeMaintenanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, window)
+}
+
+// CreateWindow handles POST /api/maintenance
+func (h *MaintenanceHandler) CreateWindow(c *gin.Context) {
+	var req MaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	window := req.toWindow()
+	if err := h.service.CreateWindow(c.Request.Context(), window); err != nil {
+		writeMaintenanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, window)
+}
+
+// UpdateWindow handles PUT /api/maintenance/:id
+func (h *MaintenanceHandler) UpdateWindow(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid maintenance window id"})
+		return
+	}
+
+	var req MaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	window := req.toWindow()
+	window.ID = id
+	if err := h.service.UpdateWindow(c.Request.Context(), window); err != nil {
+		writeMaintenanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, window)
+}
+
+// DeleteWindow handles DELETE /api/maintenance/:id
+func (h *MaintenanceHandler) DeleteWindow(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid maintenance window id"})
+		return
+	}
+
+	if err := h.service.DeleteWindow(c.Request.Context(), id); err != nil {
+		writeMaintenanceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeMaintenanceError maps maintenance window errors to HTTP status codes
+func writeMaintenanceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrMaintenanceWindowNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrInvalidMaintenanceWindow):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
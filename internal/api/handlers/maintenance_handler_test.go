@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+	"github.com/monitoring-engine/monitoring-tool/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMaintenanceRouter() http.Handler {
+	handler := NewMaintenanceHandler(service.NewMaintenanceService(repository.NewInMemoryMaintenanceRepo()))
+	router := setupRouter()
+	router.GET("/maintenance", handler.ListWindows)
+	router.POST("/maintenance", handler.CreateWindow)
+	router.GET("/maintenance/:id", handler.GetWindow)
+	router.PUT("/maintenance/:id", handler.UpdateWindow)
+	router.DELETE("/maintenance/:id", handler.DeleteWindow)
+	return router
+}
+
+func TestMaintenanceHandler_Lifecycle(t *testing.T) {
+	router := setupMaintenanceRouter()
+	endsAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	w := doJSONRequest(router, "POST", "/maintenance",
+		`{"name":"node-1 reboot","nodes":["node-1"],"ends_at":"`+endsAt+`","created_by":"alice"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	id := created["id"].(string)
+
+	w = doJSONRequest(router, "POST", "/maintenance",
+		`{"name":"weekly","namespaces":["batch"],"schedule":"0 3 * * 6","duration_minutes":60,"created_by":"alice"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = doJSONRequest(router, "GET", "/maintenance?active=true", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var active map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &active))
+	assert.GreaterOrEqual(t, active["count"], float64(1))
+
+	w = doJSONRequest(router, "PUT", "/maintenance/"+id,
+		`{"name":"node-1 reboot","nodes":["node-1","node-2"],"ends_at":"`+endsAt+`","created_by":"alice"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doJSONRequest(router, "DELETE", "/maintenance/"+id, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = doJSONRequest(router, "GET", "/maintenance/"+id, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMaintenanceHandler_Errors(t *testing.T) {
+	router := setupMaintenanceRouter()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		expectCode int
+	}{
+		{"missing name", "POST", "/maintenance", `{"nodes":["node-1"],"created_by":"alice"}`, http.StatusBadRequest},
+		{"no scope", "POST", "/maintenance", `{"name":"x","schedule":"0 3 * * *","duration_minutes":60,"created_by":"alice"}`, http.StatusBadRequest},
+		{"invalid schedule", "POST", "/maintenance", `{"name":"x","nodes":["node-1"],"schedule":"every sunday","duration_minutes":60,"created_by":"alice"}`, http.StatusBadRequest},
+		{"invalid id", "GET", "/maintenance/not-a-uuid", "", http.StatusBadRequest},
+		{"unknown window", "DELETE", "/maintenance/00000000-0000-0000-0000-000000000001", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doJSONRequest(router, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.expectCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+	"github.com/monitoring-engine/monitoring-tool/internal/service"
+)
+
+// NotificationHandler handles notification log HTTP requests
+type NotificationHandler struct {
+	service service.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(service service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		service: service,
+	}
+}
+
+// GetAlertNotifications handles GET /api/alerts/:id/notifications
+func (h *NotificationHandler) GetAlertNotifications(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert id"})
+		return
+	}
+
+	notifications, err := h.service.GetAlertNotifications(c.Request.Context(), id)
+	if err != nil {
+		writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"count":         len(notifications),
+	})
+}
+
+// ListNotifications handles GET /api/notifications?state=dead
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	state := models.NotificationState(c.Query("state"))
+	if state != "" && !state.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification state"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+
+	notifications, err := h.service.ListNotifications(c.Request.Context(), state, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"count":         len(notifications),
+	})
+}
+
+// RetryNotification handles POST /api/notifications/:id/retry
+func (h *NotificationHandler) RetryNotification(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification id"})
+		return
+	}
+
+	notification, err := h.service.RetryNotification(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotificationNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrInvalidTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": "only failed or dead notifications can be retried"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+	"github.com/monitoring-engine/monitoring-tool/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationHandler_GetAlertNotifications(t *testing.T) {
+	ctx := context.Background()
+	alertRepo := repository.NewInMemoryAlertRepo()
+	notificationRepo := repository.NewInMemoryNotificationRepo()
+
+	alert := models.NewAlert("critical", "Pod failed", "k8s_pod", 1, map[string]string{"namespace": "default", "pod": "web-0"})
+	require.NoError(t, alertRepo.Create(ctx, alert))
+
+	delivered := models.NewNotification(alert.ID, models.ChannelTypeEmail, "", "firing")
+	delivered.RecordAttempt(nil)
+	require.NoError(t, notificationRepo.Create(ctx, delivered))
+	failed := models.NewNotification(alert.ID, models.ChannelTypeSlack, "team-a", "firing")
+	failed.RecordAttempt(errors.New("slack returned 500"))
+	require.NoError(t, notificationRepo.Create(ctx, failed))
+
+	handler := NewNotificationHandler(service.NewNotificationService(notificationRepo, alertRepo))
+	router := setupRouter()
+	router.GET("/alerts/:id/notifications", handler.GetAlertNotifications)
+
+	t.Run("should list the notifications of an alert", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/alerts/"+alert.ID.String()+"/notifications", nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Notifications []models.Notification `json:"notifications"`
+			Count         int                   `json:"count"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Count)
+		require.Len(t, response.Notifications, 2)
+		assert.Equal(t, models.ChannelTypeEmail, response.Notifications[0].Channel)
+		assert.Equal(t, models.NotificationStateSuccess, response.Notifications[0].State)
+		assert.Equal(t, "team-a", response.Notifications[1].Receiver)
+		assert.Equal(t, models.NotificationStateFailed, response.Notifications[1].State)
+		assert.Equal(t, "slack returned 500", response.Notifications[1].Error)
+	})
+
+	t.Run("should return an empty array for an alert without notifications", func(t *testing.T) {
+		quiet := models.NewAlert("low", "Pod restarted", "k8s_pod", 1, map[string]string{"namespace": "default", "pod": "web-1"})
+		require.NoError(t, alertRepo.Create(ctx, quiet))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/alerts/"+quiet.ID.String()+"/notifications", nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"notifications":[],"count":0}`, w.Body.String())
+	})
+
+	t.Run("should return 404 for an unknown alert", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/alerts/"+uuid.New().String()+"/notifications", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("should return 400 for an invalid id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/alerts/not-a-uuid/notifications", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestNotificationHandler_DeadLetters(t *testing.T) {
+	ctx := context.Background()
+	alertRepo := repository.NewInMemoryAlertRepo()
+	notificationRepo := repository.NewInMemoryNotificationRepo()
+
+	delivered := models.NewNotification(uuid.New(), models.ChannelTypeEmail, "", "firing")
+	delivered.RecordAttempt(nil)
+	require.NoError(t, notificationRepo.Create(ctx, delivered))
+	dead := models.NewNotification(uuid.New(), models.ChannelTypeWebhook, "", "firing")
+	dead.RecordAttempt(errors.New("connection refused"))
+	dead.GiveUp()
+	require.NoError(t, notificationRepo.Create(ctx, dead))
+
+	handler := NewNotificationHandler(service.NewNotificationService(notificationRepo, alertRepo))
+	router := setupRouter()
+	router.GET("/notifications", handler.ListNotifications)
+	router.POST("/notifications/:id/retry", handler.RetryNotification)
+
+	t.Run("should list dead notifications", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/notifications?state=dead", nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Notifications []models.Notification `json:"notifications"`
+			Count         int                   `json:"count"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.Count)
+		require.Len(t, response.Notifications, 1)
+		assert.Equal(t, dead.ID, response.Notifications[0].ID)
+		assert.Equal(t, "connection refused", response.Notifications[0].Error)
+	})
+
+	t.Run("should return an empty array when nothing matches", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/notifications?state=skipped", nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"notifications":[],"count":0}`, w.Body.String())
+	})
+
+	t.Run("should return 400 for an unknown state", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/notifications?state=lost", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should queue a dead notification again", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/notifications/"+dead.ID.String()+"/retry", nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		stored, err := notificationRepo.GetByID(ctx, dead.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.NotificationStatePending, stored.State)
+		assert.Equal(t, 0, stored.Attempts)
+		assert.True(t, stored.IsQueued())
+	})
+
+	t.Run("should return 409 for a delivered notification", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/notifications/"+delivered.ID.String()+"/retry", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should return 404 for an unknown notification", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/notifications/"+uuid.New().String()+"/retry", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
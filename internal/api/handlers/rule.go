@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/monitoring-engine/monitoring-tool/internal/service"
+)
+
+// RuleHandler handles alert rule HTTP requests
+type RuleHandler struct {
+	service service.RuleService
+}
+
+// NewRuleHandler creates a new rule handler
+func NewRuleHandler(service service.RuleService) *RuleHandler {
+	return &RuleHandler{
+		service: service,
+	}
+}
+
+// ListRules handles GET /api/rules
+func (h *RuleHandler) ListRules(c *gin.Context) {
+	rules := h.service.ListRules()
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules": rules,
+		"count": len(rules),
+	})
+}
+
+// ListPendingAlerts handles GET /api/alerts/pending
+func (h *RuleHandler) ListPendingAlerts(c *gin.Context) {
+	pending := h.service.ListPendingAlerts()
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts": pending,
+		"count":  len(pending),
+	})
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+	"github.com/monitoring-engine/monitoring-tool/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleHandler_ListPendingAlerts(t *testing.T) {
+	engine, err := processor.NewRuleEngine([]processor.Rule{{
+		Name:     "pod_cpu_high",
+		Object:   processor.ObjectPod,
+		Signal:   processor.SignalCPUPercent,
+		Operator: processor.OpGreater,
+		Value:    "80",
+		For:      3 * time.Minute,
+		Severity: "high",
+		Message:  "Pod {{.Namespace}}/{{.Pod}} CPU usage is HIGH",
+	}})
+	require.NoError(t, err)
+
+	handler := NewRuleHandler(service.NewRuleService(engine))
+	router := setupRouter()
+	router.GET("/rules", handler.ListRules)
+	router.GET("/alerts/pending", handler.ListPendingAlerts)
+
+	w := doJSONRequest(router, "GET", "/alerts/pending", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"alerts":[],"count":0}`, w.Body.String())
+
+	alerts := engine.Evaluate(&processor.Observation{
+		Source:    "k8s_pod_metrics",
+		Namespace: "production",
+		Pod:       "api-0",
+		Samples:   []processor.Sample{{Signal: processor.SignalCPUPercent, Value: 95}},
+	})
+	require.Empty(t, alerts)
+
+	w = doJSONRequest(router, "GET", "/alerts/pending", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Alerts []processor.PendingAlert `json:"alerts"`
+		Count  int                      `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, 1, response.Count)
+	assert.Equal(t, "pod_cpu_high", response.Alerts[0].Rule)
+	assert.Equal(t, "api-0", response.Alerts[0].Labels["pod"])
+	assert.Equal(t, 3*time.Minute, response.Alerts[0].FiresAt.Sub(response.Alerts[0].ActiveSince))
+
+	w = doJSONRequest(router, "GET", "/rules", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"pod_cpu_high"`)
+}
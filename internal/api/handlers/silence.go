@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+	"github.com/monitoring-engine/monitoring-tool/internal/service"
+)
+
+// SilenceRequest is the body of create and update silence requests
+type SilenceRequest struct {
+	Matchers  []models.Matcher `json:"matchers" binding:"required"`
+	StartsAt  *time.Time       `json:"starts_at"` // defaults to now
+	EndsAt    time.Time        `json:"ends_at" binding:"required"`
+	CreatedBy string           `json:"created_by" binding:"required"`
+	Comment   string           `json:"comment"`
+}
+
+// toSilence builds a silence from the request, starting it now if no start time is given
+func (r *SilenceRequest) toSilence() *models.Silence {
+	startsAt := time.Now()
+	if r.StartsAt != nil {
+		startsAt = *r.StartsAt
+	}
+	return models.NewSilence(r.Matchers, startsAt, r.EndsAt, r.CreatedBy, r.Comment)
+}
+
+// SilenceHandler handles silence HTTP requests
+type SilenceHandler struct {
+	service service.SilenceService
+}
+
+// NewSilenceHandler creates a new silence handler
+func NewSilenceHandler(service service.SilenceService) *SilenceHandler {
+	return &SilenceHandler{
+		service: service,
+	}
+}
+
+// ListSilences handles GET /api/silences
+func (h *SilenceHandler) ListSilences(c *gin.Context) {
+	silences, err := h.service.ListSilences(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"silences": silences,
+		"count":    len(silences),
+	})
+}
+
+// GetSilence handles GET /api/silences/:id
+func (h *SilenceHandler) GetSilence(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid silence id"})
+		return
+	}
+
+	silence, err := h.service.GetSilence(c.Request.Context(), id)
+	if err != nil {
+		writeSilenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, silence)
+}
+
+// CreateSilence handles POST /api/silences
+func (h *SilenceHandler) CreateSilence(c *gin.Context) {
+	var req SilenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	silence := req.toSilence()
+	if err := h.service.CreateSilence(c.Request.Context(), silence); err != nil {
+		writeSilenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, silence)
+}
+
+// UpdateSilence handles PUT /api/silences/:id
+func (h *SilenceHandler) UpdateSilence(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid silence id"})
+		return
+	}
+
+	var req SilenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	silence := req.toSilence()
+	silence.ID = id
+	if err := h.service.UpdateSilence(c.Request.Context(), silence); err != nil {
+		writeSilenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, silence)
+}
+
+// DeleteSilence handles DELETE /api/silences/:id
+func (h *SilenceHandler) DeleteSilence(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid silence id"})
+		return
+	}
+
+	if err := h.service.DeleteSilence(c.Request.Context(), id); err != nil {
+		writeSilenceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeSilenceError maps silence errors to HTTP status codes
+func writeSilenceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrSilenceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrInvalidSilence):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+	"github.com/monitoring-engine/monitoring-tool/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSilenceRouter() http.Handler {
+	handler := NewSilenceHandler(service.NewSilenceService(repository.NewInMemorySilenceRepo()))
+	router := setupRouter()
+	router.GET("/silences", handler.ListSilences)
+	router.POST("/silences", handler.CreateSilence)
+	router.GET("/silences/:id", handler.GetSilence)
+	router.PUT("/silences/:id", handler.UpdateSilence)
+	router.DELETE("/silences/:id", handler.DeleteSilence)
+	return router
+}
+
+func doJSONRequest(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSilenceHandler_Lifecycle(t *testing.T) {
+	router := setupSilenceRouter()
+	endsAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	w := doJSONRequest(router, "POST", "/silences",
+		`{"matchers":[{"name":"namespace","operator":"=~","value":"stag.*"}],"ends_at":"`+endsAt+`","created_by":"alice","comment":"deploy"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	id := created["id"].(string)
+	assert.Equal(t, "alice", created["created_by"])
+
+	w = doJSONRequest(router, "GET", "/silences/"+id, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doJSONRequest(router, "PUT", "/silences/"+id,
+		`{"matchers":[{"name":"namespace","operator":"=","value":"staging"}],"ends_at":"`+endsAt+`","created_by":"alice","comment":"extended"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doJSONRequest(router, "GET", "/silences", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, float64(1), list["count"])
+
+	w = doJSONRequest(router, "DELETE", "/silences/"+id, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = doJSONRequest(router, "GET", "/silences/"+id, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSilenceHandler_Errors(t *testing.T) {
+	router := setupSilenceRouter()
+	endsAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		expectCode int
+	}{
+		{"missing created_by", "POST", "/silences", `{"matchers":[{"name":"pod","operator":"=","value":"x"}],"ends_at":"` + endsAt + `"}`, http.StatusBadRequest},
+		{"invalid regex", "POST", "/silences", `{"matchers":[{"name":"pod","operator":"=~","value":"("}],"ends_at":"` + endsAt + `","created_by":"alice"}`, http.StatusBadRequest},
+		{"no matchers", "POST", "/silences", `{"matchers":[],"ends_at":"` + endsAt + `","created_by":"alice"}`, http.StatusBadRequest},
+		{"ends before start", "POST", "/silences", `{"matchers":[{"name":"pod","operator":"=","value":"x"}],"ends_at":"` + past + `","created_by":"alice"}`, http.StatusBadRequest},
+		{"invalid id", "GET", "/silences/not-a-uuid", "", http.StatusBadRequest},
+		{"unknown silence", "DELETE", "/silences/00000000-0000-0000-0000-000000000001", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doJSONRequest(router, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.expectCode, w.Code)
+		})
+	}
+}
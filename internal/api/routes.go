@@ -27,10 +27,57 @@ func RegisterRoutes(deps *app.Dependencies, router *gin.Engine) {
 	{
 		alertGroup := apiV1.Group("/alerts")
 		{
+			alertGroup.GET("", alertHandler.ListAlerts)
 			alertGroup.GET("/recent", alertHandler.GetRecentAlerts)
 			alertGroup.GET("/count", alertHandler.GetAlertsCount)
-			alertGroup.GET("/active/count", alertHandler.GetFiringAlertsCount)
+			alertGroup.GET("/active/count", alertHandler.GetActiveAlertsCount)
 			alertGroup.GET("/severity/counts", alertHandler.GetSeverityCounts)
+			alertGroup.GET("/stats", alertHandler.GetAlertStats)
+			alertGroup.GET("/:id", alertHandler.GetAlert)
+			alertGroup.GET("/:id/actions", alertHandler.GetAlertActions)
+			alertGroup.POST("/:id/ack", alertHandler.AcknowledgeAlert)
+			alertGroup.POST("/:id/unack", alertHandler.UnacknowledgeAlert)
+			alertGroup.POST("/:id/resolve", alertHandler.ResolveAlert)
+		}
+
+		if deps.RuleService != nil {
+			ruleHandler := handlers.NewRuleHandler(deps.RuleService)
+			alertGroup.GET("/pending", ruleHandler.ListPendingAlerts)
+			apiV1.GET("/rules", ruleHandler.ListRules)
+		}
+
+		if deps.NotificationService != nil {
+			notificationHandler := handlers.NewNotificationHandler(deps.NotificationService)
+			alertGroup.GET("/:id/notifications", notificationHandler.GetAlertNotifications)
+			notificationGroup := apiV1.Group("/notifications")
+			{
+				notificationGroup.GET("", notificationHandler.ListNotifications)
+				notificationGroup.POST("/:id/retry", notificationHandler.RetryNotification)
+			}
+		}
+
+		if deps.SilenceService != nil {
+			silenceHandler := handlers.NewSilenceHandler(deps.SilenceService)
+			silenceGroup := apiV1.Group("/silences")
+			{
+				silenceGroup.GET("", silenceHandler.ListSilences)
+				silenceGroup.POST("", silenceHandler.CreateSilence)
+				silenceGroup.GET("/:id", silenceHandler.GetSilence)
+				silenceGroup.PUT("/:id", silenceHandler.UpdateSilence)
+				silenceGroup.DELETE("/:id", silenceHandler.DeleteSilence)
+			}
+		}
+
+		if deps.MaintenanceService != nil {
+			maintenanceHandler := handlers.NewMaintenanceHandler(deps.MaintenanceService)
+			maintenanceGroup := apiV1.Group("/maintenance")
+			{
+				maintenanceGroup.GET("", maintenanceHandler.ListWindows)
+				maintenanceGroup.POST("", maintenanceHandler.CreateWindow)
+				maintenanceGroup.GET("/:id", maintenanceHandler.GetWindow)
+				maintenanceGroup.PUT("/:id", maintenanceHandler.UpdateWindow)
+				maintenanceGroup.DELETE("/:id", maintenanceHandler.DeleteWindow)
+			}
 		}
 	}
 
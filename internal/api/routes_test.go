@@ -30,8 +30,8 @@ func setupTestDependencies(t *testing.T) *app.Dependencies {
 	db := setupTestDB(t)
 	k8sClient := &collector.K8sClient{}
 	repo := repository.NewInMemoryAlertRepo()
-	alertService := service.NewAlertService(repo)
 	eventBus := processor.NewEventBus()
+	alertService := service.NewAlertService(repo, processor.NewAlertStateManager(repo, eventBus))
 	wsHub := websocket.NewHub()
 
 	deps, err := app.NewDependencies(db, k8sClient, alertService, eventBus, wsHub)
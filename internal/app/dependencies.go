@@ -17,6 +17,12 @@ type Dependencies struct {
 	AlertService service.AlertService
 	EventBus     *processor.EventBus
 	WSHub        *websocket.Hub
+
+	// Optional feature services; their routes are only registered when set
+	SilenceService      service.SilenceService
+	MaintenanceService  service.MaintenanceService
+	RuleService         service.RuleService
+	NotificationService service.NotificationService
 }
 
 // NewDependencies creates a new dependencies container with validation
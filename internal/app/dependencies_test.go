@@ -29,10 +29,9 @@ func setupMockDependencies(t *testing.T) (*gorm.DB, *collector.K8sClient, servic
 
 	// Create real alert service with in-memory repository
 	repo := repository.NewInMemoryAlertRepo()
-	alertService := service.NewAlertService(repo)
-
 	// Create real event bus
 	eventBus := processor.NewEventBus()
+	alertService := service.NewAlertService(repo, processor.NewAlertStateManager(repo, eventBus))
 
 	// Create real WebSocket hub
 	wsHub := websocket.NewHub()
@@ -142,11 +141,11 @@ func TestDependencies_Validation(t *testing.T) {
 			hub       *websocket.Hub
 			expectErr string
 		}{
-			{"nil db", nil, &collector.K8sClient{}, service.NewAlertService(repository.NewInMemoryAlertRepo()), processor.NewEventBus(), websocket.NewHub(), "database is required"},
-			{"nil k8s", setupTestDB(t), nil, service.NewAlertService(repository.NewInMemoryAlertRepo()), processor.NewEventBus(), websocket.NewHub(), "k8s client is required"},
+			{"nil db", nil, &collector.K8sClient{}, service.NewAlertService(repository.NewInMemoryAlertRepo(), nil), processor.NewEventBus(), websocket.NewHub(), "database is required"},
+			{"nil k8s", setupTestDB(t), nil, service.NewAlertService(repository.NewInMemoryAlertRepo(), nil), processor.NewEventBus(), websocket.NewHub(), "k8s client is required"},
 			{"nil service", setupTestDB(t), &collector.K8sClient{}, nil, processor.NewEventBus(), websocket.NewHub(), "alert service is required"},
-			{"nil eventbus", setupTestDB(t), &collector.K8sClient{}, service.NewAlertService(repository.NewInMemoryAlertRepo()), nil, websocket.NewHub(), "event bus is required"},
-			{"nil hub", setupTestDB(t), &collector.K8sClient{}, service.NewAlertService(repository.NewInMemoryAlertRepo()), processor.NewEventBus(), nil, "websocket hub is required"},
+			{"nil eventbus", setupTestDB(t), &collector.K8sClient{}, service.NewAlertService(repository.NewInMemoryAlertRepo(), nil), nil, websocket.NewHub(), "event bus is required"},
+			{"nil hub", setupTestDB(t), &collector.K8sClient{}, service.NewAlertService(repository.NewInMemoryAlertRepo(), nil), processor.NewEventBus(), nil, "websocket hub is required"},
 		}
 
 		for _, tt := range tests {
@@ -2,7 +2,11 @@ package collector
 
 import (
 	"fmt"
+	"strings"
+	"time"
+	"unicode"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/monitoring-engine/monitoring-tool/internal/models"
@@ -25,6 +29,26 @@ const (
 	AlertTypeNodeDiskPressure    AlertType = "node_disk_pressure"
 	AlertTypeNodePIDPressure     AlertType = "node_pid_pressure"
 
+	// Workload alerts
+	AlertTypeDeploymentRolloutStuck AlertType = "deployment_rollout_stuck"
+	AlertTypeDeploymentUnavailable  AlertType = "deployment_replicas_unavailable"
+	AlertTypeStatefulSetNotReady    AlertType = "statefulset_replicas_not_ready"
+	AlertTypeDaemonSetMisscheduled  AlertType = "daemonset_misscheduled"
+	AlertTypeDaemonSetUnavailable   AlertType = "daemonset_pods_unavailable"
+	AlertTypeWorkloadGenerationLag  AlertType = "workload_generation_lag"
+
+	// Batch alerts
+	AlertTypeJobFailed             AlertType = "job_failed"
+	AlertTypeJobRunningTooLong     AlertType = "job_running_too_long"
+	AlertTypeCronJobMissedSchedule AlertType = "cronjob_missed_schedule"
+	AlertTypeCronJobSuspended      AlertType = "cronjob_suspended"
+
+	// Storage alerts
+	AlertTypePVCPending       AlertType = "pvc_pending"
+	AlertTypePVCLost          AlertType = "pvc_lost"
+	AlertTypeVolumeUsageHigh  AlertType = "volume_usage_high"
+	AlertTypeVolumeInodesHigh AlertType = "volume_inodes_high"
+
 	// Metric-based alerts
 	AlertTypePodCPUHigh     AlertType = "pod_cpu_high"
 	AlertTypePodMemoryHigh  AlertType = "pod_memory_high"
@@ -32,238 +56,195 @@ const (
 	AlertTypeNodeMemoryHigh AlertType = "node_memory_high"
 )
 
-// BuildPodAlert creates a detailed alert for pod issues
-func BuildPodAlert(pod *corev1.Pod, alertType AlertType, value float64) *models.Alert {
+// BuildJobAlert creates a detailed alert for job issues. Jobs created by a CronJob are
+// labelled with it.
+func BuildJobAlert(job *batchv1.Job, alertType AlertType, value float64) *models.Alert {
 	var severity string
 	var message string
 
 	labels := map[string]string{
-		"namespace":  pod.Namespace,
-		"pod":        pod.Name,
+		"namespace":  job.Namespace,
+		"job":        job.Name,
 		"alert_type": string(alertType),
 	}
+	if cronJob := getJobCronJob(job); cronJob != "" {
+		labels["cronjob"] = cronJob
+	}
 
 	switch alertType {
-	case AlertTypePodFailed:
-		severity = SeverityCritical
-		message = fmt.Sprintf("Pod %s/%s has FAILED - Phase: %s, Reason: %s",
-			pod.Namespace, pod.Name, pod.Status.Phase, pod.Status.Reason)
-		if pod.Status.Message != "" {
-			message += fmt.Sprintf(", Message: %s", pod.Status.Message)
-		}
-
-	case AlertTypePodOOMKilled:
-		severity = SeverityCritical
-		containerName := getOOMKilledContainer(pod)
-		message = fmt.Sprintf("Pod %s/%s container '%s' was OOM KILLED - Out of memory",
-			pod.Namespace, pod.Name, containerName)
-		labels["container"] = containerName
-
-	case AlertTypePodCrashLoop:
+	case AlertTypeJobFailed:
 		severity = SeverityHigh
-		containerName, reason := getCrashLoopContainer(pod)
-		message = fmt.Sprintf("Pod %s/%s container '%s' is in CRASH LOOP BACKOFF - Reason: %s",
-			pod.Namespace, pod.Name, containerName, reason)
-		labels["container"] = containerName
+		reason, detail := getJobFailure(job)
+		message = fmt.Sprintf("Job %s/%s has FAILED - Reason: %s, Failed pods: %d (backoffLimit: %d)",
+			job.Namespace, job.Name, reason, job.Status.Failed, jobBackoffLimit(job))
+		if detail != "" {
+			message += fmt.Sprintf(", Message: %s", detail)
+		}
 		labels["reason"] = reason
 
-	case AlertTypePodRestartThreshold:
-		severity = SeverityHigh
-		restartCount := int32(value)
-		containerName := getHighestRestartContainer(pod)
-		message = fmt.Sprintf("Pod %s/%s has EXCESSIVE RESTARTS - Total restarts: %d, Container: %s",
-			pod.Namespace, pod.Name, restartCount, containerName)
-		labels["container"] = containerName
-
-	case AlertTypePodImagePullError:
-		severity = SeverityHigh
-		containerName, imageError := getImagePullError(pod)
-		message = fmt.Sprintf("Pod %s/%s container '%s' cannot pull image - Error: %s",
-			pod.Namespace, pod.Name, containerName, imageError)
-		labels["container"] = containerName
-
-	case AlertTypePodPending:
+	case AlertTypeJobRunningTooLong:
 		severity = SeverityMedium
-		message = fmt.Sprintf("Pod %s/%s is PENDING for extended period - Reason: %s",
-			pod.Namespace, pod.Name, pod.Status.Reason)
-		if pod.Status.Message != "" {
-			message += fmt.Sprintf(", Details: %s", pod.Status.Message)
-		}
-
-	case AlertTypePodUnknown:
-		severity = SeverityCritical
-		message = fmt.Sprintf("Pod %s/%s is in UNKNOWN state - Last known phase: %s",
-			pod.Namespace, pod.Name, pod.Status.Phase)
+		message = fmt.Sprintf("Job %s/%s is RUNNING TOO LONG - Running for %s, Active pods: %d",
+			job.Namespace, job.Name, time.Duration(value)*time.Second, job.Status.Active)
 
 	default:
 		severity = SeverityMedium
-		message = fmt.Sprintf("Pod %s/%s issue detected - Type: %s",
-			pod.Namespace, pod.Name, alertType)
+		message = fmt.Sprintf("Job %s/%s issue detected - Type: %s",
+			job.Namespace, job.Name, alertType)
 	}
 
-	return models.NewAlert(severity, message, "k8s_pod", value, labels)
+	return models.NewAlert(severity, message, SourceK8sJob, value, labels)
 }
 
-// BuildNodeAlert creates a detailed alert for node issues
-func BuildNodeAlert(node *corev1.Node, alertType AlertType, value float64) *models.Alert {
+// BuildCronJobAlert creates a detailed alert for cronjob issues
+func BuildCronJobAlert(cronJob *batchv1.CronJob, alertType AlertType, value float64) *models.Alert {
 	var severity string
 	var message string
 
 	labels := map[string]string{
-		"node":       node.Name,
+		"namespace":  cronJob.Namespace,
+		"cronjob":    cronJob.Name,
 		"alert_type": string(alertType),
 	}
 
 	switch alertType {
-	case AlertTypeNodeNotReady:
-		severity = SeverityCritical
-		message = fmt.Sprintf("Node %s is NOT READY - Status: %s",
-			node.Name, getNodeConditionReason(node, corev1.NodeReady))
-
-	case AlertTypeNodeMemoryPressure:
-		severity = SeverityHigh
-		message = fmt.Sprintf("Node %s has MEMORY PRESSURE - Available memory is low",
-			node.Name)
-
-	case AlertTypeNodeDiskPressure:
+	case AlertTypeCronJobMissedSchedule:
 		severity = SeverityHigh
-		message = fmt.Sprintf("Node %s has DISK PRESSURE - Disk space is running low",
-			node.Name)
+		lastSuccess := "never"
+		if cronJob.Status.LastSuccessfulTime != nil {
+			lastSuccess = cronJob.Status.LastSuccessfulTime.UTC().Format(time.RFC3339)
+		}
+		message = fmt.Sprintf("CronJob %s/%s has NOT SUCCEEDED for %d scheduled runs - Schedule: %s, Last success: %s",
+			cronJob.Namespace, cronJob.Name, int(value), cronJob.Spec.Schedule, lastSuccess)
 
-	case AlertTypeNodePIDPressure:
-		severity = SeverityMedium
-		message = fmt.Sprintf("Node %s has PID PRESSURE - Too many processes running",
-			node.Name)
+	case AlertTypeCronJobSuspended:
+		severity = SeverityLow
+		message = fmt.Sprintf("CronJob %s/%s is SUSPENDED - Schedule: %s",
+			cronJob.Namespace, cronJob.Name, cronJob.Spec.Schedule)
 
 	default:
 		severity = SeverityMedium
-		message = fmt.Sprintf("Node %s issue detected - Type: %s",
-			node.Name, alertType)
-	}
-
-	return models.NewAlert(severity, message, "k8s_node", value, labels)
-}
-
-// Helper functions to extract container-specific information
-
-func getOOMKilledContainer(pod *corev1.Pod) string {
-	for _, cs := range pod.Status.ContainerStatuses {
-		if cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.Reason == "OOMKilled" {
-			return cs.Name
-		}
+		message = fmt.Sprintf("CronJob %s/%s issue detected - Type: %s",
+			cronJob.Namespace, cronJob.Name, alertType)
 	}
-	return "unknown"
-}
 
-func getCrashLoopContainer(pod *corev1.Pod) (string, string) {
-	for _, cs := range pod.Status.ContainerStatuses {
-		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
-			return cs.Name, cs.State.Waiting.Message
-		}
-	}
-	return "unknown", "unknown"
+	return models.NewAlert(severity, message, SourceK8sCronJob, value, labels)
 }
 
-func getHighestRestartContainer(pod *corev1.Pod) string {
-	maxRestarts := int32(0)
-	containerName := "unknown"
-	for _, cs := range pod.Status.ContainerStatuses {
-		if cs.RestartCount > maxRestarts {
-			maxRestarts = cs.RestartCount
-			containerName = cs.Name
-		}
-	}
-	return containerName
-}
-
-func getImagePullError(pod *corev1.Pod) (string, string) {
-	for _, cs := range pod.Status.ContainerStatuses {
-		if cs.State.Waiting != nil &&
-			(cs.State.Waiting.Reason == "ImagePullBackOff" || cs.State.Waiting.Reason == "ErrImagePull") {
-			return cs.Name, cs.State.Waiting.Message
-		}
-	}
-	return "unknown", "unknown"
-}
-
-func getNodeConditionReason(node *corev1.Node, conditionType corev1.NodeConditionType) string {
-	for _, condition := range node.Status.Conditions {
-		if condition.Type == conditionType {
-			if condition.Reason != "" {
-				return condition.Reason
-			}
-			return string(condition.Status)
-		}
-	}
-	return "Unknown"
-}
-// BuildPodMetricAlert creates an alert for pod metric threshold violations
-func BuildPodMetricAlert(namespace, podName string, alertType AlertType, value float64, threshold float64) *models.Alert {
+// BuildPVCAlert creates a detailed alert for PersistentVolumeClaim issues
+func BuildPVCAlert(pvc *corev1.PersistentVolumeClaim, alertType AlertType, value float64) *models.Alert {
 	var severity string
 	var message string
-	
 
 	labels := map[string]string{
-		"namespace":  namespace,
-		"pod":        podName,
+		"namespace":  pvc.Namespace,
+		"pvc":        pvc.Name,
 		"alert_type": string(alertType),
-		"metric":     "",
+	}
+	storageClass := "default"
+	if pvc.Spec.StorageClassName != nil {
+		storageClass = *pvc.Spec.StorageClassName
+		labels["storage_class"] = storageClass
 	}
 
 	switch alertType {
-	case AlertTypePodCPUHigh:
-		severity = SeverityHigh
-		
-		labels["metric"] = "cpu"
-		message = fmt.Sprintf("Pod %s/%s CPU usage is HIGH: %.1f%% (threshold: %.1f%%)",
-			namespace, podName, value, threshold)
+	case AlertTypePVCPending:
+		severity = SeverityMedium
+		message = fmt.Sprintf("PVC %s/%s is PENDING - Not bound for %s, StorageClass: %s",
+			pvc.Namespace, pvc.Name, time.Duration(value)*time.Second, storageClass)
 
-	case AlertTypePodMemoryHigh:
-		severity = SeverityHigh
-		
-		labels["metric"] = "memory"
-		message = fmt.Sprintf("Pod %s/%s Memory usage is HIGH: %.1f%% (threshold: %.1f%%)",
-			namespace, podName, value, threshold)
+	case AlertTypePVCLost:
+		severity = SeverityCritical
+		message = fmt.Sprintf("PVC %s/%s is LOST - Its PersistentVolume %s no longer exists",
+			pvc.Namespace, pvc.Name, pvc.Spec.VolumeName)
 
 	default:
 		severity = SeverityMedium
-		message = fmt.Sprintf("Pod %s/%s metric alert", namespace, podName)
+		message = fmt.Sprintf("PVC %s/%s issue detected - Type: %s",
+			pvc.Namespace, pvc.Name, alertType)
 	}
 
-	return models.NewAlert(severity, message, "k8s_pod_metrics", value, labels)
+	return models.NewAlert(severity, message, SourceK8sPVC, value, labels)
 }
 
-// BuildNodeMetricAlert creates an alert for node metric threshold violations
-func BuildNodeMetricAlert(nodeName string, alertType AlertType, value float64, threshold float64) *models.Alert {
-	var severity string
-	var message string
-	
-
+// BuildEventAlert creates an alert for Warning events with one reason on one object. The
+// value is the number of events seen in the aggregation window.
+func BuildEventAlert(event *corev1.Event, severity string, count int) *models.Alert {
+	object := event.InvolvedObject
 	labels := map[string]string{
-		"node":       nodeName,
-		"alert_type": string(alertType),
-		"metric":     "",
+		"namespace":   object.Namespace,
+		"object_kind": object.Kind,
+		"object":      object.Name,
+		"reason":      event.Reason,
+		"alert_type":  EventAlertType(event.Reason),
+	}
+	// Pod and node events share the subject labels of pod and node alerts
+	switch object.Kind {
+	case K8sResourceTypePod:
+		labels["pod"] = object.Name
+	case K8sResourceTypeNode:
+		labels["node"] = object.Name
 	}
 
-	switch alertType {
-	case AlertTypeNodeCPUHigh:
-		severity = SeverityCritical
-		
-		labels["metric"] = "cpu"
-		message = fmt.Sprintf("Node %s CPU usage is CRITICAL: %.1f%% (threshold: %.1f%%)",
-			nodeName, value, threshold)
+	subject := object.Name
+	if object.Namespace != "" {
+		subject = object.Namespace + "/" + object.Name
+	}
+	message := fmt.Sprintf("%s %s has %d %s WARNING events - %s",
+		object.Kind, subject, count, event.Reason, event.Message)
 
-	case AlertTypeNodeMemoryHigh:
-		severity = SeverityCritical
-		
-		labels["metric"] = "memory"
-		message = fmt.Sprintf("Node %s Memory usage is CRITICAL: %.1f%% (threshold: %.1f%%)",
-			nodeName, value, threshold)
+	return models.NewAlert(severity, message, SourceK8sEvent, float64(count), labels)
+}
 
-	default:
-		severity = SeverityMedium
-		message = fmt.Sprintf("Node %s metric alert", nodeName)
+// EventAlertType returns the alert type of events with the reason, e.g. event_failed_scheduling
+// for FailedScheduling and event_oom_killing for OOMKilling
+func EventAlertType(reason string) string {
+	runes := []rune(reason)
+	var b strings.Builder
+	b.WriteString("event")
+
+	newWord := true
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			newWord = true
+			continue
+		}
+		// A word starts at an upper-case letter following a lower-case one, or ending an acronym
+		if unicode.IsUpper(r) && i > 0 && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			newWord = true
+		}
+		if newWord {
+			b.WriteByte('_')
+			newWord = false
+		}
+		b.WriteRune(unicode.ToLower(r))
 	}
+	return b.String()
+}
 
-	return models.NewAlert(severity, message, "k8s_node_metrics", value, labels)
+// getJobCronJob returns the name of the CronJob that created the job, if any
+func getJobCronJob(job *batchv1.Job) string {
+	for _, owner := range job.OwnerReferences {
+		if owner.Kind == "CronJob" {
+			return owner.Name
+		}
+	}
+	return ""
+}
+
+func getJobFailure(job *batchv1.Job) (string, string) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return condition.Reason, condition.Message
+		}
+	}
+	return "BackoffLimitExceeded", ""
+}
+
+// jobBackoffLimit returns the retries a job allows, which default to 6 when unset
+func jobBackoffLimit(job *batchv1.Job) int32 {
+	if job.Spec.BackoffLimit == nil {
+		return 6
+	}
+	return *job.Spec.BackoffLimit
 }
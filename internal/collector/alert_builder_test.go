@@ -4,559 +4,146 @@ import (
 	"testing"
 
 	"github.com/monitoring-engine/monitoring-tool/internal/collector"
-	"github.com/monitoring-engine/monitoring-tool/internal/models"
 	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func TestBuildPodAlert(t *testing.T) {
-	t.Run("should build pod failed alert", func(t *testing.T) {
-		pod := &corev1.Pod{
+func TestBuildBatchAlerts(t *testing.T) {
+	t.Run("should build job failed alert labelled with its cronjob", func(t *testing.T) {
+		backoffLimit := int32(2)
+		job := &batchv1.Job{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test-pod",
-				Namespace: "test-namespace",
+				Name:            "nightly-report-28930",
+				Namespace:       "batch",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "nightly-report"}},
 			},
-			Status: corev1.PodStatus{
-				Phase:   corev1.PodFailed,
-				Reason:  "Error",
-				Message: "Container failed",
+			Spec: batchv1.JobSpec{BackoffLimit: &backoffLimit},
+			Status: batchv1.JobStatus{
+				Failed: 3,
+				Conditions: []batchv1.JobCondition{{
+					Type:    batchv1.JobFailed,
+					Status:  corev1.ConditionTrue,
+					Reason:  "BackoffLimitExceeded",
+					Message: "Job has reached the specified backoff limit",
+				}},
 			},
 		}
 
-		alert := collector.BuildPodAlert(pod, collector.AlertTypePodFailed, 1.0)
+		alert := collector.BuildJobAlert(job, collector.AlertTypeJobFailed, 3)
 
-		assert.NotNil(t, alert)
-		assert.Equal(t, "critical", alert.Severity)
-		assert.Contains(t, alert.Message, "test-pod")
-		assert.Contains(t, alert.Message, "FAILED")
-		assert.Equal(t, "k8s_pod", alert.Source)
-		assert.Equal(t, 1.0, alert.Value)
-	})
-
-	t.Run("should build pod OOM killed alert", func(t *testing.T) {
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "oom-pod",
-				Namespace: "production",
-			},
-			Status: corev1.PodStatus{
-				ContainerStatuses: []corev1.ContainerStatus{
-					{
-						Name: "app-container",
-						LastTerminationState: corev1.ContainerState{
-							Terminated: &corev1.ContainerStateTerminated{
-								Reason: "OOMKilled",
-							},
-						},
-					},
-				},
-			},
-		}
-
-		alert := collector.BuildPodAlert(pod, collector.AlertTypePodOOMKilled, 1.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "critical", alert.Severity)
-		assert.Contains(t, alert.Message, "OOM KILLED")
-		assert.Contains(t, alert.Message, "app-container")
-	})
-
-	t.Run("should build pod crash loop alert", func(t *testing.T) {
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "crash-pod",
-				Namespace: "staging",
-			},
-			Status: corev1.PodStatus{
-				ContainerStatuses: []corev1.ContainerStatus{
-					{
-						Name: "main-container",
-						State: corev1.ContainerState{
-							Waiting: &corev1.ContainerStateWaiting{
-								Reason:  "CrashLoopBackOff",
-								Message: "Container exited with code 1",
-							},
-						},
-					},
-				},
-			},
-		}
-
-		alert := collector.BuildPodAlert(pod, collector.AlertTypePodCrashLoop, 5.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "high", alert.Severity)
-		assert.Contains(t, alert.Message, "CRASH LOOP")
-		assert.Contains(t, alert.Message, "main-container")
-	})
-
-	t.Run("should build pod restart threshold alert", func(t *testing.T) {
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "restart-pod",
-				Namespace: "default",
-			},
-			Status: corev1.PodStatus{
-				ContainerStatuses: []corev1.ContainerStatus{
-					{
-						Name:         "container-1",
-						RestartCount: 10,
-					},
-					{
-						Name:         "container-2",
-						RestartCount: 5,
-					},
-				},
-			},
-		}
-
-		alert := collector.BuildPodAlert(pod, collector.AlertTypePodRestartThreshold, 10.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "high", alert.Severity)
-		assert.Contains(t, alert.Message, "EXCESSIVE RESTARTS")
-		assert.Contains(t, alert.Message, "container-1") // Highest restart count
-	})
-
-	t.Run("should build pod image pull error alert", func(t *testing.T) {
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "image-pod",
-				Namespace: "dev",
-			},
-			Status: corev1.PodStatus{
-				ContainerStatuses: []corev1.ContainerStatus{
-					{
-						Name: "app",
-						State: corev1.ContainerState{
-							Waiting: &corev1.ContainerStateWaiting{
-								Reason:  "ImagePullBackOff",
-								Message: "Failed to pull image",
-							},
-						},
-					},
-				},
-			},
-		}
-
-		alert := collector.BuildPodAlert(pod, collector.AlertTypePodImagePullError, 1.0)
-
-		assert.NotNil(t, alert)
 		assert.Equal(t, "high", alert.Severity)
-		assert.Contains(t, alert.Message, "cannot pull image")
+		assert.Equal(t, "k8s_job", alert.Source)
+		assert.Contains(t, alert.Message, "Failed pods: 3 (backoffLimit: 2)")
+		assert.Equal(t, map[string]string{
+			"namespace":  "batch",
+			"job":        "nightly-report-28930",
+			"cronjob":    "nightly-report",
+			"alert_type": "job_failed",
+			"reason":     "BackoffLimitExceeded",
+		}, alert.GetLabelsMap())
 	})
 
-	t.Run("should build pod pending alert", func(t *testing.T) {
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "pending-pod",
-				Namespace: "test",
-			},
-			Status: corev1.PodStatus{
-				Phase:   corev1.PodPending,
-				Reason:  "Unschedulable",
-				Message: "Insufficient CPU",
-			},
+	t.Run("should build job running too long alert", func(t *testing.T) {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default"},
+			Status:     batchv1.JobStatus{Active: 1},
 		}
 
-		alert := collector.BuildPodAlert(pod, collector.AlertTypePodPending, 1.0)
+		alert := collector.BuildJobAlert(job, collector.AlertTypeJobRunningTooLong, 7200)
 
-		assert.NotNil(t, alert)
 		assert.Equal(t, "medium", alert.Severity)
-		assert.Contains(t, alert.Message, "PENDING")
-		assert.Contains(t, alert.Message, "Unschedulable")
-	})
-
-	t.Run("should build pod unknown alert", func(t *testing.T) {
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "unknown-pod",
-				Namespace: "test",
-			},
-			Status: corev1.PodStatus{
-				Phase: corev1.PodUnknown,
-			},
-		}
-
-		alert := collector.BuildPodAlert(pod, collector.AlertTypePodUnknown, 1.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "critical", alert.Severity)
-		assert.Contains(t, alert.Message, "UNKNOWN state")
-	})
-
-	t.Run("should handle pod with no container statuses", func(t *testing.T) {
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "empty-pod",
-				Namespace: "test",
-			},
-			Status: corev1.PodStatus{
-				ContainerStatuses: []corev1.ContainerStatus{},
-			},
-		}
-
-		alert := collector.BuildPodAlert(pod, collector.AlertTypePodOOMKilled, 1.0)
-
-		assert.NotNil(t, alert)
-		// Should handle gracefully with "unknown" container
-	})
-}
-
-func TestBuildNodeAlert(t *testing.T) {
-	t.Run("should build node not ready alert", func(t *testing.T) {
-		node := &corev1.Node{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "worker-node-1",
-			},
-			Status: corev1.NodeStatus{
-				Conditions: []corev1.NodeCondition{
-					{
-						Type:   corev1.NodeReady,
-						Status: corev1.ConditionFalse,
-						Reason: "KubeletNotReady",
-					},
-				},
-			},
-		}
-
-		alert := collector.BuildNodeAlert(node, collector.AlertTypeNodeNotReady, 1.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "critical", alert.Severity)
-		assert.Contains(t, alert.Message, "NOT READY")
-		assert.Contains(t, alert.Message, "worker-node-1")
-		assert.Equal(t, "k8s_node", alert.Source)
-	})
-
-	t.Run("should build node memory pressure alert", func(t *testing.T) {
-		node := &corev1.Node{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "node-2",
-			},
-			Status: corev1.NodeStatus{
-				Conditions: []corev1.NodeCondition{
-					{
-						Type:   corev1.NodeMemoryPressure,
-						Status: corev1.ConditionTrue,
-					},
-				},
-			},
-		}
-
-		alert := collector.BuildNodeAlert(node, collector.AlertTypeNodeMemoryPressure, 1.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "high", alert.Severity)
-		assert.Contains(t, alert.Message, "MEMORY PRESSURE")
-	})
-
-	t.Run("should build node disk pressure alert", func(t *testing.T) {
-		node := &corev1.Node{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "node-3",
-			},
-		}
-
-		alert := collector.BuildNodeAlert(node, collector.AlertTypeNodeDiskPressure, 1.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "high", alert.Severity)
-		assert.Contains(t, alert.Message, "DISK PRESSURE")
+		assert.Contains(t, alert.Message, "Running for 2h0m0s")
+		assert.NotContains(t, alert.GetLabelsMap(), "cronjob")
 	})
 
-	t.Run("should build node PID pressure alert", func(t *testing.T) {
-		node := &corev1.Node{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "node-4",
-			},
+	t.Run("should build cronjob alerts", func(t *testing.T) {
+		cronJob := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly-report", Namespace: "batch"},
+			Spec:       batchv1.CronJobSpec{Schedule: "0 2 * * *"},
 		}
 
-		alert := collector.BuildNodeAlert(node, collector.AlertTypeNodePIDPressure, 1.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "medium", alert.Severity)
-		assert.Contains(t, alert.Message, "PID PRESSURE")
-	})
-}
-
-func TestBuildPodMetricAlert(t *testing.T) {
-	t.Run("should build pod CPU high alert", func(t *testing.T) {
-		alert := collector.BuildPodMetricAlert("production", "api-pod", collector.AlertTypePodCPUHigh, 85.5, 80.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "high", alert.Severity)
-		assert.Contains(t, alert.Message, "CPU usage is HIGH")
-		assert.Contains(t, alert.Message, "85.5")
-		assert.Contains(t, alert.Message, "80.0")
-		assert.Equal(t, "k8s_pod_metrics", alert.Source)
-		assert.Equal(t, 85.5, alert.Value)
-	})
-
-	t.Run("should build pod memory high alert", func(t *testing.T) {
-		alert := collector.BuildPodMetricAlert("staging", "db-pod", collector.AlertTypePodMemoryHigh, 92.3, 85.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "high", alert.Severity)
-		assert.Contains(t, alert.Message, "Memory usage is HIGH")
-		assert.Contains(t, alert.Message, "92.3")
-		assert.Contains(t, alert.Message, "85.0")
-	})
-
-	t.Run("should handle unknown pod metric alert type", func(t *testing.T) {
-		alert := collector.BuildPodMetricAlert("test", "pod-1", "unknown", 50.0, 60.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "medium", alert.Severity)
-	})
-}
-
-func TestBuildNodeMetricAlert(t *testing.T) {
-	t.Run("should build node CPU high alert", func(t *testing.T) {
-		alert := collector.BuildNodeMetricAlert("master-node", collector.AlertTypeNodeCPUHigh, 88.7, 70.0)
+		missed := collector.BuildCronJobAlert(cronJob, collector.AlertTypeCronJobMissedSchedule, 2)
+		suspended := collector.BuildCronJobAlert(cronJob, collector.AlertTypeCronJobSuspended, 1)
 
-		assert.NotNil(t, alert)
-		assert.Equal(t, "critical", alert.Severity)
-		assert.Contains(t, alert.Message, "CPU usage is CRITICAL")
-		assert.Contains(t, alert.Message, "88.7")
-		assert.Contains(t, alert.Message, "70.0")
-		assert.Equal(t, "k8s_node_metrics", alert.Source)
-		assert.Equal(t, 88.7, alert.Value)
-	})
-
-	t.Run("should build node memory high alert", func(t *testing.T) {
-		alert := collector.BuildNodeMetricAlert("worker-node", collector.AlertTypeNodeMemoryHigh, 91.2, 75.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "critical", alert.Severity)
-		assert.Contains(t, alert.Message, "Memory usage is CRITICAL")
-		assert.Contains(t, alert.Message, "91.2")
-		assert.Contains(t, alert.Message, "75.0")
-	})
-
-	t.Run("should handle unknown node metric alert type", func(t *testing.T) {
-		alert := collector.BuildNodeMetricAlert("node-1", "unknown", 50.0, 60.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "medium", alert.Severity)
+		assert.Equal(t, "high", missed.Severity)
+		assert.Equal(t, "k8s_cronjob", missed.Source)
+		assert.Contains(t, missed.Message, "2 scheduled runs")
+		assert.Contains(t, missed.Message, "Last success: never")
+		assert.Equal(t, "low", suspended.Severity)
+		assert.Equal(t, "nightly-report", suspended.GetLabelsMap()["cronjob"])
+		assert.NotEqual(t, missed.Fingerprint, suspended.Fingerprint)
 	})
 }
 
-func TestAlertLabels(t *testing.T) {
-	t.Run("pod alert should include correct labels", func(t *testing.T) {
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test-pod",
-				Namespace: "test-ns",
-			},
-		}
-
-		alert := collector.BuildPodAlert(pod, collector.AlertTypePodFailed, 1.0)
-
-		labels := alert.GetLabelsMap()
-		assert.Equal(t, "test-ns", labels["namespace"])
-		assert.Equal(t, "test-pod", labels["pod"])
-		assert.Equal(t, "pod_failed", labels["alert_type"])
-	})
-
-	t.Run("node alert should include correct labels", func(t *testing.T) {
-		node := &corev1.Node{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test-node",
-			},
+func TestBuildStorageAlerts(t *testing.T) {
+	t.Run("should build pvc alerts", func(t *testing.T) {
+		storageClass := "fast-ssd"
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-postgres-0", Namespace: "db"},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass, VolumeName: "pvc-1234"},
 		}
 
-		alert := collector.BuildNodeAlert(node, collector.AlertTypeNodeNotReady, 1.0)
+		pending := collector.BuildPVCAlert(pvc, collector.AlertTypePVCPending, 600)
+		lost := collector.BuildPVCAlert(pvc, collector.AlertTypePVCLost, 1)
 
-		labels := alert.GetLabelsMap()
-		assert.Equal(t, "test-node", labels["node"])
-		assert.Equal(t, "node_not_ready", labels["alert_type"])
+		assert.Equal(t, "medium", pending.Severity)
+		assert.Equal(t, "k8s_pvc", pending.Source)
+		assert.Contains(t, pending.Message, "Not bound for 10m0s, StorageClass: fast-ssd")
+		assert.Equal(t, "critical", lost.Severity)
+		assert.Contains(t, lost.Message, "PersistentVolume pvc-1234")
+		assert.Equal(t, map[string]string{
+			"namespace":     "db",
+			"pvc":           "data-postgres-0",
+			"storage_class": "fast-ssd",
+			"alert_type":    "pvc_lost",
+		}, lost.GetLabelsMap())
 	})
-}
-
-func TestAlertSeverityMapping(t *testing.T) {
-	tests := []struct {
-		name         string
-		alertType    collector.AlertType
-		wantSeverity string
-	}{
-		{"pod failed is critical", collector.AlertTypePodFailed, "critical"},
-		{"pod OOM is critical", collector.AlertTypePodOOMKilled, "critical"},
-		{"pod crash loop is high", collector.AlertTypePodCrashLoop, "high"},
-		{"pod restart threshold is high", collector.AlertTypePodRestartThreshold, "high"},
-		{"pod pending is medium", collector.AlertTypePodPending, "medium"},
-		{"node not ready is critical", collector.AlertTypeNodeNotReady, "critical"},
-		{"node memory pressure is high", collector.AlertTypeNodeMemoryPressure, "high"},
-		{"node disk pressure is high", collector.AlertTypeNodeDiskPressure, "high"},
-		{"node PID pressure is medium", collector.AlertTypeNodePIDPressure, "medium"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			var alert *models.Alert
-
-			// Create appropriate test object based on alert type
-			if tt.alertType == collector.AlertTypeNodeNotReady ||
-				tt.alertType == collector.AlertTypeNodeMemoryPressure ||
-				tt.alertType == collector.AlertTypeNodeDiskPressure ||
-				tt.alertType == collector.AlertTypeNodePIDPressure {
-				node := &corev1.Node{
-					ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
-				}
-				alert = collector.BuildNodeAlert(node, tt.alertType, 1.0)
-			} else {
-				pod := &corev1.Pod{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "test-pod",
-						Namespace: "test-ns",
-					},
-				}
-				alert = collector.BuildPodAlert(pod, tt.alertType, 1.0)
-			}
 
-			assert.Equal(t, tt.wantSeverity, alert.Severity)
-		})
-	}
 }
 
-func TestBuildPodAlert_EdgeCases(t *testing.T) {
-	t.Run("should handle pod with multiple image pull errors", func(t *testing.T) {
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "multi-error-pod",
-				Namespace: "test",
-			},
-			Status: corev1.PodStatus{
-				ContainerStatuses: []corev1.ContainerStatus{
-					{
-						Name: "container-1",
-						State: corev1.ContainerState{
-							Waiting: &corev1.ContainerStateWaiting{
-								Reason:  "ErrImagePull",
-								Message: "Failed to pull image",
-							},
-						},
-					},
-					{
-						Name: "container-2",
-						State: corev1.ContainerState{
-							Running: &corev1.ContainerStateRunning{},
-						},
-					},
-				},
-			},
+func TestBuildEventAlert(t *testing.T) {
+	t.Run("should build event alert with pod subject labels", func(t *testing.T) {
+		event := &corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "production", Name: "api-0"},
+			Reason:         "FailedScheduling",
+			Message:        "0/3 nodes are available: 3 Insufficient cpu.",
 		}
 
-		alert := collector.BuildPodAlert(pod, collector.AlertTypePodImagePullError, 1.0)
+		alert := collector.BuildEventAlert(event, "high", 4)
 
-		assert.NotNil(t, alert)
 		assert.Equal(t, "high", alert.Severity)
-		assert.Contains(t, alert.Message, "container-1")
-	})
-
-	t.Run("should handle pod with no waiting reason", func(t *testing.T) {
-		pod := &corev1.Pod{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "no-reason-pod",
-				Namespace: "test",
-			},
-			Status: corev1.PodStatus{
-				ContainerStatuses: []corev1.ContainerStatus{
-					{
-						Name: "container",
-						State: corev1.ContainerState{
-							Waiting: &corev1.ContainerStateWaiting{},
-						},
-					},
-				},
-			},
-		}
-
-		alert := collector.BuildPodAlert(pod, collector.AlertTypePodImagePullError, 1.0)
-
-		assert.NotNil(t, alert)
-		assert.Contains(t, alert.Message, "unknown")
+		assert.Equal(t, "k8s_event", alert.Source)
+		assert.Equal(t, 4.0, alert.Value)
+		assert.Equal(t, "Pod production/api-0 has 4 FailedScheduling WARNING events - 0/3 nodes are available: 3 Insufficient cpu.", alert.Message)
+		assert.Equal(t, map[string]string{
+			"namespace":   "production",
+			"object_kind": "Pod",
+			"object":      "api-0",
+			"pod":         "api-0",
+			"reason":      "FailedScheduling",
+			"alert_type":  "event_failed_scheduling",
+		}, alert.GetLabelsMap())
 	})
 
-	t.Run("should handle node with no matching condition", func(t *testing.T) {
-		node := &corev1.Node{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test-node",
-			},
-			Status: corev1.NodeStatus{
-				Conditions: []corev1.NodeCondition{},
-			},
+	t.Run("should keep the fingerprint when the count changes", func(t *testing.T) {
+		event := &corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: "db", Name: "data-0"},
+			Reason:         "ProvisioningFailed",
 		}
 
-		alert := collector.BuildNodeAlert(node, collector.AlertTypeNodeMemoryPressure, 1.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "high", alert.Severity)
+		assert.Equal(t, collector.BuildEventAlert(event, "medium", 1).Fingerprint, collector.BuildEventAlert(event, "medium", 9).Fingerprint)
 	})
 
-	t.Run("should handle node condition with empty reason", func(t *testing.T) {
-		node := &corev1.Node{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test-node",
-			},
-			Status: corev1.NodeStatus{
-				Conditions: []corev1.NodeCondition{
-					{
-						Type:   corev1.NodeReady,
-						Status: corev1.ConditionFalse,
-						Reason: "",
-					},
-				},
-			},
+	t.Run("should derive alert types from reasons", func(t *testing.T) {
+		for reason, alertType := range map[string]string{
+			"FailedCreatePodSandBox": "event_failed_create_pod_sand_box",
+			"BackOff":                "event_back_off",
+			"OOMKilling":             "event_oom_killing",
+			"Evicted":                "event_evicted",
+		} {
+			assert.Equal(t, alertType, collector.EventAlertType(reason), reason)
 		}
-
-		alert := collector.BuildNodeAlert(node, collector.AlertTypeNodeNotReady, 1.0)
-
-		assert.NotNil(t, alert)
-		assert.Contains(t, alert.Message, "False")
-	})
-}
-
-func TestBuildNodeAlert_EdgeCases(t *testing.T) {
-	t.Run("should handle unknown alert type", func(t *testing.T) {
-		node := &corev1.Node{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test-node",
-			},
-		}
-
-		alert := collector.BuildNodeAlert(node, "unknown_type", 1.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "medium", alert.Severity)
-	})
-
-	t.Run("should handle node with multiple conditions", func(t *testing.T) {
-		node := &corev1.Node{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "multi-condition-node",
-			},
-			Status: corev1.NodeStatus{
-				Conditions: []corev1.NodeCondition{
-					{
-						Type:   corev1.NodeReady,
-						Status: corev1.ConditionTrue,
-						Reason: "KubeletReady",
-					},
-					{
-						Type:   corev1.NodeMemoryPressure,
-						Status: corev1.ConditionTrue,
-						Reason: "MemoryPressure",
-					},
-				},
-			},
-		}
-
-		alert := collector.BuildNodeAlert(node, collector.AlertTypeNodeMemoryPressure, 1.0)
-
-		assert.NotNil(t, alert)
-		assert.Equal(t, "high", alert.Severity)
-		assert.Contains(t, alert.Message, "MEMORY PRESSURE")
 	})
 }
@@ -15,16 +15,19 @@ const (
 	AlertStateResolved     = "resolved"
 )
 
-// Notification States
+// Alert Sources
 const (
-	NotificationStatePending = "pending"
-	NotificationStateSuccess = "success"
-	NotificationStateFailed  = "failed"
-)
-
-// Notification Channel Types
-const (
-	ChannelTypeEmail = "email"
+	SourceK8sPod              = "k8s_pod"
+	SourceK8sNode             = "k8s_node"
+	SourceK8sPodMetrics       = "k8s_pod_metrics"
+	SourceK8sNodeMetrics      = "k8s_node_metrics"
+	SourceK8sContainerMetrics = "k8s_container_metrics"
+	SourceK8sWorkload         = "k8s_workload"
+	SourceK8sJob              = "k8s_job"
+	SourceK8sCronJob          = "k8s_cronjob"
+	SourceK8sEvent            = "k8s_event"
+	SourceK8sPVC              = "k8s_pvc"
+	SourceK8sVolume           = "k8s_volume"
 )
 
 // Target Types
@@ -55,12 +58,14 @@ const (
 
 // Kubernetes Resource Types
 const (
-	K8sResourceTypePod        = "Pod"
-	K8sResourceTypeNode       = "Node"
-	K8sResourceTypeDeployment = "Deployment"
-	K8sResourceTypeService    = "Service"
-	K8sResourceTypePVC        = "PersistentVolumeClaim"
-	K8sResourceTypeNamespace  = "Namespace"
+	K8sResourceTypePod         = "Pod"
+	K8sResourceTypeNode        = "Node"
+	K8sResourceTypeDeployment  = "Deployment"
+	K8sResourceTypeStatefulSet = "StatefulSet"
+	K8sResourceTypeDaemonSet   = "DaemonSet"
+	K8sResourceTypeService     = "Service"
+	K8sResourceTypePVC         = "PersistentVolumeClaim"
+	K8sResourceTypeNamespace   = "Namespace"
 )
 
 // Kubernetes Event Types
@@ -0,0 +1,405 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/pool"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// Event watcher defaults
+const (
+	defaultEventWindow = 10 * time.Minute
+	eventSweepInterval = 30 * time.Second
+)
+
+// defaultEventReasons are the Warning event reasons that alert when none are configured
+var defaultEventReasons = map[string]config.EventReasonConfig{
+	"FailedScheduling":       {Severity: SeverityHigh},
+	"FailedMount":            {Severity: SeverityHigh},
+	"FailedCreatePodSandBox": {Severity: SeverityHigh},
+	"Evicted":                {Severity: SeverityMedium},
+	"BackOff":                {Severity: SeverityMedium, MinCount: 5},
+	"Unhealthy":              {Severity: SeverityMedium, MinCount: 3},
+}
+
+// Broadcaster streams messages to dashboard clients, e.g. the WebSocket hub
+type Broadcaster interface {
+	BroadcastJSON(msgType string, payload interface{}) error
+}
+
+// K8sEvent is a Warning event as streamed to WebSocket clients
+type K8sEvent struct {
+	Namespace       string    `json:"namespace"`
+	Kind            string    `json:"kind"`
+	Name            string    `json:"name"`
+	Reason          string    `json:"reason"`
+	Message         string    `json:"message"`
+	Component       string    `json:"component,omitempty"`
+	Count           int32     `json:"count"`            // occurrences of this event
+	AggregatedCount int       `json:"aggregated_count"` // occurrences of events with the reason on the object within the window
+	FirstSeen       time.Time `json:"first_seen"`
+	LastSeen        time.Time `json:"last_seen"`
+}
+
+// eventAggregate holds the recent events with one reason on one object
+type eventAggregate struct {
+	latest *corev1.Event           // the most recently seen event, which alerts describe
+	events map[string]*eventSeries // event key -> its occurrences
+}
+
+// eventSeries holds the occurrences of one event. Kubernetes reports the count of an event over
+// its lifetime, so the count at each sighting within the window is kept to tell how many of
+// them happened within the window.
+type eventSeries struct {
+	count     int32 // occurrences over the event's lifetime
+	lastSeen  time.Time
+	before    int32           // occurrences before the window
+	sightings []eventSighting // within the window, oldest first
+}
+
+type eventSighting struct {
+	count int32
+	at    time.Time
+}
+
+// newEventSeries starts the series of an event first seen now. Its occurrences count as within
+// the window unless the event reports a first occurrence before the window; then only the
+// latest one does.
+func newEventSeries(event *corev1.Event, windowStart time.Time) *eventSeries {
+	series := &eventSeries{}
+	if firstSeen := eventFirstSeen(event); !firstSeen.IsZero() && firstSeen.Before(windowStart) {
+		series.before = eventCount(event) - 1
+	}
+	return series
+}
+
+// observe records a sighting of the event
+func (s *eventSeries) observe(count int32, lastSeen time.Time) {
+	s.count = count
+	s.lastSeen = lastSeen
+	s.sightings = append(s.sightings, eventSighting{count: count, at: lastSeen})
+}
+
+// expire drops the sightings before the window start; the occurrences up to them no longer count
+func (s *eventSeries) expire(windowStart time.Time) {
+	for len(s.sightings) > 0 && s.sightings[0].at.Before(windowStart) {
+		s.before = s.sightings[0].count
+		s.sightings = s.sightings[1:]
+	}
+}
+
+// inWindow returns the occurrences of the event within the window
+func (s *eventSeries) inWindow() int {
+	return int(s.count - s.before)
+}
+
+// total returns the occurrences of all events of the aggregate within the window
+func (a *eventAggregate) total() int {
+	total := 0
+	for _, series := range a.events {
+		total += series.inWindow()
+	}
+	return total
+}
+
+// EventWatcher watches core/v1 Events through a shared informer. Warning events are streamed
+// to the broadcaster and aggregated by involved object and reason; reasons mapped to a
+// severity alert once enough events are seen within the window, and resolve once the window
+// passes without them.
+type EventWatcher struct {
+	client       *K8sClient
+	informer     cache.SharedIndexInformer
+	lister       listersv1.EventLister
+	queue        *objectQueue
+	stateManager *processor.AlertStateManager
+	broadcaster  Broadcaster // optional
+	window       time.Duration
+	reasons      map[string]config.EventReasonConfig
+	aggregates   map[string]*eventAggregate // involved object and reason -> recent events
+	mu           sync.Mutex
+	// alertMu is held exclusively while sweeping, so no alert is raised between the sweep
+	// deciding what still fires and resolving the rest
+	alertMu sync.RWMutex
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewEventWatcher creates a new event watcher. Unset settings take their defaults.
+func NewEventWatcher(k8sClient *K8sClient, stateManager *processor.AlertStateManager, broadcaster Broadcaster, cfg config.EventsConfig, workerPool *pool.WorkerPool) (*EventWatcher, error) {
+	reasons := cfg.Reasons
+	if len(reasons) == 0 {
+		reasons = defaultEventReasons
+	}
+	normalized := make(map[string]config.EventReasonConfig, len(reasons))
+	for reason, rule := range reasons {
+		switch rule.Severity {
+		case SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow:
+		default:
+			return nil, fmt.Errorf("event reason %q: invalid severity %q", reason, rule.Severity)
+		}
+		if rule.MinCount <= 0 {
+			rule.MinCount = 1
+		}
+		normalized[reason] = rule
+	}
+
+	eventInformer := k8sClient.GetInformerFactory().Core().V1().Events()
+	ew := &EventWatcher{
+		client:       k8sClient,
+		informer:     eventInformer.Informer(),
+		lister:       eventInformer.Lister(),
+		stateManager: stateManager,
+		broadcaster:  broadcaster,
+		window:       cfg.Window,
+		reasons:      normalized,
+		aggregates:   make(map[string]*eventAggregate),
+		stopCh:       make(chan struct{}),
+	}
+	if ew.window <= 0 {
+		ew.window = defaultEventWindow
+	}
+	ew.queue = newObjectQueue("event", workerPool, ew.processEvent)
+	return ew, nil
+}
+
+// Start begins watching events. The informer lists all events, then watches from that
+// resourceVersion, relisting with backoff whenever the watch breaks.
+func (ew *EventWatcher) Start(ctx context.Context) {
+	logger.Info().
+		Dur("window", ew.window).
+		Int("alerting_reasons", len(ew.reasons)).
+		Msg("Starting Event Watcher with informer and worker pool")
+
+	if err := ew.informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		logger.Warn().Err(err).Msg("Event watch failed, informer will relist with backoff")
+	}); err != nil {
+		logger.Warn().Err(err).Msg("Failed to set event watch error handler")
+	}
+	if _, err := ew.informer.AddEventHandler(ew.queue.handler()); err != nil {
+		logger.Error().Err(err).Msg("Failed to register event handler")
+		return
+	}
+
+	ew.wg.Add(2)
+	go ew.queue.run(ctx, ew.stopCh, &ew.wg, ew.informer.HasSynced)
+	go ew.runSweeps(ctx)
+}
+
+// processEvent aggregates the latest cached state of a Warning event, streams it and raises
+// the alert of its reason once enough of them occurred within the window
+func (ew *EventWatcher) processEvent(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Warn().Err(err).Str("key", key).Msg("Invalid event key")
+		return nil
+	}
+
+	event, err := ew.lister.Events(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		// Expired events age out of their aggregate when the window passes
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if event.Type != corev1.EventTypeWarning {
+		return nil
+	}
+
+	count, lastSeen := eventCount(event), eventLastSeen(event)
+	now := time.Now()
+	// Events listed at startup may be long over
+	if now.Sub(lastSeen) > ew.window {
+		return nil
+	}
+
+	ew.alertMu.RLock()
+	defer ew.alertMu.RUnlock()
+
+	object := event.InvolvedObject
+	aggregateKey := object.Kind + "/" + object.Namespace + "/" + object.Name + "/" + event.Reason
+
+	ew.mu.Lock()
+	aggregate, ok := ew.aggregates[aggregateKey]
+	if !ok {
+		aggregate = &eventAggregate{events: make(map[string]*eventSeries)}
+		ew.aggregates[aggregateKey] = aggregate
+	}
+	series, seen := aggregate.events[key]
+	if seen && series.count == count && series.lastSeen.Equal(lastSeen) {
+		// A resync of an event that has not recurred
+		ew.mu.Unlock()
+		return nil
+	}
+	// A lower count is a new event reusing the name
+	if !seen || count < series.count {
+		series = newEventSeries(event, now.Add(-ew.window))
+		aggregate.events[key] = series
+	}
+	series.observe(count, lastSeen)
+	if aggregate.latest == nil || !lastSeen.Before(eventLastSeen(aggregate.latest)) {
+		aggregate.latest = event
+	}
+	total := aggregate.total()
+	ew.mu.Unlock()
+
+	ew.stream(event, count, lastSeen, total)
+
+	rule, ok := ew.reasons[event.Reason]
+	if !ok || total < rule.MinCount {
+		return nil
+	}
+
+	alert := BuildEventAlert(event, rule.Severity, total)
+	created, err := ew.stateManager.ProcessAlert(ctx, alert)
+	if err != nil {
+		return err
+	}
+	if created {
+		logger.Warn().
+			Str("kind", object.Kind).
+			Str("object", object.Name).
+			Str("namespace", object.Namespace).
+			Str("reason", event.Reason).
+			Str("severity", alert.Severity).
+			Msg("New event alert created")
+	}
+	return nil
+}
+
+// stream sends the event to the broadcaster as a k8s_event message
+func (ew *EventWatcher) stream(event *corev1.Event, count int32, lastSeen time.Time, total int) {
+	if ew.broadcaster == nil {
+		return
+	}
+
+	component := event.Source.Component
+	if component == "" {
+		component = event.ReportingController
+	}
+
+	if err := ew.broadcaster.BroadcastJSON(WSMessageTypeK8sEvent, &K8sEvent{
+		Namespace:       event.InvolvedObject.Namespace,
+		Kind:            event.InvolvedObject.Kind,
+		Name:            event.InvolvedObject.Name,
+		Reason:          event.Reason,
+		Message:         event.Message,
+		Component:       component,
+		Count:           count,
+		AggregatedCount: total,
+		FirstSeen:       eventFirstSeen(event),
+		LastSeen:        lastSeen,
+	}); err != nil {
+		logger.Error().Err(err).Str("reason", event.Reason).Msg("Failed to stream event")
+	}
+}
+
+// runSweeps periodically drops events older than the window and resolves the alerts of
+// objects that no longer have enough of them. The first sweep waits a window, so alerts
+// raised before a restart resolve only if their events do not recur.
+func (ew *EventWatcher) runSweeps(ctx context.Context) {
+	defer ew.wg.Done()
+
+	interval := eventSweepInterval
+	if ew.window < interval {
+		interval = ew.window
+	}
+
+	timer := time.NewTimer(ew.window)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			ew.sweep(ctx, time.Now())
+			timer.Reset(interval)
+		case <-ew.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sweep expires events older than the window and resolves every event alert whose aggregate
+// no longer fires
+func (ew *EventWatcher) sweep(ctx context.Context, now time.Time) {
+	ew.alertMu.Lock()
+	defer ew.alertMu.Unlock()
+
+	var firing []*models.Alert
+	ew.mu.Lock()
+	for key, aggregate := range ew.aggregates {
+		for eventKey, series := range aggregate.events {
+			series.expire(now.Add(-ew.window))
+			if len(series.sightings) == 0 {
+				delete(aggregate.events, eventKey)
+			}
+		}
+		if len(aggregate.events) == 0 {
+			delete(ew.aggregates, key)
+			continue
+		}
+		if rule, ok := ew.reasons[aggregate.latest.Reason]; ok && aggregate.total() >= rule.MinCount {
+			firing = append(firing, BuildEventAlert(aggregate.latest, rule.Severity, aggregate.total()))
+		}
+	}
+	ew.mu.Unlock()
+
+	// An empty subject covers every event alert, including those raised before a restart
+	if _, err := ew.stateManager.ResolveCleared(ctx, SourceK8sEvent, map[string]string{}, firing); err != nil {
+		logger.Error().Err(err).Msg("Failed to resolve event alerts")
+	}
+}
+
+// Stop gracefully stops the event watcher
+func (ew *EventWatcher) Stop() {
+	close(ew.stopCh)
+	ew.wg.Wait()
+}
+
+// eventCount returns how often the event occurred
+func eventCount(event *corev1.Event) int32 {
+	if event.Series != nil && event.Series.Count > 0 {
+		return event.Series.Count
+	}
+	if event.Count > 0 {
+		return event.Count
+	}
+	return 1
+}
+
+// eventFirstSeen returns when the event first occurred, or the zero time if it does not tell
+func eventFirstSeen(event *corev1.Event) time.Time {
+	if !event.FirstTimestamp.IsZero() {
+		return event.FirstTimestamp.Time
+	}
+	return event.EventTime.Time
+}
+
+// eventLastSeen returns when the event last occurred. Events carry it in different fields
+// depending on the API their reporter used.
+func eventLastSeen(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
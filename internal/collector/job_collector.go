@@ -0,0 +1,304 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	listersbatchv1 "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/pool"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// Batch alerting defaults
+const (
+	defaultJobMaxDuration         = 6 * time.Hour
+	defaultCronJobMissedSchedules = 2
+)
+
+// JobWatcher watches Jobs and CronJobs through shared informers and evaluates them on the
+// worker pool. It alerts on failed and overrunning Jobs and on CronJobs that are suspended or
+// have not succeeded for several scheduled runs.
+type JobWatcher struct {
+	client          *K8sClient
+	jobInformer     cache.SharedIndexInformer
+	cronJobInformer cache.SharedIndexInformer
+	jobs            listersbatchv1.JobLister
+	cronJobs        listersbatchv1.CronJobLister
+	jobQueue        *objectQueue
+	cronJobQueue    *objectQueue
+	stateManager    *processor.AlertStateManager
+	maxDuration     time.Duration
+	missedSchedules int
+	stopCh          chan struct{}
+	wg              sync.WaitGroup
+}
+
+// NewJobWatcher creates a new job watcher. Unset alert rule settings take their defaults.
+func NewJobWatcher(k8sClient *K8sClient, stateManager *processor.AlertStateManager, rules config.AlertRulesConfig, workerPool *pool.WorkerPool) *JobWatcher {
+	batch := k8sClient.GetInformerFactory().Batch().V1()
+	jw := &JobWatcher{
+		client:          k8sClient,
+		jobInformer:     batch.Jobs().Informer(),
+		cronJobInformer: batch.CronJobs().Informer(),
+		jobs:            batch.Jobs().Lister(),
+		cronJobs:        batch.CronJobs().Lister(),
+		stateManager:    stateManager,
+		maxDuration:     rules.JobMaxDuration,
+		missedSchedules: rules.CronJobMissedSchedules,
+		stopCh:          make(chan struct{}),
+	}
+	if jw.maxDuration <= 0 {
+		jw.maxDuration = defaultJobMaxDuration
+	}
+	if jw.missedSchedules <= 0 {
+		jw.missedSchedules = defaultCronJobMissedSchedules
+	}
+	jw.jobQueue = newObjectQueue("job", workerPool, jw.processJob)
+	jw.cronJobQueue = newObjectQueue("cronjob", workerPool, jw.processCronJob)
+	return jw
+}
+
+// Start begins watching jobs and cronjobs. Each informer lists its objects, then watches from
+// that resourceVersion, relisting with backoff whenever the watch breaks.
+func (jw *JobWatcher) Start(ctx context.Context) {
+	logger.Info().
+		Dur("max_duration", jw.maxDuration).
+		Int("missed_schedules", jw.missedSchedules).
+		Msg("Starting Job Watcher with informers and worker pool")
+
+	for _, watched := range []struct {
+		informer cache.SharedIndexInformer
+		queue    *objectQueue
+	}{
+		{jw.jobInformer, jw.jobQueue},
+		{jw.cronJobInformer, jw.cronJobQueue},
+	} {
+		kind := watched.queue.kind
+		if err := watched.informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+			logger.Warn().Err(err).Str("kind", kind).Msg("Batch watch failed, informer will relist with backoff")
+		}); err != nil {
+			logger.Warn().Err(err).Str("kind", kind).Msg("Failed to set batch watch error handler")
+		}
+		if _, err := watched.informer.AddEventHandler(watched.queue.handler()); err != nil {
+			logger.Error().Err(err).Str("kind", kind).Msg("Failed to register batch event handler")
+			return
+		}
+	}
+
+	jw.wg.Add(2)
+	go jw.jobQueue.run(ctx, jw.stopCh, &jw.wg, jw.jobInformer.HasSynced)
+	go jw.cronJobQueue.run(ctx, jw.stopCh, &jw.wg, jw.cronJobInformer.HasSynced)
+}
+
+// processJob evaluates the latest cached state of a job
+func (jw *JobWatcher) processJob(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Warn().Err(err).Str("key", key).Msg("Invalid job key")
+		return nil
+	}
+
+	subject := map[string]string{
+		"namespace": namespace,
+		"job":       name,
+	}
+
+	job, err := jw.jobs.Jobs(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		// A deleted job can no longer be unhealthy - resolve everything firing for it
+		_, err := jw.stateManager.ResolveCleared(ctx, SourceK8sJob, subject, nil)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	var alerts []*models.Alert
+	now := time.Now()
+
+	switch {
+	case jobFailed(job):
+		if !jw.failureSuperseded(job) {
+			alerts = append(alerts, BuildJobAlert(job, AlertTypeJobFailed, float64(job.Status.Failed)))
+		}
+	case jobFinished(job) || job.Status.StartTime == nil || (job.Spec.Suspend != nil && *job.Spec.Suspend):
+	default:
+		running := now.Sub(job.Status.StartTime.Time)
+		if running >= jw.maxDuration {
+			alerts = append(alerts, BuildJobAlert(job, AlertTypeJobRunningTooLong, running.Round(time.Second).Seconds()))
+		} else {
+			// Nothing else may change the job by the time it overruns
+			jw.jobQueue.addAfter(key, jw.maxDuration-running)
+		}
+	}
+
+	jw.processAlerts(ctx, alerts)
+
+	// Resolve alerts whose condition is no longer present
+	_, err = jw.stateManager.ResolveCleared(ctx, SourceK8sJob, subject, alerts)
+	return err
+}
+
+// failureSuperseded reports whether a later run of the job's CronJob has succeeded since it
+// failed. The failed job is kept as history, but the CronJob is healthy again.
+func (jw *JobWatcher) failureSuperseded(job *batchv1.Job) bool {
+	name := getJobCronJob(job)
+	if name == "" {
+		return false
+	}
+	cronJob, err := jw.cronJobs.CronJobs(job.Namespace).Get(name)
+	if err != nil || cronJob.Status.LastSuccessfulTime == nil {
+		return false
+	}
+	return job.Status.StartTime != nil && cronJob.Status.LastSuccessfulTime.After(job.Status.StartTime.Time)
+}
+
+// processCronJob evaluates the latest cached state of a cronjob
+func (jw *JobWatcher) processCronJob(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Warn().Err(err).Str("key", key).Msg("Invalid cronjob key")
+		return nil
+	}
+
+	subject := map[string]string{
+		"namespace": namespace,
+		"cronjob":   name,
+	}
+
+	cronJob, err := jw.cronJobs.CronJobs(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		_, err := jw.stateManager.ResolveCleared(ctx, SourceK8sCronJob, subject, nil)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	var alerts []*models.Alert
+	if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+		// A suspended cronjob is expected not to run, so missed schedules are not reported
+		alerts = append(alerts, BuildCronJobAlert(cronJob, AlertTypeCronJobSuspended, 1))
+	} else if missed, due := jw.missedRuns(cronJob, time.Now()); missed >= jw.missedSchedules {
+		alerts = append(alerts, BuildCronJobAlert(cronJob, AlertTypeCronJobMissedSchedule, float64(missed)))
+	} else if !due.IsZero() {
+		// Nothing else may change the cronjob by the time it misses enough runs
+		jw.cronJobQueue.addAfter(key, time.Until(due))
+	}
+
+	jw.processAlerts(ctx, alerts)
+
+	// A success may supersede failed jobs of the cronjob
+	jw.requeueJobs(cronJob)
+
+	_, err = jw.stateManager.ResolveCleared(ctx, SourceK8sCronJob, subject, alerts)
+	return err
+}
+
+// missedRuns counts, up to the alerting threshold, the runs scheduled since the cronjob last
+// succeeded (or was created). Below the threshold it also returns the next scheduled run, when
+// the count changes.
+func (jw *JobWatcher) missedRuns(cronJob *batchv1.CronJob, now time.Time) (int, time.Time) {
+	location := time.UTC
+	if cronJob.Spec.TimeZone != nil {
+		loaded, err := time.LoadLocation(*cronJob.Spec.TimeZone)
+		if err != nil {
+			logger.Warn().Err(err).Str("cronjob", cronJob.Name).Msg("Unknown cronjob time zone, skipping schedule check")
+			return 0, time.Time{}
+		}
+		location = loaded
+	}
+	schedule, err := models.ParseCron(cronJob.Spec.Schedule)
+	if err != nil {
+		logger.Warn().Err(err).Str("cronjob", cronJob.Name).Msg("Invalid cronjob schedule, skipping schedule check")
+		return 0, time.Time{}
+	}
+
+	// Schedules are evaluated in the cronjob's time zone
+	since := cronJob.CreationTimestamp.Time.In(location)
+	if cronJob.Status.LastSuccessfulTime != nil {
+		since = cronJob.Status.LastSuccessfulTime.Time.In(location)
+	}
+
+	missed := 0
+	for run := schedule.Next(since); !run.IsZero(); run = schedule.Next(run) {
+		if run.After(now) {
+			return missed, run
+		}
+		missed++
+		if missed >= jw.missedSchedules {
+			break
+		}
+	}
+	return missed, time.Time{}
+}
+
+// requeueJobs queues the failed jobs of the cronjob for evaluation
+func (jw *JobWatcher) requeueJobs(cronJob *batchv1.CronJob) {
+	jobs, err := jw.jobs.Jobs(cronJob.Namespace).List(labels.Everything())
+	if err != nil {
+		return
+	}
+	for _, job := range jobs {
+		if getJobCronJob(job) == cronJob.Name && jobFailed(job) {
+			jw.jobQueue.add(job.Namespace + "/" + job.Name)
+		}
+	}
+}
+
+// processAlerts passes each alert through the state manager
+func (jw *JobWatcher) processAlerts(ctx context.Context, alerts []*models.Alert) {
+	for _, alert := range alerts {
+		created, err := jw.stateManager.ProcessAlert(ctx, alert)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("alert_type", alert.GetLabelsMap()["alert_type"]).
+				Msg("Failed to process alert")
+			continue
+		}
+
+		if created {
+			logger.Warn().
+				Str("source", alert.Source).
+				Str("severity", alert.Severity).
+				Str("message", alert.Message).
+				Msg("New batch alert created")
+		}
+	}
+}
+
+// Stop gracefully stops the job watcher
+func (jw *JobWatcher) Stop() {
+	close(jw.stopCh)
+	jw.wg.Wait()
+}
+
+// jobFailed reports whether the job has failed, e.g. by exceeding its backoffLimit
+func jobFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return job.Status.Failed > jobBackoffLimit(job)
+}
+
+// jobFinished reports whether the job has completed or failed
+func jobFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
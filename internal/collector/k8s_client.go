@@ -6,18 +6,28 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	appconfig "github.com/monitoring-engine/monitoring-tool/internal/config"
 )
 
+// defaultResyncPeriod is how often informers replay their cache when no resync period is configured
+const defaultResyncPeriod = 5 * time.Minute
+
 // K8sClient wraps Kubernetes client
 type K8sClient struct {
-	clientset        *kubernetes.Clientset
+	clientset        kubernetes.Interface
 	metricsClientset *metricsclientset.Clientset
 	metricsClient    *MetricsClient
+	kubeletClient    *KubeletClient
+	informerFactory  informers.SharedInformerFactory
+	namespaces       *NamespaceCache
 	stopCh           chan struct{}
 	mu               sync.RWMutex
 }
@@ -39,14 +49,27 @@ func NewK8sClient() (*K8sClient, error) {
 		return nil, fmt.Errorf("failed to create metrics clientset: %w", err)
 	}
 
-	metricsClient := NewMetricsClient(clientset, metricsClientset)
+	resyncPeriod := defaultResyncPeriod
+	if cfg := appconfig.Get(); cfg != nil && cfg.Kubernetes.ResyncPeriod > 0 {
+		resyncPeriod = time.Duration(cfg.Kubernetes.ResyncPeriod) * time.Second
+	}
 
+	k8sClient := NewK8sClientForClientset(clientset, resyncPeriod)
+	k8sClient.metricsClientset = metricsClientset
+	k8sClient.metricsClient = NewMetricsClient(clientset, metricsClientset)
+	k8sClient.kubeletClient = NewKubeletClient(clientset.CoreV1().RESTClient())
+	return k8sClient, nil
+}
+
+// NewK8sClientForClientset creates a K8s client around an existing clientset, without metrics
+// or kubelet access. Informers replay their cache every resyncPeriod so alerts are re-evaluated.
+func NewK8sClientForClientset(clientset kubernetes.Interface, resyncPeriod time.Duration) *K8sClient {
 	return &K8sClient{
-		clientset:        clientset,
-		metricsClientset: metricsClientset,
-		metricsClient:    metricsClient,
-		stopCh:           make(chan struct{}),
-	}, nil
+		clientset:       clientset,
+		informerFactory: informers.NewSharedInformerFactory(clientset, resyncPeriod),
+		namespaces:      NewNamespaceCache(clientset),
+		stopCh:          make(chan struct{}),
+	}
 }
 
 // getKubeConfig returns Kubernetes REST config
@@ -76,15 +99,37 @@ func getKubeConfig() (*rest.Config, error) {
 }
 
 // GetClientset returns the Kubernetes clientset
-func (kc *K8sClient) GetClientset() *kubernetes.Clientset {
+func (kc *K8sClient) GetClientset() kubernetes.Interface {
 	return kc.clientset
 }
 
+// GetInformerFactory returns the shared informer factory watchers build their informers from
+func (kc *K8sClient) GetInformerFactory() informers.SharedInformerFactory {
+	return kc.informerFactory
+}
+
 // GetMetricsClient returns the metrics client
 func (kc *K8sClient) GetMetricsClient() *MetricsClient {
 	return kc.metricsClient
 }
 
+// GetKubeletClient returns the client for the kubelet Summary API
+func (kc *K8sClient) GetKubeletClient() *KubeletClient {
+	return kc.kubeletClient
+}
+
+// GetNamespaceCache returns the cache of namespace annotations
+func (kc *K8sClient) GetNamespaceCache() *NamespaceCache {
+	return kc.namespaces
+}
+
+// StartInformers starts the informers the watchers registered with the shared factory. Call it
+// once, after every watcher has been created: informers run until the client stops, so stopping
+// one watcher never stops an informer another watcher relies on.
+func (kc *K8sClient) StartInformers() {
+	kc.informerFactory.Start(kc.stopCh)
+}
+
 // Start initializes the client
 func (kc *K8sClient) Start(ctx context.Context) {
 	go func() {
@@ -93,15 +138,16 @@ func (kc *K8sClient) Start(ctx context.Context) {
 	}()
 }
 
-// Stop gracefully stops the client
+// Stop gracefully stops the client and waits for its informers to stop
 func (kc *K8sClient) Stop() {
 	kc.mu.Lock()
-	defer kc.mu.Unlock()
 	select {
 	case <-kc.stopCh:
 	default:
 		close(kc.stopCh)
 	}
+	kc.mu.Unlock()
+	kc.informerFactory.Shutdown()
 }
 
 // GetStopChannel returns the stop channel
@@ -0,0 +1,164 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/client-go/rest"
+)
+
+// SummaryProvider fetches the kubelet Summary API of a node
+type SummaryProvider interface {
+	GetNodeSummary(ctx context.Context, nodeName string) (*NodeSummary, error)
+}
+
+// KubeletClient reads the kubelet Summary API through the API server's node proxy, so no
+// direct network access to the kubelets is needed (only the nodes/proxy permission)
+type KubeletClient struct {
+	restClient rest.Interface
+}
+
+// NodeSummary is the part of the kubelet's stats/summary response the collectors read
+type NodeSummary struct {
+	Node NodeStats  `json:"node"`
+	Pods []PodStats `json:"pods"`
+}
+
+// NodeStats identifies the node a summary was read from
+type NodeStats struct {
+	NodeName string `json:"nodeName"`
+}
+
+// PodStats holds the stats of one pod
+type PodStats struct {
+	PodRef           PodReference     `json:"podRef"`
+	Containers       []ContainerStats `json:"containers"`
+	Network          *NetworkStats    `json:"network,omitempty"`
+	VolumeStats      []VolumeStats    `json:"volume,omitempty"`
+	EphemeralStorage *FsStats         `json:"ephemeral-storage,omitempty"`
+}
+
+// ContainerStats holds the stats of one container of a pod
+type ContainerStats struct {
+	Name   string       `json:"name"`
+	CPU    *CPUStats    `json:"cpu,omitempty"`
+	Memory *MemoryStats `json:"memory,omitempty"`
+	Rootfs *FsStats     `json:"rootfs,omitempty"` // the container's writable layer
+	Logs   *FsStats     `json:"logs,omitempty"`
+}
+
+// CPUStats holds CPU usage
+type CPUStats struct {
+	UsageNanoCores *uint64 `json:"usageNanoCores,omitempty"`
+}
+
+// MemoryStats holds memory usage. The working set is what the kubelet compares to the
+// memory limit and eviction thresholds.
+type MemoryStats struct {
+	UsageBytes      *uint64 `json:"usageBytes,omitempty"`
+	WorkingSetBytes *uint64 `json:"workingSetBytes,omitempty"`
+}
+
+// NetworkStats holds the network usage of a pod: its default interface and all interfaces
+type NetworkStats struct {
+	InterfaceStats
+	Interfaces []InterfaceStats `json:"interfaces,omitempty"`
+}
+
+// InterfaceStats holds the cumulative traffic of a network interface
+type InterfaceStats struct {
+	Name    string  `json:"name"`
+	RxBytes *uint64 `json:"rxBytes,omitempty"`
+	TxBytes *uint64 `json:"txBytes,omitempty"`
+}
+
+// PodReference identifies a pod
+type PodReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	UID       string `json:"uid"`
+}
+
+// VolumeStats holds the filesystem stats of a volume mounted by a pod. PVCRef is set for
+// volumes backed by a PersistentVolumeClaim.
+type VolumeStats struct {
+	Name   string        `json:"name"`
+	PVCRef *PVCReference `json:"pvcRef,omitempty"`
+	FsStats
+}
+
+// PVCReference identifies a PersistentVolumeClaim
+type PVCReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// FsStats holds filesystem usage. Values the kubelet could not determine are nil.
+type FsStats struct {
+	AvailableBytes *uint64 `json:"availableBytes,omitempty"`
+	CapacityBytes  *uint64 `json:"capacityBytes,omitempty"`
+	UsedBytes      *uint64 `json:"usedBytes,omitempty"`
+	InodesFree     *uint64 `json:"inodesFree,omitempty"`
+	Inodes         *uint64 `json:"inodes,omitempty"`
+	InodesUsed     *uint64 `json:"inodesUsed,omitempty"`
+}
+
+// NewKubeletClient creates a kubelet client using the REST client of the core API group
+func NewKubeletClient(restClient rest.Interface) *KubeletClient {
+	return &KubeletClient{restClient: restClient}
+}
+
+// GetNodeSummary retrieves the stats summary of a node from its kubelet
+func (kc *KubeletClient) GetNodeSummary(ctx context.Context, nodeName string) (*NodeSummary, error) {
+	raw, err := kc.restClient.Get().
+		AbsPath("/api/v1/nodes", nodeName, "proxy", "stats", "summary").
+		DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node summary: %w", err)
+	}
+
+	var summary NodeSummary
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return nil, fmt.Errorf("failed to decode node summary: %w", err)
+	}
+	return &summary, nil
+}
+
+// UsedPercent returns the percentage of the filesystem's capacity in use, if known
+func (fs FsStats) UsedPercent() (float64, bool) {
+	return percentOf(fs.UsedBytes, fs.CapacityBytes)
+}
+
+// InodesUsedPercent returns the percentage of the filesystem's inodes in use, if known
+func (fs FsStats) InodesUsedPercent() (float64, bool) {
+	return percentOf(fs.InodesUsed, fs.Inodes)
+}
+
+// TotalBytes returns the bytes received and sent across all interfaces of the pod
+func (n *NetworkStats) TotalBytes() (rx, tx uint64) {
+	interfaces := n.Interfaces
+	if len(interfaces) == 0 {
+		interfaces = []InterfaceStats{n.InterfaceStats}
+	}
+	for _, iface := range interfaces {
+		rx += valueOf(iface.RxBytes)
+		tx += valueOf(iface.TxBytes)
+	}
+	return rx, tx
+}
+
+// valueOf returns the stat, or 0 if the kubelet did not report it
+func valueOf(stat *uint64) uint64 {
+	if stat == nil {
+		return 0
+	}
+	return *stat
+}
+
+func percentOf(used, total *uint64) (float64, bool) {
+	if used == nil || total == nil || *total == 0 {
+		return 0, false
+	}
+	return float64(*used) / float64(*total) * 100, true
+}
@@ -0,0 +1,91 @@
+package collector_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/collector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const nodeSummaryJSON = `{
+  "node": {"nodeName": "worker-1"},
+  "pods": [{
+    "podRef": {"name": "postgres-0", "namespace": "db", "uid": "abc"},
+    "containers": [{
+      "name": "postgres",
+      "cpu": {"usageNanoCores": 250000000},
+      "memory": {"usageBytes": 600, "workingSetBytes": 500},
+      "rootfs": {"usedBytes": 40},
+      "logs": {"usedBytes": 10}
+    }],
+    "network": {"name": "eth0", "rxBytes": 100, "txBytes": 50,
+      "interfaces": [{"name": "eth0", "rxBytes": 100, "txBytes": 50}, {"name": "net1", "rxBytes": 20, "txBytes": 5}]},
+    "volume": [
+      {"name": "data", "pvcRef": {"name": "data-postgres-0", "namespace": "db"},
+       "capacityBytes": 1000, "usedBytes": 900, "availableBytes": 100, "inodes": 200, "inodesUsed": 50, "inodesFree": 150},
+      {"name": "kube-api-access", "capacityBytes": 100, "usedBytes": 1}
+    ]
+  }]
+}`
+
+func TestKubeletClient(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if r.URL.Path != "/api/v1/nodes/worker-1/proxy/stats/summary" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(nodeSummaryJSON))
+	}))
+	t.Cleanup(server.Close)
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+	client := collector.NewKubeletClient(clientset.CoreV1().RESTClient())
+
+	t.Run("should read the summary through the node proxy", func(t *testing.T) {
+		summary, err := client.GetNodeSummary(context.Background(), "worker-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, "/api/v1/nodes/worker-1/proxy/stats/summary", path)
+		assert.Equal(t, "worker-1", summary.Node.NodeName)
+		require.Len(t, summary.Pods, 1)
+		require.Len(t, summary.Pods[0].VolumeStats, 2)
+
+		data := summary.Pods[0].VolumeStats[0]
+		assert.Equal(t, "data-postgres-0", data.PVCRef.Name)
+		used, ok := data.UsedPercent()
+		assert.True(t, ok)
+		assert.InDelta(t, 90, used, 0.001)
+		inodes, ok := data.InodesUsedPercent()
+		assert.True(t, ok)
+		assert.InDelta(t, 25, inodes, 0.001)
+
+		require.Len(t, summary.Pods[0].Containers, 1)
+		container := summary.Pods[0].Containers[0]
+		assert.Equal(t, uint64(250000000), *container.CPU.UsageNanoCores)
+		assert.Equal(t, uint64(500), *container.Memory.WorkingSetBytes)
+		assert.Equal(t, uint64(10), *container.Logs.UsedBytes)
+		rx, tx := summary.Pods[0].Network.TotalBytes()
+		assert.Equal(t, uint64(120), rx)
+		assert.Equal(t, uint64(55), tx)
+
+		// Projected volumes have no claim, and their inodes are unknown
+		assert.Nil(t, summary.Pods[0].VolumeStats[1].PVCRef)
+		_, ok = summary.Pods[0].VolumeStats[1].InodesUsedPercent()
+		assert.False(t, ok)
+	})
+
+	t.Run("should return an error for unreachable nodes", func(t *testing.T) {
+		_, err := client.GetNodeSummary(context.Background(), "worker-2")
+		assert.Error(t, err)
+	})
+}
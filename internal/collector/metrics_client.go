@@ -12,7 +12,7 @@ import (
 
 // MetricsClient wraps Kubernetes metrics API client
 type MetricsClient struct {
-	clientset        *kubernetes.Clientset
+	clientset        kubernetes.Interface
 	metricsClientset *metricsclientset.Clientset
 }
 
@@ -26,6 +26,7 @@ type PodMetrics struct {
 	MemoryUsagePercent float64
 	CPURequestMillis   int64
 	MemoryRequestBytes int64
+	Annotations        map[string]string // pod annotations, for threshold overrides
 }
 
 // NodeMetrics represents CPU and memory usage for a node
@@ -37,10 +38,11 @@ type NodeMetrics struct {
 	MemoryUsagePercent float64
 	CPUCapacityMillis  int64
 	MemoryCapacityBytes int64
+	Annotations        map[string]string // node annotations, for threshold overrides
 }
 
 // NewMetricsClient creates a new metrics client
-func NewMetricsClient(clientset *kubernetes.Clientset, metricsClientset *metricsclientset.Clientset) *MetricsClient {
+func NewMetricsClient(clientset kubernetes.Interface, metricsClientset *metricsclientset.Clientset) *MetricsClient {
 	return &MetricsClient{
 		clientset:        clientset,
 		metricsClientset: metricsClientset,
@@ -105,6 +107,7 @@ func (mc *MetricsClient) GetPodMetrics(ctx context.Context, namespace, podName s
 		MemoryUsagePercent: memPercent,
 		CPURequestMillis:   totalCPURequest,
 		MemoryRequestBytes: totalMemoryRequest,
+		Annotations:        pod.Annotations,
 	}, nil
 }
 
@@ -138,6 +141,7 @@ func (mc *MetricsClient) GetNodeMetrics(ctx context.Context, nodeName string) (*
 		MemoryUsagePercent:  memPercent,
 		CPUCapacityMillis:   cpuCapacity,
 		MemoryCapacityBytes: memCapacity,
+		Annotations:         node.Annotations,
 	}, nil
 }
 
@@ -7,26 +7,26 @@ import (
 
 	"github.com/monitoring-engine/monitoring-tool/internal/config"
 	"github.com/monitoring-engine/monitoring-tool/internal/processor"
-	
 	"github.com/monitoring-engine/monitoring-tool/internal/logger"
 	"github.com/monitoring-engine/monitoring-tool/internal/pool"
 )
 
 // MetricsWatcher watches pod and node metrics and generates alerts
 type MetricsWatcher struct {
-	client        *K8sClient
-	stateManager  *processor.AlertStateManager
-	workerPool    *pool.WorkerPool
-	interval      time.Duration
-	thresholds    *config.AlertRulesConfig
-	stopCh        chan struct{}
-	wg            sync.WaitGroup
+	client       *K8sClient
+	stateManager *processor.AlertStateManager
+	ruleEngine   *processor.RuleEngine
+	workerPool   *pool.WorkerPool
+	interval     time.Duration
+	stopCh       chan struct{}
+	wg           sync.WaitGroup
 }
 
 // NewMetricsWatcher creates a new metrics watcher
 func NewMetricsWatcher(
 	client *K8sClient,
 	stateManager *processor.AlertStateManager,
+	ruleEngine *processor.RuleEngine,
 	workerPool *pool.WorkerPool,
 ) *MetricsWatcher {
 	cfg := config.Get()
@@ -38,9 +38,9 @@ func NewMetricsWatcher(
 	return &MetricsWatcher{
 		client:       client,
 		stateManager: stateManager,
+		ruleEngine:   ruleEngine,
 		workerPool:   workerPool,
 		interval:     interval,
-		thresholds:   &cfg.AlertRules,
 		stopCh:       make(chan struct{}),
 	}
 }
@@ -115,44 +115,28 @@ func (mw *MetricsWatcher) checkPodMetrics(ctx context.Context) error {
 			continue
 		}
 
-		// Check CPU threshold
-		if metrics.CPURequestMillis > 0 && metrics.CPUUsagePercent > mw.thresholds.PodCPUPercent {
-			alert := BuildPodMetricAlert(
-				metrics.Namespace,
-				metrics.PodName,
-				AlertTypePodCPUHigh,
-				metrics.CPUUsagePercent,
-				mw.thresholds.PodCPUPercent,
-			)
-
-			if _, err := mw.stateManager.ProcessAlert(ctx, alert); err != nil {
-				logger.Error().Err(err).Str("pod", metrics.PodName).Msg("Failed to create pod CPU alert")
-			} else {
+		// Check CPU and memory thresholds
+		obs := ObservePodMetrics(metrics)
+		obs.NamespaceAnnotations = mw.client.GetNamespaceCache().Annotations(ctx, metrics.Namespace)
+		active := mw.ruleEngine.Evaluate(obs)
+		pending, _ := mw.ruleEngine.PendingFor(obs)
+		for _, alert := range active {
+			if created, err := mw.stateManager.ProcessAlert(ctx, alert); err != nil {
+				logger.Error().Err(err).Str("pod", metrics.PodName).Msg("Failed to create pod metric alert")
+			} else if created {
 				logger.Info().
 					Str("pod", metrics.PodName).
-					Float64("cpu_percent", metrics.CPUUsagePercent).
-					Msg("Pod CPU alert created")
+					Str("metric", alert.GetLabelsMap()["metric"]).
+					Float64("percent", alert.Value).
+					Msg("Pod metric alert created")
 			}
 		}
 
-		// Check Memory threshold
-		if metrics.MemoryRequestBytes > 0 && metrics.MemoryUsagePercent > mw.thresholds.PodMemoryPercent {
-			alert := BuildPodMetricAlert(
-				metrics.Namespace,
-				metrics.PodName,
-				AlertTypePodMemoryHigh,
-				metrics.MemoryUsagePercent,
-				mw.thresholds.PodMemoryPercent,
-			)
-
-			if _, err := mw.stateManager.ProcessAlert(ctx, alert); err != nil {
-				logger.Error().Err(err).Str("pod", metrics.PodName).Msg("Failed to create pod memory alert")
-			} else {
-				logger.Info().
-					Str("pod", metrics.PodName).
-					Float64("memory_percent", metrics.MemoryUsagePercent).
-					Msg("Pod memory alert created")
-			}
+		// Resolve CPU/memory alerts that dropped back below threshold. Usage still above it for
+		// less than its rule's duration keeps an alert already firing, e.g. after a restart.
+		subject := map[string]string{"namespace": metrics.Namespace, "pod": metrics.PodName}
+		if _, err := mw.stateManager.ResolveCleared(ctx, SourceK8sPodMetrics, subject, append(active, pending...)); err != nil {
+			logger.Error().Err(err).Str("pod", metrics.PodName).Msg("Failed to resolve pod metric alerts")
 		}
 	}
 
@@ -171,42 +155,26 @@ func (mw *MetricsWatcher) checkNodeMetrics(ctx context.Context) error {
 	logger.Info().Int("node_count", len(nodeMetrics)).Msg("Checking node metrics")
 
 	for _, metrics := range nodeMetrics {
-		// Check CPU threshold
-		if metrics.CPUUsagePercent > mw.thresholds.NodeCPUPercent {
-			alert := BuildNodeMetricAlert(
-				metrics.NodeName,
-				AlertTypeNodeCPUHigh,
-				metrics.CPUUsagePercent,
-				mw.thresholds.NodeCPUPercent,
-			)
-
-			if _, err := mw.stateManager.ProcessAlert(ctx, alert); err != nil {
-				logger.Error().Err(err).Str("node", metrics.NodeName).Msg("Failed to create node CPU alert")
-			} else {
+		// Check CPU and memory thresholds
+		obs := ObserveNodeMetrics(metrics)
+		active := mw.ruleEngine.Evaluate(obs)
+		pending, _ := mw.ruleEngine.PendingFor(obs)
+		for _, alert := range active {
+			if created, err := mw.stateManager.ProcessAlert(ctx, alert); err != nil {
+				logger.Error().Err(err).Str("node", metrics.NodeName).Msg("Failed to create node metric alert")
+			} else if created {
 				logger.Info().
 					Str("node", metrics.NodeName).
-					Float64("cpu_percent", metrics.CPUUsagePercent).
-					Msg("Node CPU alert created")
+					Str("metric", alert.GetLabelsMap()["metric"]).
+					Float64("percent", alert.Value).
+					Msg("Node metric alert created")
 			}
 		}
 
-		// Check Memory threshold
-		if metrics.MemoryUsagePercent > mw.thresholds.NodeMemoryPercent {
-			alert := BuildNodeMetricAlert(
-				metrics.NodeName,
-				AlertTypeNodeMemoryHigh,
-				metrics.MemoryUsagePercent,
-				mw.thresholds.NodeMemoryPercent,
-			)
-
-			if _, err := mw.stateManager.ProcessAlert(ctx, alert); err != nil {
-				logger.Error().Err(err).Str("node", metrics.NodeName).Msg("Failed to create node memory alert")
-			} else {
-				logger.Info().
-					Str("node", metrics.NodeName).
-					Float64("memory_percent", metrics.MemoryUsagePercent).
-					Msg("Node memory alert created")
-			}
+		// Resolve CPU/memory alerts that dropped back below threshold, keeping those still pending
+		subject := map[string]string{"node": metrics.NodeName}
+		if _, err := mw.stateManager.ResolveCleared(ctx, SourceK8sNodeMetrics, subject, append(active, pending...)); err != nil {
+			logger.Error().Err(err).Str("node", metrics.NodeName).Msg("Failed to resolve node metric alerts")
 		}
 	}
 
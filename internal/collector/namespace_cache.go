@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+)
+
+// namespaceCacheTTL is how long namespace annotations are reused before they are fetched again
+const namespaceCacheTTL = time.Minute
+
+// NamespaceCache caches namespace annotations so every pod event does not fetch its namespace
+type NamespaceCache struct {
+	fetch   func(ctx context.Context, name string) (map[string]string, error)
+	ttl     time.Duration
+	entries map[string]namespaceCacheEntry
+	mu      sync.Mutex
+}
+
+type namespaceCacheEntry struct {
+	annotations map[string]string
+	fetchedAt   time.Time
+}
+
+// NewNamespaceCache creates a namespace cache that fetches namespaces with the clientset
+func NewNamespaceCache(clientset kubernetes.Interface) *NamespaceCache {
+	return NewNamespaceCacheWithFetcher(func(ctx context.Context, name string) (map[string]string, error) {
+		namespace, err := clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return namespace.Annotations, nil
+	}, namespaceCacheTTL)
+}
+
+// NewNamespaceCacheWithFetcher creates a namespace cache with a custom fetch function
+func NewNamespaceCacheWithFetcher(fetch func(ctx context.Context, name string) (map[string]string, error), ttl time.Duration) *NamespaceCache {
+	return &NamespaceCache{
+		fetch:   fetch,
+		ttl:     ttl,
+		entries: make(map[string]namespaceCacheEntry),
+	}
+}
+
+// Annotations returns the namespace's annotations. If they cannot be fetched the last known
+// annotations are returned, or none.
+func (nc *NamespaceCache) Annotations(ctx context.Context, name string) map[string]string {
+	if nc == nil || name == "" {
+		return nil
+	}
+
+	nc.mu.Lock()
+	entry, ok := nc.entries[name]
+	nc.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < nc.ttl {
+		return entry.annotations
+	}
+
+	annotations, err := nc.fetch(ctx, name)
+	if err != nil {
+		logger.Warn().Err(err).Str("namespace", name).Msg("Failed to fetch namespace annotations")
+		return entry.annotations
+	}
+
+	nc.mu.Lock()
+	nc.entries[name] = namespaceCacheEntry{annotations: annotations, fetchedAt: time.Now()}
+	nc.mu.Unlock()
+	return annotations
+}
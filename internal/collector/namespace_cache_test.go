@@ -0,0 +1,51 @@
+package collector_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/collector"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceCache(t *testing.T) {
+	t.Run("should reuse annotations until the TTL expires", func(t *testing.T) {
+		fetches := 0
+		cache := collector.NewNamespaceCacheWithFetcher(func(ctx context.Context, name string) (map[string]string, error) {
+			fetches++
+			return map[string]string{"monitoring-tool/cpu-threshold": "100"}, nil
+		}, 20*time.Millisecond)
+
+		ctx := context.Background()
+		assert.Equal(t, "100", cache.Annotations(ctx, "batch")["monitoring-tool/cpu-threshold"])
+		cache.Annotations(ctx, "batch")
+		assert.Equal(t, 1, fetches)
+
+		time.Sleep(30 * time.Millisecond)
+		cache.Annotations(ctx, "batch")
+		assert.Equal(t, 2, fetches)
+	})
+
+	t.Run("should keep the last known annotations when fetching fails", func(t *testing.T) {
+		fail := false
+		cache := collector.NewNamespaceCacheWithFetcher(func(ctx context.Context, name string) (map[string]string, error) {
+			if fail {
+				return nil, errors.New("api unavailable")
+			}
+			return map[string]string{"monitoring-tool/disabled-alerts": "pod_cpu_high"}, nil
+		}, 0)
+
+		ctx := context.Background()
+		cache.Annotations(ctx, "batch")
+		fail = true
+		assert.Equal(t, "pod_cpu_high", cache.Annotations(ctx, "batch")["monitoring-tool/disabled-alerts"])
+		assert.Nil(t, cache.Annotations(ctx, "unknown"))
+	})
+
+	t.Run("should return nothing for pods without a namespace or a nil cache", func(t *testing.T) {
+		var cache *collector.NamespaceCache
+		assert.Nil(t, cache.Annotations(context.Background(), "batch"))
+	})
+}
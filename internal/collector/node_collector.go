@@ -6,161 +6,92 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/watch"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 
-	"github.com/monitoring-engine/monitoring-tool/internal/processor"
-	"github.com/monitoring-engine/monitoring-tool/internal/models"
 	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
 	"github.com/monitoring-engine/monitoring-tool/internal/pool"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
 )
 
-// NodeEvent represents a node event
-type NodeEvent struct {
-	Type      watch.EventType
-	Node      *corev1.Node
-	Timestamp time.Time
-}
-
-// NodeWatcher watches node events using worker pool
+// NodeWatcher watches nodes through a shared informer and evaluates them on the worker pool
 type NodeWatcher struct {
 	client       *K8sClient
-	eventChan    chan *NodeEvent
+	informer     cache.SharedIndexInformer
+	lister       listersv1.NodeLister
+	queue        *objectQueue
 	stateManager *processor.AlertStateManager
-	workerPool   *pool.WorkerPool
+	ruleEngine   *processor.RuleEngine
+	maintenance  *processor.MaintenanceSuppressor // optional; told about cordoned nodes
 	stopCh       chan struct{}
 	wg           sync.WaitGroup
 }
 
-func NewNodeWatcher(k8sClient *K8sClient, stateManager *processor.AlertStateManager, workerPool *pool.WorkerPool) *NodeWatcher {
-	return &NodeWatcher{
+func NewNodeWatcher(k8sClient *K8sClient, stateManager *processor.AlertStateManager, ruleEngine *processor.RuleEngine, maintenance *processor.MaintenanceSuppressor, workerPool *pool.WorkerPool) *NodeWatcher {
+	nodeInformer := k8sClient.GetInformerFactory().Core().V1().Nodes()
+	nw := &NodeWatcher{
 		client:       k8sClient,
-		eventChan:    make(chan *NodeEvent, 300),
+		informer:     nodeInformer.Informer(),
+		lister:       nodeInformer.Lister(),
 		stateManager: stateManager,
-		workerPool:   workerPool,
+		ruleEngine:   ruleEngine,
+		maintenance:  maintenance,
 		stopCh:       make(chan struct{}),
 	}
+	nw.queue = newObjectQueue("node", workerPool, nw.processNode)
+	return nw
 }
 
+// Start begins watching nodes. The informer lists all nodes, then watches from that
+// resourceVersion, relisting with backoff whenever the watch breaks.
 func (nw *NodeWatcher) Start(ctx context.Context) {
-	logger.Info().Msg("Starting Node Watcher with worker pool")
+	logger.Info().Msg("Starting Node Watcher with informer and worker pool")
 
-	// Start event dispatcher that submits to worker pool
-	nw.wg.Add(1)
-	go nw.eventDispatcher(ctx)
+	if err := nw.informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		logger.Warn().Err(err).Msg("Node watch failed, informer will relist with backoff")
+	}); err != nil {
+		logger.Warn().Err(err).Msg("Failed to set node watch error handler")
+	}
+	if _, err := nw.informer.AddEventHandler(nw.queue.handler()); err != nil {
+		logger.Error().Err(err).Msg("Failed to register node event handler")
+		return
+	}
 
-	// Start real K8s node watcher
 	nw.wg.Add(1)
-	go nw.watchNodes(ctx)
+	go nw.queue.run(ctx, nw.stopCh, &nw.wg, nw.informer.HasSynced)
 }
 
-// watchNodes watches for node events from Kubernetes API
-func (nw *NodeWatcher) watchNodes(ctx context.Context) {
-	defer nw.wg.Done()
+// processNode evaluates the latest cached state of a node with detailed alert categorization
+func (nw *NodeWatcher) processNode(ctx context.Context, key string) error {
+	subject := map[string]string{"node": key}
 
-	clientset := nw.client.GetClientset()
-
-	for {
-		select {
-		case <-nw.stopCh:
-			return
-		case <-ctx.Done():
-			return
-		default:
-		}
-
-		// Watch all nodes
-		watcher, err := clientset.CoreV1().Nodes().Watch(ctx, metav1.ListOptions{})
-		if err != nil {
-			logger.Error().Err(err).Msg("Failed to create node watcher, retrying in 5s")
-			time.Sleep(5 * time.Second)
-			continue
-		}
-
-		logger.Info().Msg("Node watcher connected to Kubernetes API")
-
-		// Process watch events
-		func() {
-			defer watcher.Stop()
-
-			for {
-				select {
-				case event, ok := <-watcher.ResultChan():
-					if !ok {
-						logger.Warn().Msg("Node watch channel closed, reconnecting...")
-						return
-					}
-
-					node, ok := event.Object.(*corev1.Node)
-					if !ok {
-						logger.Warn().Msg("Received non-node object from watch")
-						continue
-					}
-
-					nodeEvent := &NodeEvent{
-						Type:      event.Type,
-						Node:      node,
-						Timestamp: time.Now(),
-					}
-
-					select {
-					case nw.eventChan <- nodeEvent:
-						logger.Debug().
-							Str("type", string(event.Type)).
-							Str("node", node.Name).
-							Msg("Received node event")
-					default:
-						logger.Warn().Msg("Node event channel full, dropping event")
-					}
-
-				case <-nw.stopCh:
-					return
-				case <-ctx.Done():
-					return
-				}
-			}
-		}()
-	}
-}
+	node, err := nw.lister.Get(key)
+	if apierrors.IsNotFound(err) {
+		nw.trackCordon(key, false)
 
-// eventDispatcher reads events and submits them to worker pool
-func (nw *NodeWatcher) eventDispatcher(ctx context.Context) {
-	defer nw.wg.Done()
-
-	for {
-		select {
-		case event := <-nw.eventChan:
-			// Submit event processing to worker pool
-			eventCopy := event // Capture for closure
-			if err := nw.workerPool.SubmitWithContext(ctx, func(ctx context.Context) error {
-				return nw.processEvent(ctx, eventCopy)
-			}); err != nil {
-				logger.Warn().Err(err).
-					Str("node", event.Node.Name).
-					Msg("Failed to submit node event to worker pool (queue full)")
+		// A deleted node can no longer be unhealthy - resolve everything firing for it
+		for _, source := range []string{SourceK8sNode, SourceK8sNodeMetrics} {
+			nw.ruleEngine.Forget(source, subject)
+			if _, err := nw.stateManager.ResolveCleared(ctx, source, subject, nil); err != nil {
+				return err
 			}
-
-		case <-nw.stopCh:
-			logger.Info().Msg("Node event dispatcher stopped")
-			return
-
-		case <-ctx.Done():
-			return
 		}
+		return nil
+	}
+	if err != nil {
+		return err
 	}
-}
-
-// processEvent handles a single node event with detailed alert categorization
-func (nw *NodeWatcher) processEvent(ctx context.Context, event *NodeEvent) error {
-	node := event.Node
 
 	logger.Debug().
-		Str("type", string(event.Type)).
 		Str("node", node.Name).
-		Msg("Processing node event")
+		Msg("Processing node")
+
+	nw.trackCordon(node.Name, node.Spec.Unschedulable)
 
 	// Check for different types of critical conditions
-	alerts := nw.evaluateNodeConditions(node)
+	alerts, pending := nw.evaluateNodeConditions(node)
 
 	// Process each alert through the state manager
 	for _, alert := range alerts {
@@ -182,46 +113,45 @@ func (nw *NodeWatcher) processEvent(ctx context.Context, event *NodeEvent) error
 		}
 	}
 
-	return nil
+	// Resolve alerts whose condition is no longer present (e.g. Ready=True again)
+	_, err = nw.stateManager.ResolveCleared(ctx, SourceK8sNode, subject, append(alerts, pending...))
+	return err
 }
 
-// evaluateNodeConditions checks node for various critical conditions and returns alerts
-func (nw *NodeWatcher) evaluateNodeConditions(node *corev1.Node) []*models.Alert {
-	var alerts []*models.Alert
-
-	for _, condition := range node.Status.Conditions {
-		switch condition.Type {
-		case corev1.NodeReady:
-			// Node is NOT ready
-			if condition.Status != corev1.ConditionTrue {
-				alert := BuildNodeAlert(node, AlertTypeNodeNotReady, 1.0)
-				alerts = append(alerts, alert)
-			}
-
-		case corev1.NodeMemoryPressure:
-			// Node has memory pressure
-			if condition.Status == corev1.ConditionTrue {
-				alert := BuildNodeAlert(node, AlertTypeNodeMemoryPressure, 1.0)
-				alerts = append(alerts, alert)
-			}
+// trackCordon reports cordoned nodes as implicit maintenance so alerts raised while
+// they are drained or rebooted are recorded but not notified
+func (nw *NodeWatcher) trackCordon(node string, cordoned bool) {
+	if nw.maintenance == nil {
+		return
+	}
 
-		case corev1.NodeDiskPressure:
-			// Node has disk pressure
-			if condition.Status == corev1.ConditionTrue {
-				alert := BuildNodeAlert(node, AlertTypeNodeDiskPressure, 1.0)
-				alerts = append(alerts, alert)
-			}
+	if cordoned == nw.maintenance.IsNodeCordoned(node) {
+		return
+	}
+	nw.maintenance.SetNodeCordoned(node, cordoned)
 
-		case corev1.NodePIDPressure:
-			// Node has PID pressure
-			if condition.Status == corev1.ConditionTrue {
-				alert := BuildNodeAlert(node, AlertTypeNodePIDPressure, 1.0)
-				alerts = append(alerts, alert)
-			}
-		}
+	status := TargetStatusActive
+	if cordoned {
+		status = TargetStatusMaintenance
 	}
+	logger.Info().
+		Str("node", node).
+		Str("status", status).
+		Msg("Node maintenance status changed")
+}
 
-	return alerts
+// evaluateNodeConditions evaluates the alert rules against the node and returns the alerts that fire
+// and those still pending for their rule's duration, which keep an alert already firing for them.
+// The node is evaluated again once a pending condition is due to fire.
+func (nw *NodeWatcher) evaluateNodeConditions(node *corev1.Node) ([]*models.Alert, []*models.Alert) {
+	obs := ObserveNode(node)
+
+	alerts := nw.ruleEngine.Evaluate(obs)
+	pending, firesAt := nw.ruleEngine.PendingFor(obs)
+	if !firesAt.IsZero() {
+		nw.queue.addAfter(node.Name, time.Until(firesAt))
+	}
+	return alerts, pending
 }
 
 func (nw *NodeWatcher) Stop() {
@@ -0,0 +1,141 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/pool"
+)
+
+// Workqueue retry settings for objects whose processing fails
+const (
+	queueBaseDelay  = time.Second
+	queueMaxDelay   = 5 * time.Minute
+	queueMaxRetries = 5
+)
+
+// objectQueue feeds informer events to the worker pool through a rate-limited workqueue.
+// Events are queued by object key, so a burst of updates to one object coalesces into a
+// single evaluation of its latest cached state, and failed or unsubmittable keys are
+// retried with exponential backoff instead of being dropped.
+type objectQueue struct {
+	kind       string // object kind for logs
+	queue      workqueue.TypedRateLimitingInterface[string]
+	workerPool *pool.WorkerPool
+	process    func(ctx context.Context, key string) error
+}
+
+func newObjectQueue(kind string, workerPool *pool.WorkerPool, process func(ctx context.Context, key string) error) *objectQueue {
+	return &objectQueue{
+		kind: kind,
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[string](queueBaseDelay, queueMaxDelay),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: kind},
+		),
+		workerPool: workerPool,
+		process:    process,
+	}
+}
+
+// handler returns informer event handlers that queue the key of every added, updated
+// (including resyncs) and deleted object
+func (q *objectQueue) handler() cache.ResourceEventHandlerFuncs {
+	enqueue := func(obj interface{}) {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil {
+			logger.Warn().Err(err).Str("kind", q.kind).Msg("Failed to get object key")
+			return
+		}
+		q.queue.Add(key)
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		UpdateFunc: func(_, newObj interface{}) { enqueue(newObj) },
+		DeleteFunc: enqueue,
+	}
+}
+
+// add queues the key, e.g. when a related object changed
+func (q *objectQueue) add(key string) {
+	q.queue.Add(key)
+}
+
+// addAfter queues the key again once the delay has passed, e.g. when a condition becomes due
+func (q *objectQueue) addAfter(key string, delay time.Duration) {
+	q.queue.AddAfter(key, delay)
+}
+
+// run waits for the informer cache to sync and then submits queued keys to the worker pool
+// until the context is cancelled or stopCh is closed
+func (q *objectQueue) run(ctx context.Context, stopCh <-chan struct{}, wg *sync.WaitGroup, hasSynced cache.InformerSynced) {
+	defer wg.Done()
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stopCh:
+		}
+		q.queue.ShutDown()
+		close(done)
+	}()
+
+	if !cache.WaitForCacheSync(done, hasSynced) {
+		logger.Warn().Str("kind", q.kind).Msg("Stopped before informer cache synced")
+		return
+	}
+	logger.Info().Str("kind", q.kind).Msg("Informer cache synced")
+
+	for {
+		key, shutdown := q.queue.Get()
+		if shutdown {
+			return
+		}
+
+		if err := q.workerPool.SubmitWithContext(ctx, func(ctx context.Context) error {
+			return q.handle(ctx, key)
+		}); err != nil {
+			// Keep the key and try again later rather than losing the event
+			logger.Warn().Err(err).
+				Str("kind", q.kind).
+				Str("key", key).
+				Msg("Failed to submit object to worker pool, requeueing")
+			q.queue.Done(key)
+			q.queue.AddRateLimited(key)
+		}
+	}
+}
+
+// handle processes one key and requeues it with backoff if processing fails
+func (q *objectQueue) handle(ctx context.Context, key string) error {
+	defer q.queue.Done(key)
+
+	err := q.process(ctx, key)
+	if err == nil {
+		q.queue.Forget(key)
+		return nil
+	}
+
+	if q.queue.NumRequeues(key) < queueMaxRetries {
+		logger.Warn().Err(err).
+			Str("kind", q.kind).
+			Str("key", key).
+			Msg("Failed to process object, retrying with backoff")
+		q.queue.AddRateLimited(key)
+		return err
+	}
+
+	logger.Error().Err(err).
+		Str("kind", q.kind).
+		Str("key", key).
+		Int("retries", queueMaxRetries).
+		Msg("Giving up processing object until its next change")
+	q.queue.Forget(key)
+	return err
+}
@@ -0,0 +1,343 @@
+package collector
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// ObservePod builds the rule engine observation for a pod
+func ObservePod(pod *corev1.Pod) *processor.Observation {
+	obs := &processor.Observation{
+		Source:      SourceK8sPod,
+		Namespace:   pod.Namespace,
+		Pod:         pod.Name,
+		Node:        pod.Spec.NodeName,
+		Labels:      pod.Labels,
+		Annotations: pod.Annotations,
+	}
+
+	phase := processor.Sample{
+		Signal:  processor.SignalPodPhase,
+		Text:    string(pod.Status.Phase),
+		Value:   1,
+		Reason:  pod.Status.Reason,
+		Message: pod.Status.Message,
+	}
+	// Pending is the only phase whose duration rules look at; it starts when the pod is created
+	if pod.Status.Phase == corev1.PodPending {
+		phase.Since = pod.CreationTimestamp.Time
+	}
+	// A failed pod of a Job is reported with the Job it ran for
+	if job := getPodJob(pod); job != "" {
+		phase.Labels = map[string]string{"job": job}
+	}
+	obs.Samples = append(obs.Samples, phase)
+
+	var totalRestarts int32
+	for _, cs := range pod.Status.ContainerStatuses {
+		totalRestarts += cs.RestartCount
+
+		if waiting := cs.State.Waiting; waiting != nil {
+			labels := map[string]string{"container": cs.Name}
+			// Crash loop alerts have always been labelled with the back-off message; other
+			// waiting reasons keep their message in the alert message only
+			if waiting.Reason == "CrashLoopBackOff" {
+				labels["reason"] = waiting.Message
+			}
+			obs.Samples = append(obs.Samples, processor.Sample{
+				Signal:  processor.SignalContainerWaitingReason,
+				Key:     cs.Name,
+				Text:    waiting.Reason,
+				Value:   float64(cs.RestartCount),
+				Reason:  waiting.Reason,
+				Message: waiting.Message,
+				Labels:  labels,
+			})
+		}
+
+		if terminated := cs.LastTerminationState.Terminated; terminated != nil {
+			obs.Samples = append(obs.Samples, processor.Sample{
+				Signal:  processor.SignalContainerTerminatedReason,
+				Key:     cs.Name,
+				Text:    terminated.Reason,
+				Value:   float64(cs.RestartCount),
+				Since:   terminated.FinishedAt.Time,
+				Reason:  terminated.Reason,
+				Message: terminated.Message,
+				Labels:  map[string]string{"container": cs.Name},
+			})
+		}
+	}
+
+	obs.Samples = append(obs.Samples, processor.Sample{
+		Signal: processor.SignalRestartCount,
+		Value:  float64(totalRestarts),
+		Labels: map[string]string{"container": getHighestRestartContainer(pod)},
+	})
+
+	return obs
+}
+
+// getHighestRestartContainer returns the name of the container of the pod restarted most often
+func getHighestRestartContainer(pod *corev1.Pod) string {
+	maxRestarts := int32(0)
+	containerName := "unknown"
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.RestartCount > maxRestarts {
+			maxRestarts = cs.RestartCount
+			containerName = cs.Name
+		}
+	}
+	return containerName
+}
+
+// getPodJob returns the name of the Job that created the pod, if any
+func getPodJob(pod *corev1.Pod) string {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "Job" {
+			return owner.Name
+		}
+	}
+	return ""
+}
+
+// ObserveNode builds the rule engine observation for a node
+func ObserveNode(node *corev1.Node) *processor.Observation {
+	obs := &processor.Observation{
+		Source:      SourceK8sNode,
+		Node:        node.Name,
+		Labels:      node.Labels,
+		Annotations: node.Annotations,
+	}
+
+	for _, condition := range node.Status.Conditions {
+		reason := condition.Reason
+		if reason == "" {
+			reason = string(condition.Status)
+		}
+		obs.Samples = append(obs.Samples, processor.Sample{
+			Signal:  processor.SignalNodeCondition,
+			Key:     string(condition.Type),
+			Text:    string(condition.Status),
+			Value:   1,
+			Since:   condition.LastTransitionTime.Time,
+			Reason:  reason,
+			Message: condition.Message,
+		})
+	}
+
+	return obs
+}
+
+// ObservePodMetrics builds the rule engine observation for a pod's resource usage.
+// Usage is a percentage of requests, so only resources with requests are sampled.
+func ObservePodMetrics(metrics *PodMetrics) *processor.Observation {
+	obs := &processor.Observation{
+		Source:      SourceK8sPodMetrics,
+		Namespace:   metrics.Namespace,
+		Pod:         metrics.PodName,
+		Annotations: metrics.Annotations,
+	}
+
+	if metrics.CPURequestMillis > 0 {
+		obs.Samples = append(obs.Samples, processor.Sample{
+			Signal: processor.SignalCPUPercent,
+			Value:  metrics.CPUUsagePercent,
+			Labels: map[string]string{"metric": "cpu"},
+		})
+	}
+	if metrics.MemoryRequestBytes > 0 {
+		obs.Samples = append(obs.Samples, processor.Sample{
+			Signal: processor.SignalMemoryPercent,
+			Value:  metrics.MemoryUsagePercent,
+			Labels: map[string]string{"metric": "memory"},
+		})
+	}
+
+	return obs
+}
+
+// ObserveNodeMetrics builds the rule engine observation for a node's resource usage
+func ObserveNodeMetrics(metrics *NodeMetrics) *processor.Observation {
+	return &processor.Observation{
+		Source:      SourceK8sNodeMetrics,
+		Node:        metrics.NodeName,
+		Annotations: metrics.Annotations,
+		Samples: []processor.Sample{
+			{
+				Signal: processor.SignalCPUPercent,
+				Value:  metrics.CPUUsagePercent,
+				Labels: map[string]string{"metric": "cpu"},
+			},
+			{
+				Signal: processor.SignalMemoryPercent,
+				Value:  metrics.MemoryUsagePercent,
+				Labels: map[string]string{"metric": "memory"},
+			},
+		},
+	}
+}
+
+// ObservePodUsage builds the rule engine observation for the container usage of a pod read from
+// its kubelet. Usage is a percentage of limits, so only containers with limits are sampled.
+func ObservePodUsage(pod *corev1.Pod, usage *PodUsage) *processor.Observation {
+	obs := &processor.Observation{
+		Source:      SourceK8sContainerMetrics,
+		Namespace:   pod.Namespace,
+		Pod:         pod.Name,
+		Node:        usage.Node,
+		Labels:      pod.Labels,
+		Annotations: pod.Annotations,
+	}
+
+	for _, container := range usage.Containers {
+		if container.MemoryLimitBytes > 0 {
+			obs.Samples = append(obs.Samples, processor.Sample{
+				Signal: processor.SignalContainerMemoryLimit,
+				Key:    container.Name,
+				Value:  float64(container.MemoryWorkingSetBytes) / float64(container.MemoryLimitBytes) * 100,
+				Labels: map[string]string{"container": container.Name},
+			})
+		}
+		if container.EphemeralStorageLimitBytes > 0 {
+			obs.Samples = append(obs.Samples, processor.Sample{
+				Signal: processor.SignalEphemeralStorage,
+				Key:    container.Name,
+				Value:  float64(container.RootfsUsedBytes+container.LogsUsedBytes) / float64(container.EphemeralStorageLimitBytes) * 100,
+				Labels: map[string]string{"container": container.Name},
+			})
+		}
+	}
+
+	return obs
+}
+
+// ObserveDeployment builds the rule engine observation for a deployment: its rollout progress,
+// available replicas and the generation its controller observed
+func ObserveDeployment(deployment *appsv1.Deployment) *processor.Observation {
+	obs := observeWorkload(K8sResourceTypeDeployment, deployment.ObjectMeta, deployment.Status.ObservedGeneration)
+
+	for _, condition := range deployment.Status.Conditions {
+		// The deployment controller reports ProgressDeadlineExceeded once a rollout makes no progress for progressDeadlineSeconds
+		if condition.Type == appsv1.DeploymentProgressing {
+			deadline := int32(defaultProgressDeadline)
+			if deployment.Spec.ProgressDeadlineSeconds != nil {
+				deadline = *deployment.Spec.ProgressDeadlineSeconds
+			}
+			obs.Samples = append(obs.Samples, processor.Sample{
+				Signal:  processor.SignalRolloutProgress,
+				Key:     K8sResourceTypeDeployment,
+				Text:    condition.Reason,
+				Value:   float64(deadline),
+				Reason:  condition.Reason,
+				Message: condition.Message,
+			})
+		}
+	}
+
+	if desired := desiredReplicas(deployment.Spec.Replicas); desired > 0 {
+		obs.Samples = append(obs.Samples, processor.Sample{
+			Signal:  processor.SignalAvailableReplicasPercent,
+			Key:     K8sResourceTypeDeployment,
+			Value:   float64(deployment.Status.AvailableReplicas) / float64(desired) * 100,
+			Message: fmt.Sprintf("Available: %d/%d", deployment.Status.AvailableReplicas, desired),
+		})
+	}
+
+	return obs
+}
+
+// ObserveStatefulSet builds the rule engine observation for a statefulset: its replicas that are
+// not ready and the generation its controller observed
+func ObserveStatefulSet(statefulSet *appsv1.StatefulSet) *processor.Observation {
+	obs := observeWorkload(K8sResourceTypeStatefulSet, statefulSet.ObjectMeta, statefulSet.Status.ObservedGeneration)
+
+	desired := desiredReplicas(statefulSet.Spec.Replicas)
+	obs.Samples = append(obs.Samples, processor.Sample{
+		Signal:  processor.SignalUnavailableReplicas,
+		Key:     K8sResourceTypeStatefulSet,
+		Value:   float64(desired - statefulSet.Status.ReadyReplicas),
+		Message: fmt.Sprintf("Ready: %d/%d", statefulSet.Status.ReadyReplicas, desired),
+	})
+
+	return obs
+}
+
+// ObserveDaemonSet builds the rule engine observation for a daemonset: its pods running where
+// they should not, its unavailable pods and the generation its controller observed
+func ObserveDaemonSet(daemonSet *appsv1.DaemonSet) *processor.Observation {
+	obs := observeWorkload(K8sResourceTypeDaemonSet, daemonSet.ObjectMeta, daemonSet.Status.ObservedGeneration)
+
+	obs.Samples = append(obs.Samples,
+		processor.Sample{
+			Signal: processor.SignalMisscheduledPods,
+			Key:    K8sResourceTypeDaemonSet,
+			Value:  float64(daemonSet.Status.NumberMisscheduled),
+		},
+		processor.Sample{
+			Signal:  processor.SignalUnavailableReplicas,
+			Key:     K8sResourceTypeDaemonSet,
+			Value:   float64(daemonSet.Status.NumberUnavailable),
+			Message: fmt.Sprintf("Unavailable: %d/%d", daemonSet.Status.NumberUnavailable, daemonSet.Status.DesiredNumberScheduled),
+		},
+	)
+
+	return obs
+}
+
+// desiredReplicas returns the replicas a workload asks for, which default to 1 when unset
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// observeWorkload builds the observation of a workload with the sample every kind has: how many
+// generations of its spec the controller has not observed yet
+func observeWorkload(kind string, meta metav1.ObjectMeta, observedGeneration int64) *processor.Observation {
+	return &processor.Observation{
+		Source:       SourceK8sWorkload,
+		Namespace:    meta.Namespace,
+		WorkloadKind: kind,
+		Workload:     meta.Name,
+		Labels:       meta.Labels,
+		Annotations:  meta.Annotations,
+		Samples: []processor.Sample{{
+			Signal:  processor.SignalGenerationLag,
+			Key:     kind,
+			Value:   float64(meta.Generation - observedGeneration),
+			Message: fmt.Sprintf("Generation: %d, Observed: %d", meta.Generation, observedGeneration),
+		}},
+	}
+}
+
+// ObserveVolume builds the rule engine observation for the volume of a claim as reported by the
+// kubelet of the node the pod mounting it runs on. The claim, if known, provides the labels and
+// annotations rules select and override thresholds by.
+func ObserveVolume(pvc *corev1.PersistentVolumeClaim, pod PodReference, volume VolumeStats) *processor.Observation {
+	obs := &processor.Observation{
+		Source:    SourceK8sVolume,
+		Namespace: volume.PVCRef.Namespace,
+		PVC:       volume.PVCRef.Name,
+		Pod:       pod.Name,
+	}
+	if pvc != nil {
+		obs.Labels = pvc.Labels
+		obs.Annotations = pvc.Annotations
+	}
+
+	if percent, ok := volume.UsedPercent(); ok {
+		obs.Samples = append(obs.Samples, processor.Sample{Signal: processor.SignalVolumeUsedPercent, Value: percent})
+	}
+	if percent, ok := volume.InodesUsedPercent(); ok {
+		obs.Samples = append(obs.Samples, processor.Sample{Signal: processor.SignalVolumeInodesPercent, Value: percent})
+	}
+
+	return obs
+}
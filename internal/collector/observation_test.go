@@ -0,0 +1,465 @@
+package collector_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/collector"
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newDefaultRuleEngine(t *testing.T) *processor.RuleEngine {
+	cfg := config.AlertRulesConfig{
+		PodRestartThreshold: 3,
+		PodCPUPercent:       80,
+		PodMemoryPercent:    85,
+		NodeCPUPercent:      80,
+		NodeMemoryPercent:   85,
+	}
+	engine, err := processor.NewRuleEngine(processor.DefaultRules(cfg))
+	require.NoError(t, err)
+	return engine
+}
+
+// assertAlert checks the severity, source, message and labels of a raised alert
+func assertAlert(t *testing.T, alert *models.Alert, severity, source, message string, labels map[string]string) {
+	t.Helper()
+	require.NotNil(t, alert)
+	assert.Equal(t, severity, alert.Severity)
+	assert.Equal(t, source, alert.Source)
+	assert.Equal(t, message, alert.Message)
+	assert.Equal(t, labels, alert.GetLabelsMap())
+}
+
+func alertsByType(alerts []*models.Alert) map[string]*models.Alert {
+	byType := make(map[string]*models.Alert)
+	for _, alert := range alerts {
+		byType[alert.GetLabelsMap()["alert_type"]] = alert
+	}
+	return byType
+}
+
+func TestDefaultRules_PodAlerts(t *testing.T) {
+	engine := newDefaultRuleEngine(t)
+
+	t.Run("should raise crash loop, OOM and restart alerts per container", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "production"},
+			Spec:       corev1.PodSpec{NodeName: "worker-1"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:         "app",
+						RestartCount: 7,
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 5m0s"},
+						},
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"},
+						},
+					},
+				},
+			},
+		}
+
+		alerts := alertsByType(engine.Evaluate(collector.ObservePod(pod)))
+		require.Len(t, alerts, 3)
+
+		labels := func(alertType string) map[string]string {
+			return map[string]string{"namespace": "production", "pod": "api-0", "node": "worker-1", "container": "app", "alert_type": alertType}
+		}
+		crashLoop := labels("pod_crash_loop")
+		crashLoop["reason"] = "back-off 5m0s"
+
+		assertAlert(t, alerts["pod_crash_loop"], collector.SeverityHigh, collector.SourceK8sPod,
+			"Pod production/api-0 container 'app' is in CRASH LOOP BACKOFF - Reason: back-off 5m0s", crashLoop)
+		assertAlert(t, alerts["pod_oom_killed"], collector.SeverityCritical, collector.SourceK8sPod,
+			"Pod production/api-0 container 'app' was OOM KILLED - Out of memory", labels("pod_oom_killed"))
+		assertAlert(t, alerts["pod_restart_threshold"], collector.SeverityHigh, collector.SourceK8sPod,
+			"Pod production/api-0 has EXCESSIVE RESTARTS - Total restarts: 7, Container: app", labels("pod_restart_threshold"))
+		for _, alert := range alerts {
+			assert.Equal(t, 7.0, alert.Value)
+		}
+	})
+
+	t.Run("should keep the waiting message of image pull errors out of the labels", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "app",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: `Back-off pulling image "web:1.2"`},
+					},
+				}},
+			},
+		}
+
+		alerts := alertsByType(engine.Evaluate(collector.ObservePod(pod)))
+		assertAlert(t, alerts["pod_image_pull"], collector.SeverityHigh, collector.SourceK8sPod,
+			`Pod default/web-0 container 'app' cannot pull image - Error: Back-off pulling image "web:1.2"`,
+			map[string]string{"namespace": "default", "pod": "web-0", "container": "app", "alert_type": "pod_image_pull"})
+	})
+
+	t.Run("should raise pending alert only after five minutes", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "web-0",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodPending, Reason: "Unschedulable"},
+		}
+		assert.Empty(t, engine.Evaluate(collector.ObservePod(pod)))
+
+		pod.CreationTimestamp = metav1.NewTime(time.Now().Add(-10 * time.Minute))
+		alerts := engine.Evaluate(collector.ObservePod(pod))
+		require.Len(t, alerts, 1)
+		assertAlert(t, alerts[0], collector.SeverityMedium, collector.SourceK8sPod,
+			"Pod default/web-0 is PENDING for extended period - Reason: Unschedulable",
+			map[string]string{"namespace": "default", "pod": "web-0", "alert_type": "pod_pending"})
+	})
+
+	t.Run("should label failed job pods with their job", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "nightly-report-28930-x7k2p",
+				Namespace:       "batch",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "nightly-report-28930"}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Error"},
+		}
+		alerts := engine.Evaluate(collector.ObservePod(pod))
+		require.Len(t, alerts, 1)
+		assertAlert(t, alerts[0], collector.SeverityCritical, collector.SourceK8sPod,
+			"Pod batch/nightly-report-28930-x7k2p has FAILED - Phase: Failed, Reason: Error",
+			map[string]string{"namespace": "batch", "pod": "nightly-report-28930-x7k2p", "job": "nightly-report-28930", "alert_type": "pod_failed"})
+	})
+
+	t.Run("should raise unknown alert for pods in an unknown phase", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "production"},
+			Spec:       corev1.PodSpec{NodeName: "worker-2"},
+			Status:     corev1.PodStatus{Phase: corev1.PodUnknown},
+		}
+		alerts := engine.Evaluate(collector.ObservePod(pod))
+		require.Len(t, alerts, 1)
+		assertAlert(t, alerts[0], collector.SeverityCritical, collector.SourceK8sPod,
+			"Pod production/api-1 is in UNKNOWN state - Last known phase: Unknown",
+			map[string]string{"namespace": "production", "pod": "api-1", "node": "worker-2", "alert_type": "pod_unknown"})
+	})
+
+	t.Run("should raise nothing for a healthy pod", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ok-0", Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: 1}},
+			},
+		}
+		assert.Empty(t, engine.Evaluate(collector.ObservePod(pod)))
+	})
+}
+
+func TestDefaultRules_NodeAlerts(t *testing.T) {
+	engine := newDefaultRuleEngine(t)
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Reason: "KubeletNotReady"},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodePIDPressure, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+
+	alerts := alertsByType(engine.Evaluate(collector.ObserveNode(node)))
+	require.Len(t, alerts, 4)
+
+	labels := func(alertType string) map[string]string {
+		return map[string]string{"node": "worker-1", "alert_type": alertType}
+	}
+	assertAlert(t, alerts["node_not_ready"], collector.SeverityCritical, collector.SourceK8sNode,
+		"Node worker-1 is NOT READY - Status: KubeletNotReady", labels("node_not_ready"))
+	assertAlert(t, alerts["node_disk_pressure"], collector.SeverityHigh, collector.SourceK8sNode,
+		"Node worker-1 has DISK PRESSURE - Disk space is running low", labels("node_disk_pressure"))
+	assertAlert(t, alerts["node_memory_pressure"], collector.SeverityHigh, collector.SourceK8sNode,
+		"Node worker-1 has MEMORY PRESSURE - Available memory is low", labels("node_memory_pressure"))
+	assertAlert(t, alerts["node_pid_pressure"], collector.SeverityMedium, collector.SourceK8sNode,
+		"Node worker-1 has PID PRESSURE - Too many processes running", labels("node_pid_pressure"))
+}
+
+func TestDefaultRules_MetricAlerts(t *testing.T) {
+	engine := newDefaultRuleEngine(t)
+
+	t.Run("should only check resources the pod requests", func(t *testing.T) {
+		metrics := &collector.PodMetrics{
+			Namespace:          "production",
+			PodName:            "api-0",
+			CPUUsagePercent:    95.5,
+			MemoryUsagePercent: 99,
+			CPURequestMillis:   100,
+		}
+
+		alerts := engine.Evaluate(collector.ObservePodMetrics(metrics))
+		require.Len(t, alerts, 1)
+		assertAlert(t, alerts[0], collector.SeverityHigh, collector.SourceK8sPodMetrics,
+			"Pod production/api-0 CPU usage is HIGH: 95.5% (threshold: 80.0%)",
+			map[string]string{"namespace": "production", "pod": "api-0", "metric": "cpu", "alert_type": "pod_cpu_high"})
+		assert.Equal(t, 95.5, alerts[0].Value)
+	})
+
+	t.Run("should raise node memory alert above threshold", func(t *testing.T) {
+		metrics := &collector.NodeMetrics{NodeName: "worker-1", CPUUsagePercent: 40, MemoryUsagePercent: 91.2}
+
+		alerts := engine.Evaluate(collector.ObserveNodeMetrics(metrics))
+		require.Len(t, alerts, 1)
+		assertAlert(t, alerts[0], collector.SeverityCritical, collector.SourceK8sNodeMetrics,
+			"Node worker-1 Memory usage is CRITICAL: 91.2% (threshold: 85.0%)",
+			map[string]string{"node": "worker-1", "metric": "memory", "alert_type": "node_memory_high"})
+	})
+}
+
+func TestDefaultRules_WorkloadAlerts(t *testing.T) {
+	replicas := int32(4)
+	meta := metav1.ObjectMeta{Name: "api", Namespace: "production", Generation: 3}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: meta,
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			AvailableReplicas:  1,
+			Conditions: []appsv1.DeploymentCondition{{
+				Type:    appsv1.DeploymentProgressing,
+				Reason:  "ProgressDeadlineExceeded",
+				Message: `ReplicaSet "api-7d9f" has timed out progressing.`,
+			}},
+		},
+	}
+	labels := func(kind, name, alertType string) map[string]string {
+		return map[string]string{"namespace": "production", "workload_kind": kind, "workload": name, "alert_type": alertType}
+	}
+	immediate := func(t *testing.T) *processor.RuleEngine {
+		engine, err := processor.NewRuleEngine(processor.DefaultRules(config.AlertRulesConfig{
+			For: map[string]time.Duration{
+				"deployment_replicas_unavailable": 0,
+				"statefulset_replicas_not_ready":  0,
+				"daemonset_misscheduled":          0,
+				"daemonset_pods_unavailable":      0,
+				"workload_generation_lag":         0,
+			},
+		}))
+		require.NoError(t, err)
+		return engine
+	}
+
+	t.Run("should raise deployment alerts once the grace period has passed", func(t *testing.T) {
+		alerts := alertsByType(immediate(t).Evaluate(collector.ObserveDeployment(deployment)))
+		require.Len(t, alerts, 3)
+
+		assertAlert(t, alerts["deployment_rollout_stuck"], collector.SeverityCritical, collector.SourceK8sWorkload,
+			`Deployment production/api rollout is STUCK - No progress for 600s, Message: ReplicaSet "api-7d9f" has timed out progressing.`,
+			labels("Deployment", "api", "deployment_rollout_stuck"))
+		assertAlert(t, alerts["deployment_replicas_unavailable"], collector.SeverityHigh, collector.SourceK8sWorkload,
+			"Deployment production/api has UNAVAILABLE REPLICAS - Available: 1/4",
+			labels("Deployment", "api", "deployment_replicas_unavailable"))
+		assertAlert(t, alerts["workload_generation_lag"], collector.SeverityMedium, collector.SourceK8sWorkload,
+			"Deployment production/api spec is NOT OBSERVED by its controller - Generation: 3, Observed: 2",
+			labels("Deployment", "api", "workload_generation_lag"))
+		assert.Equal(t, 600.0, alerts["deployment_rollout_stuck"].Value)
+		assert.Equal(t, 25.0, alerts["deployment_replicas_unavailable"].Value)
+	})
+
+	t.Run("should tell workloads of different kinds with the same name apart", func(t *testing.T) {
+		statefulSet := &appsv1.StatefulSet{
+			ObjectMeta: meta,
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status:     appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 4},
+		}
+
+		alerts := immediate(t).Evaluate(collector.ObserveStatefulSet(statefulSet))
+		require.Len(t, alerts, 1)
+		assertAlert(t, alerts[0], collector.SeverityMedium, collector.SourceK8sWorkload,
+			"StatefulSet production/api spec is NOT OBSERVED by its controller - Generation: 3, Observed: 2",
+			labels("StatefulSet", "api", "workload_generation_lag"))
+
+		lag := alertsByType(immediate(t).Evaluate(collector.ObserveDeployment(deployment)))["workload_generation_lag"]
+		require.NotNil(t, lag)
+		assert.NotEqual(t, lag.Fingerprint, alerts[0].Fingerprint)
+	})
+
+	t.Run("should raise daemonset alerts", func(t *testing.T) {
+		daemonSet := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-exporter", Namespace: "production"},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 5, NumberUnavailable: 2, NumberMisscheduled: 1},
+		}
+
+		alerts := alertsByType(immediate(t).Evaluate(collector.ObserveDaemonSet(daemonSet)))
+		require.Len(t, alerts, 2)
+		assertAlert(t, alerts["daemonset_pods_unavailable"], collector.SeverityHigh, collector.SourceK8sWorkload,
+			"DaemonSet production/node-exporter has UNAVAILABLE pods - Unavailable: 2/5",
+			labels("DaemonSet", "node-exporter", "daemonset_pods_unavailable"))
+		assertAlert(t, alerts["daemonset_misscheduled"], collector.SeverityMedium, collector.SourceK8sWorkload,
+			"DaemonSet production/node-exporter has MISSCHEDULED pods - 1 pods run on nodes they should not",
+			labels("DaemonSet", "node-exporter", "daemonset_misscheduled"))
+	})
+
+	t.Run("should hold degraded workloads as pending for the grace period", func(t *testing.T) {
+		engine, err := processor.NewRuleEngine(processor.DefaultRules(config.AlertRulesConfig{}))
+		require.NoError(t, err)
+		statefulSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "production"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status:     appsv1.StatefulSetStatus{ReadyReplicas: 2},
+		}
+		obs := collector.ObserveStatefulSet(statefulSet)
+
+		assert.Empty(t, engine.Evaluate(obs))
+
+		held, firesAt := engine.PendingFor(obs)
+		require.Len(t, held, 1)
+		assertAlert(t, held[0], collector.SeverityHigh, collector.SourceK8sWorkload,
+			"StatefulSet production/db has replicas NOT READY - Ready: 2/4",
+			labels("StatefulSet", "db", "statefulset_replicas_not_ready"))
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), firesAt, time.Minute)
+
+		pending := engine.Pending()
+		require.Len(t, pending, 1)
+		assert.Equal(t, "statefulset_replicas_not_ready", pending[0].Rule)
+		assert.Equal(t, "db", pending[0].Labels["workload"])
+	})
+
+	t.Run("should override the available threshold with a workload annotation", func(t *testing.T) {
+		engine, err := processor.NewRuleEngine(processor.DefaultRules(config.AlertRulesConfig{
+			For: map[string]time.Duration{"deployment_replicas_unavailable": 0},
+		}))
+		require.NoError(t, err)
+		obs := collector.ObserveDeployment(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "batch-api",
+				Namespace:   "production",
+				Annotations: map[string]string{"monitoring-tool/available-replicas-threshold": "20"},
+			},
+			Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{AvailableReplicas: 1},
+		})
+
+		assert.Empty(t, engine.Evaluate(obs))
+	})
+}
+
+func TestDefaultRules_VolumeAlerts(t *testing.T) {
+	uint64p := func(v uint64) *uint64 { return &v }
+	pod := collector.PodReference{Name: "postgres-0", Namespace: "db"}
+	volume := collector.VolumeStats{
+		Name:   "data",
+		PVCRef: &collector.PVCReference{Name: "data-postgres-0", Namespace: "db"},
+		FsStats: collector.FsStats{
+			CapacityBytes: uint64p(1000),
+			UsedBytes:     uint64p(915),
+			Inodes:        uint64p(100),
+			InodesUsed:    uint64p(95),
+		},
+	}
+
+	t.Run("should raise usage and inode alerts labelled with the pod", func(t *testing.T) {
+		engine := newDefaultRuleEngine(t)
+
+		alerts := alertsByType(engine.Evaluate(collector.ObserveVolume(nil, pod, volume)))
+		require.Len(t, alerts, 2)
+
+		labels := func(alertType string) map[string]string {
+			return map[string]string{"namespace": "db", "pvc": "data-postgres-0", "pod": "postgres-0", "alert_type": alertType}
+		}
+		assertAlert(t, alerts["volume_usage_high"], collector.SeverityHigh, collector.SourceK8sVolume,
+			"PVC db/data-postgres-0 volume usage is HIGH: 91.5% (threshold: 85.0%) - Pod: postgres-0", labels("volume_usage_high"))
+		assertAlert(t, alerts["volume_inodes_high"], collector.SeverityHigh, collector.SourceK8sVolume,
+			"PVC db/data-postgres-0 inode usage is HIGH: 95.0% (threshold: 90.0%) - Pod: postgres-0", labels("volume_inodes_high"))
+		assert.Equal(t, 91.5, alerts["volume_usage_high"].Value)
+	})
+
+	t.Run("should override the usage threshold with a claim annotation", func(t *testing.T) {
+		engine := newDefaultRuleEngine(t)
+		pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:        "data-postgres-0",
+			Namespace:   "db",
+			Annotations: map[string]string{"monitoring-tool/volume-usage-threshold": "95"},
+		}}
+
+		alerts := alertsByType(engine.Evaluate(collector.ObserveVolume(pvc, pod, volume)))
+		assert.Nil(t, alerts[string(collector.AlertTypeVolumeUsageHigh)])
+		assert.NotNil(t, alerts[string(collector.AlertTypeVolumeInodesHigh)])
+	})
+
+	t.Run("should hold full volumes as pending for the configured duration", func(t *testing.T) {
+		engine, err := processor.NewRuleEngine(processor.DefaultRules(config.AlertRulesConfig{
+			For: map[string]time.Duration{"volume_usage_high": 10 * time.Minute},
+		}))
+		require.NoError(t, err)
+		obs := collector.ObserveVolume(nil, pod, volume)
+
+		alerts := alertsByType(engine.Evaluate(obs))
+		assert.Nil(t, alerts[string(collector.AlertTypeVolumeUsageHigh)])
+
+		held, _ := engine.PendingFor(obs)
+		require.Len(t, held, 1)
+		assert.Equal(t, string(collector.AlertTypeVolumeUsageHigh), held[0].GetLabelsMap()["alert_type"])
+
+		pending := engine.Pending()
+		require.Len(t, pending, 1)
+		assert.Equal(t, "volume_usage_high", pending[0].Rule)
+		assert.Equal(t, "data-postgres-0", pending[0].Labels["pvc"])
+	})
+}
+
+func TestObservePodUsage(t *testing.T) {
+	engine := newDefaultRuleEngine(t)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "production"}}
+
+	t.Run("should alert per container near its limits", func(t *testing.T) {
+		usage := &collector.PodUsage{
+			Namespace: "production",
+			Pod:       "api-0",
+			Node:      "worker-1",
+			Containers: []collector.ContainerUsage{
+				{Name: "app", MemoryWorkingSetBytes: 950, MemoryLimitBytes: 1000},
+				{Name: "sidecar", MemoryWorkingSetBytes: 100, MemoryLimitBytes: 1000, RootfsUsedBytes: 800, LogsUsedBytes: 150, EphemeralStorageLimitBytes: 1000},
+				{Name: "unbounded", MemoryWorkingSetBytes: 1 << 30},
+			},
+		}
+
+		alerts := alertsByType(engine.Evaluate(collector.ObservePodUsage(pod, usage)))
+		require.Len(t, alerts, 2)
+
+		memory := alerts["container_memory_near_limit"]
+		require.NotNil(t, memory)
+		assert.Equal(t, collector.SourceK8sContainerMetrics, memory.Source)
+		assert.Equal(t, "app", memory.GetLabelsMap()["container"])
+		assert.Equal(t, "Pod production/api-0 container 'app' memory is NEAR ITS LIMIT: 95.0% (threshold: 90.0%)", memory.Message)
+
+		storage := alerts["container_ephemeral_storage_high"]
+		require.NotNil(t, storage)
+		assert.Equal(t, "sidecar", storage.GetLabelsMap()["container"])
+		assert.InDelta(t, 95, storage.Value, 0.001)
+	})
+
+	t.Run("should respect the memory limit threshold annotation", func(t *testing.T) {
+		annotated := pod.DeepCopy()
+		annotated.Annotations = map[string]string{"monitoring-tool/memory-limit-threshold": "97"}
+		usage := &collector.PodUsage{Containers: []collector.ContainerUsage{{Name: "app", MemoryWorkingSetBytes: 950, MemoryLimitBytes: 1000}}}
+
+		assert.Empty(t, engine.Evaluate(collector.ObservePodUsage(annotated, usage)))
+	})
+}
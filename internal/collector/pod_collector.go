@@ -6,173 +6,99 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/watch"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 
-	"github.com/monitoring-engine/monitoring-tool/internal/config"
-	"github.com/monitoring-engine/monitoring-tool/internal/processor"
-	"github.com/monitoring-engine/monitoring-tool/internal/models"
 	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
 	"github.com/monitoring-engine/monitoring-tool/internal/pool"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
 )
 
-// PodEvent represents a pod event
-type PodEvent struct {
-	Type      watch.EventType
-	Pod       *corev1.Pod
-	Timestamp time.Time
-}
-
-// PodWatcher watches pod events and processes them with goroutines
+// PodWatcher watches pods through a shared informer and evaluates them on the worker pool
 type PodWatcher struct {
-	client              *K8sClient
-	eventChan           chan *PodEvent // Buffered channel
-	stateManager        *processor.AlertStateManager
-	workerPool          *pool.WorkerPool
-	stopCh              chan struct{}
-	wg                  sync.WaitGroup
-	restartThreshold    int32
-	pendingTimeout      time.Duration
+	client       *K8sClient
+	informer     cache.SharedIndexInformer
+	lister       listersv1.PodLister
+	queue        *objectQueue
+	stateManager *processor.AlertStateManager
+	ruleEngine   *processor.RuleEngine
+	stopCh       chan struct{}
+	wg           sync.WaitGroup
 }
 
 // NewPodWatcher creates a new pod watcher
-func NewPodWatcher(k8sClient *K8sClient, stateManager *processor.AlertStateManager, workerPool *pool.WorkerPool) *PodWatcher {
-	cfg := config.Get()
-	return &PodWatcher{
-		client:           k8sClient,
-		eventChan:        make(chan *PodEvent, 500), // Buffered
-		stateManager:     stateManager,
-		workerPool:       workerPool,
-		stopCh:           make(chan struct{}),
-		restartThreshold: int32(cfg.AlertRules.PodRestartThreshold),
-		pendingTimeout:   5 * time.Minute, // Default 5 minutes, can be made configurable
+func NewPodWatcher(k8sClient *K8sClient, stateManager *processor.AlertStateManager, ruleEngine *processor.RuleEngine, workerPool *pool.WorkerPool) *PodWatcher {
+	podInformer := k8sClient.GetInformerFactory().Core().V1().Pods()
+	pw := &PodWatcher{
+		client:       k8sClient,
+		informer:     podInformer.Informer(),
+		lister:       podInformer.Lister(),
+		stateManager: stateManager,
+		ruleEngine:   ruleEngine,
+		stopCh:       make(chan struct{}),
 	}
+	pw.queue = newObjectQueue("pod", workerPool, pw.processPod)
+	return pw
 }
 
-// Start begins watching pods using worker pool
+// Start begins watching pods. The informer lists all pods, then watches from that
+// resourceVersion, relisting with backoff whenever the watch breaks. Pods are processed once
+// K8sClient.StartInformers has started the informer and it has synced.
 func (pw *PodWatcher) Start(ctx context.Context) {
-	logger.Info().Msg("Starting Pod Watcher with worker pool")
+	logger.Info().Msg("Starting Pod Watcher with informer and worker pool")
 
-	// Start event dispatcher that submits to worker pool
-	pw.wg.Add(1)
-	go pw.eventDispatcher(ctx)
+	if err := pw.informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		logger.Warn().Err(err).Msg("Pod watch failed, informer will relist with backoff")
+	}); err != nil {
+		logger.Warn().Err(err).Msg("Failed to set pod watch error handler")
+	}
+	if _, err := pw.informer.AddEventHandler(pw.queue.handler()); err != nil {
+		logger.Error().Err(err).Msg("Failed to register pod event handler")
+		return
+	}
 
-	// Start real K8s pod watcher
 	pw.wg.Add(1)
-	go pw.watchPods(ctx)
+	go pw.queue.run(ctx, pw.stopCh, &pw.wg, pw.informer.HasSynced)
 }
 
-// watchPods watches for pod events from Kubernetes API
-func (pw *PodWatcher) watchPods(ctx context.Context) {
-	defer pw.wg.Done()
-
-	clientset := pw.client.GetClientset()
-
-	for {
-		select {
-		case <-pw.stopCh:
-			return
-		case <-ctx.Done():
-			return
-		default:
-		}
-
-		// Watch all pods in all namespaces
-		watcher, err := clientset.CoreV1().Pods("").Watch(ctx, metav1.ListOptions{})
-		if err != nil {
-			logger.Error().Err(err).Msg("Failed to create pod watcher, retrying in 5s")
-			time.Sleep(5 * time.Second)
-			continue
-		}
-
-		logger.Info().Msg("Pod watcher connected to Kubernetes API")
-
-		// Process watch events
-		func() {
-			defer watcher.Stop()
-
-			for {
-				select {
-				case event, ok := <-watcher.ResultChan():
-					if !ok {
-						logger.Warn().Msg("Pod watch channel closed, reconnecting...")
-						return
-					}
-
-					pod, ok := event.Object.(*corev1.Pod)
-					if !ok {
-						logger.Warn().Msg("Received non-pod object from watch")
-						continue
-					}
-
-					podEvent := &PodEvent{
-						Type:      event.Type,
-						Pod:       pod,
-						Timestamp: time.Now(),
-					}
-
-					select {
-					case pw.eventChan <- podEvent:
-						logger.Debug().
-							Str("type", string(event.Type)).
-							Str("pod", pod.Name).
-							Str("namespace", pod.Namespace).
-							Msg("Received pod event")
-					default:
-						logger.Warn().Msg("Pod event channel full, dropping event")
-					}
-
-				case <-pw.stopCh:
-					return
-				case <-ctx.Done():
-					return
-				}
-			}
-		}()
+// processPod evaluates the latest cached state of a pod with detailed alert categorization
+func (pw *PodWatcher) processPod(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Warn().Err(err).Str("key", key).Msg("Invalid pod key")
+		return nil
 	}
-}
 
-// eventDispatcher reads events and submits them to worker pool
-func (pw *PodWatcher) eventDispatcher(ctx context.Context) {
-	defer pw.wg.Done()
+	subject := map[string]string{
+		"namespace": namespace,
+		"pod":       name,
+	}
 
-	for {
-		select {
-		case event := <-pw.eventChan:
-			// Submit event processing to worker pool
-			eventCopy := event // Capture for closure
-			if err := pw.workerPool.SubmitWithContext(ctx, func(ctx context.Context) error {
-				return pw.processEvent(ctx, eventCopy)
-			}); err != nil {
-				logger.Warn().Err(err).
-					Str("pod", event.Pod.Name).
-					Str("namespace", event.Pod.Namespace).
-					Msg("Failed to submit pod event to worker pool (queue full)")
+	pod, err := pw.lister.Pods(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		// A deleted pod can no longer be unhealthy - resolve everything firing for it
+		for _, source := range []string{SourceK8sPod, SourceK8sPodMetrics, SourceK8sContainerMetrics} {
+			pw.ruleEngine.Forget(source, subject)
+			if _, err := pw.stateManager.ResolveCleared(ctx, source, subject, nil); err != nil {
+				return err
 			}
-
-		case <-pw.stopCh:
-			logger.Info().Msg("Pod event dispatcher stopped")
-			return
-
-		case <-ctx.Done():
-			return
 		}
+		return nil
+	}
+	if err != nil {
+		return err
 	}
-}
-
-// processEvent handles a single pod event with detailed alert categorization
-func (pw *PodWatcher) processEvent(ctx context.Context, event *PodEvent) error {
-	pod := event.Pod
 
 	logger.Debug().
-		Str("type", string(event.Type)).
 		Str("pod", pod.Name).
 		Str("namespace", pod.Namespace).
 		Str("phase", string(pod.Status.Phase)).
-		Msg("Processing pod event")
+		Msg("Processing pod")
 
 	// Check for different types of critical conditions
-	alerts := pw.evaluatePodConditions(pod)
+	alerts, pending := pw.evaluatePodConditions(ctx, pod)
 
 	// Process each alert through the state manager
 	for _, alert := range alerts {
@@ -195,63 +121,25 @@ func (pw *PodWatcher) processEvent(ctx context.Context, event *PodEvent) error {
 		}
 	}
 
-	return nil
+	// Resolve alerts whose condition is no longer present
+	_, err = pw.stateManager.ResolveCleared(ctx, SourceK8sPod, subject, append(alerts, pending...))
+	return err
 }
 
-// evaluatePodConditions checks pod for various critical conditions and returns alerts
-func (pw *PodWatcher) evaluatePodConditions(pod *corev1.Pod) []*models.Alert {
-	var alerts []*models.Alert
-
-	// 1. Check for pod failure
-	if pod.Status.Phase == corev1.PodFailed {
-		alert := BuildPodAlert(pod, AlertTypePodFailed, 1.0)
-		alerts = append(alerts, alert)
-	}
-
-	// 2. Check for unknown state
-	if pod.Status.Phase == corev1.PodUnknown {
-		alert := BuildPodAlert(pod, AlertTypePodUnknown, 1.0)
-		alerts = append(alerts, alert)
+// evaluatePodConditions evaluates the alert rules against the pod and returns the alerts that fire
+// and those still pending for their rule's duration, which keep an alert already firing for them.
+// Namespace annotations are included so they can override thresholds for the pod. The pod is
+// evaluated again once a pending condition is due to fire.
+func (pw *PodWatcher) evaluatePodConditions(ctx context.Context, pod *corev1.Pod) ([]*models.Alert, []*models.Alert) {
+	obs := ObservePod(pod)
+	obs.NamespaceAnnotations = pw.client.GetNamespaceCache().Annotations(ctx, pod.Namespace)
+
+	alerts := pw.ruleEngine.Evaluate(obs)
+	pending, firesAt := pw.ruleEngine.PendingFor(obs)
+	if !firesAt.IsZero() {
+		pw.queue.addAfter(pod.Namespace+"/"+pod.Name, time.Until(firesAt))
 	}
-
-	// 3. Check container statuses for various issues
-	var totalRestarts int32
-	for _, cs := range pod.Status.ContainerStatuses {
-		totalRestarts += cs.RestartCount
-
-		// Check for OOMKilled
-		if cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.Reason == "OOMKilled" {
-			alert := BuildPodAlert(pod, AlertTypePodOOMKilled, float64(cs.RestartCount))
-			alerts = append(alerts, alert)
-		}
-
-		// Check for CrashLoopBackOff
-		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
-			alert := BuildPodAlert(pod, AlertTypePodCrashLoop, float64(cs.RestartCount))
-			alerts = append(alerts, alert)
-		}
-
-		// Check for Image Pull errors
-		if cs.State.Waiting != nil &&
-			(cs.State.Waiting.Reason == "ImagePullBackOff" || cs.State.Waiting.Reason == "ErrImagePull") {
-			alert := BuildPodAlert(pod, AlertTypePodImagePullError, 1.0)
-			alerts = append(alerts, alert)
-		}
-	}
-
-	// 4. Check for excessive restarts (using configured threshold)
-	if totalRestarts > pw.restartThreshold {
-		alert := BuildPodAlert(pod, AlertTypePodRestartThreshold, float64(totalRestarts))
-		alerts = append(alerts, alert)
-	}
-
-	// 5. Check for pending state (could indicate scheduling issues)
-	if pod.Status.Phase == corev1.PodPending && time.Since(pod.CreationTimestamp.Time) > pw.pendingTimeout {
-		alert := BuildPodAlert(pod, AlertTypePodPending, 1.0)
-		alerts = append(alerts, alert)
-	}
-
-	return alerts
+	return alerts, pending
 }
 
 // Stop gracefully stops the pod watcher
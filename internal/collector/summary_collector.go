@@ -0,0 +1,257 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/pool"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// defaultContainerCheckInterval is how often container usage is read when no interval is configured
+const defaultContainerCheckInterval = 60 * time.Second
+
+// NodeUsage is the container and pod usage read from one node's kubelet, as streamed to
+// WebSocket clients in metric messages
+type NodeUsage struct {
+	Node      string      `json:"node"`
+	Pods      []*PodUsage `json:"pods"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// PodUsage is the usage of a pod's containers and its network traffic
+type PodUsage struct {
+	Namespace      string           `json:"namespace"`
+	Pod            string           `json:"pod"`
+	Node           string           `json:"-"`
+	Containers     []ContainerUsage `json:"containers"`
+	NetworkRxBytes uint64           `json:"network_rx_bytes"` // cumulative, across all interfaces
+	NetworkTxBytes uint64           `json:"network_tx_bytes"`
+}
+
+// ContainerUsage is the usage of one container, with the limits it is evaluated against
+type ContainerUsage struct {
+	Name                       string  `json:"name"`
+	CPUMillicores              float64 `json:"cpu_millicores"`
+	MemoryWorkingSetBytes      uint64  `json:"memory_working_set_bytes"`
+	MemoryLimitBytes           int64   `json:"memory_limit_bytes,omitempty"`
+	RootfsUsedBytes            uint64  `json:"rootfs_used_bytes"`
+	LogsUsedBytes              uint64  `json:"logs_used_bytes"`
+	EphemeralStorageLimitBytes int64   `json:"ephemeral_storage_limit_bytes,omitempty"`
+}
+
+// SummaryWatcher periodically reads the kubelet Summary API of every node. Unlike metrics-server
+// it reports each container separately, with its writable layer, logs and the pod's network
+// traffic. Usage is streamed to the broadcaster and evaluated against container limits by the
+// rule engine.
+type SummaryWatcher struct {
+	client       *K8sClient
+	nodeInformer cache.SharedIndexInformer
+	podInformer  cache.SharedIndexInformer
+	nodes        listerscorev1.NodeLister
+	pods         listerscorev1.PodLister
+	summaries    SummaryProvider
+	broadcaster  Broadcaster // optional
+	stateManager *processor.AlertStateManager
+	ruleEngine   *processor.RuleEngine
+	workerPool   *pool.WorkerPool
+	interval     time.Duration
+	stopCh       chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewSummaryWatcher creates a new summary watcher reading node summaries from summaries
+func NewSummaryWatcher(
+	k8sClient *K8sClient,
+	stateManager *processor.AlertStateManager,
+	ruleEngine *processor.RuleEngine,
+	summaries SummaryProvider,
+	broadcaster Broadcaster,
+	rules config.AlertRulesConfig,
+	workerPool *pool.WorkerPool,
+) *SummaryWatcher {
+	core := k8sClient.GetInformerFactory().Core().V1()
+	sw := &SummaryWatcher{
+		client:       k8sClient,
+		nodeInformer: core.Nodes().Informer(),
+		podInformer:  core.Pods().Informer(),
+		nodes:        core.Nodes().Lister(),
+		pods:         core.Pods().Lister(),
+		summaries:    summaries,
+		broadcaster:  broadcaster,
+		stateManager: stateManager,
+		ruleEngine:   ruleEngine,
+		workerPool:   workerPool,
+		interval:     rules.ContainerCheckInterval,
+		stopCh:       make(chan struct{}),
+	}
+	if sw.interval <= 0 {
+		sw.interval = defaultContainerCheckInterval
+	}
+	return sw
+}
+
+// Start begins reading node summaries
+func (sw *SummaryWatcher) Start(ctx context.Context) {
+	logger.Info().
+		Str("interval", sw.interval.String()).
+		Msg("Starting Summary Watcher")
+
+	sw.wg.Add(1)
+	go sw.summaryLoop(ctx)
+}
+
+// summaryLoop periodically checks every node once nodes and pods are known
+func (sw *SummaryWatcher) summaryLoop(ctx context.Context) {
+	defer sw.wg.Done()
+
+	if !cache.WaitForCacheSync(sw.stopCh, sw.nodeInformer.HasSynced, sw.podInformer.HasSynced) {
+		return
+	}
+
+	ticker := time.NewTicker(sw.interval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	sw.checkAllNodes(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			sw.checkAllNodes(ctx)
+		case <-sw.stopCh:
+			logger.Info().Msg("Summary watcher stopped")
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkAllNodes submits a check of each node to the worker pool
+func (sw *SummaryWatcher) checkAllNodes(ctx context.Context) {
+	nodes, err := sw.nodes.List(labels.Everything())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to list nodes")
+		return
+	}
+
+	for _, node := range nodes {
+		nodeName := node.Name
+		if err := sw.workerPool.SubmitWithContext(ctx, func(ctx context.Context) error {
+			return sw.checkNode(ctx, nodeName)
+		}); err != nil {
+			logger.Warn().Err(err).Str("node", nodeName).Msg("Failed to submit node summary check (worker pool queue full)")
+		}
+	}
+}
+
+// checkNode reads the summary of a node, streams it and evaluates the usage of each of its pods
+func (sw *SummaryWatcher) checkNode(ctx context.Context, nodeName string) error {
+	summary, err := sw.summaries.GetNodeSummary(ctx, nodeName)
+	if err != nil {
+		logger.Warn().Err(err).Str("node", nodeName).Msg("Failed to get node summary")
+		return err
+	}
+
+	nodeUsage := &NodeUsage{Node: nodeName, Timestamp: time.Now()}
+	for _, stats := range summary.Pods {
+		pod, err := sw.pods.Pods(stats.PodRef.Namespace).Get(stats.PodRef.Name)
+		if err != nil {
+			// Deleted pods have their alerts resolved by the pod watcher
+			continue
+		}
+
+		usage := newPodUsage(nodeName, stats, pod)
+		nodeUsage.Pods = append(nodeUsage.Pods, usage)
+
+		obs := ObservePodUsage(pod, usage)
+		obs.NamespaceAnnotations = sw.client.GetNamespaceCache().Annotations(ctx, pod.Namespace)
+		active := sw.ruleEngine.Evaluate(obs)
+		pending, _ := sw.ruleEngine.PendingFor(obs)
+		for _, alert := range active {
+			if created, err := sw.stateManager.ProcessAlert(ctx, alert); err != nil {
+				logger.Error().Err(err).Str("pod", pod.Name).Msg("Failed to create container usage alert")
+			} else if created {
+				logger.Info().
+					Str("pod", pod.Name).
+					Str("container", alert.GetLabelsMap()["container"]).
+					Float64("percent", alert.Value).
+					Msg("Container usage alert created")
+			}
+		}
+
+		// Resolve usage alerts that dropped back below threshold, keeping those still pending
+		subject := map[string]string{"namespace": pod.Namespace, "pod": pod.Name}
+		if _, err := sw.stateManager.ResolveCleared(ctx, SourceK8sContainerMetrics, subject, append(active, pending...)); err != nil {
+			logger.Error().Err(err).Str("pod", pod.Name).Msg("Failed to resolve container usage alerts")
+		}
+	}
+
+	sw.stream(nodeUsage)
+	return nil
+}
+
+// stream sends the usage of a node to the broadcaster as a metric message
+func (sw *SummaryWatcher) stream(usage *NodeUsage) {
+	if sw.broadcaster == nil {
+		return
+	}
+	if err := sw.broadcaster.BroadcastJSON(WSMessageTypeMetric, usage); err != nil {
+		logger.Warn().Err(err).Str("node", usage.Node).Msg("Failed to stream node usage")
+	}
+}
+
+// Stop gracefully stops the summary watcher
+func (sw *SummaryWatcher) Stop() {
+	close(sw.stopCh)
+	sw.wg.Wait()
+}
+
+// newPodUsage combines the kubelet stats of a pod with the limits of its containers
+func newPodUsage(nodeName string, stats PodStats, pod *corev1.Pod) *PodUsage {
+	limits := make(map[string]corev1.ResourceList, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		limits[container.Name] = container.Resources.Limits
+	}
+
+	usage := &PodUsage{
+		Namespace: pod.Namespace,
+		Pod:       pod.Name,
+		Node:      nodeName,
+	}
+	for _, stats := range stats.Containers {
+		container := ContainerUsage{Name: stats.Name}
+		if stats.CPU != nil {
+			container.CPUMillicores = float64(valueOf(stats.CPU.UsageNanoCores)) / 1e6
+		}
+		if stats.Memory != nil {
+			container.MemoryWorkingSetBytes = valueOf(stats.Memory.WorkingSetBytes)
+		}
+		if stats.Rootfs != nil {
+			container.RootfsUsedBytes = valueOf(stats.Rootfs.UsedBytes)
+		}
+		if stats.Logs != nil {
+			container.LogsUsedBytes = valueOf(stats.Logs.UsedBytes)
+		}
+		if limit, ok := limits[stats.Name][corev1.ResourceMemory]; ok {
+			container.MemoryLimitBytes = limit.Value()
+		}
+		if limit, ok := limits[stats.Name][corev1.ResourceEphemeralStorage]; ok {
+			container.EphemeralStorageLimitBytes = limit.Value()
+		}
+		usage.Containers = append(usage.Containers, container)
+	}
+	if stats.Network != nil {
+		usage.NetworkRxBytes, usage.NetworkTxBytes = stats.Network.TotalBytes()
+	}
+	return usage
+}
@@ -0,0 +1,309 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	listersstoragev1 "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/pool"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// Volume alerting defaults
+const (
+	defaultVolumeCheckInterval   = 60 * time.Second
+	defaultPVCPendingGracePeriod = 5 * time.Minute
+)
+
+// annSelectedNode is set on a claim once a pod using it is scheduled to a node
+const annSelectedNode = "volume.kubernetes.io/selected-node"
+
+// VolumeWatcher watches PersistentVolumeClaims through a shared informer and alerts on claims
+// that stay Pending or are Lost. It also periodically reads the volume stats of every node from
+// the kubelet Summary API and evaluates them against the volume rules, which alert on claims
+// running out of space or inodes.
+type VolumeWatcher struct {
+	client         *K8sClient
+	pvcInformer    cache.SharedIndexInformer
+	classInformer  cache.SharedIndexInformer
+	nodeInformer   cache.SharedIndexInformer
+	pvcs           listerscorev1.PersistentVolumeClaimLister
+	storageClasses listersstoragev1.StorageClassLister
+	nodes          listerscorev1.NodeLister
+	queue          *objectQueue
+	summaries      SummaryProvider
+	stateManager   *processor.AlertStateManager
+	ruleEngine     *processor.RuleEngine
+	workerPool     *pool.WorkerPool
+	interval       time.Duration
+	pendingGrace   time.Duration
+	mountedMu      sync.Mutex
+	mounted        map[string]map[string]string // claim key -> subject, of the claims of the last volume check
+	stopCh         chan struct{}
+	wg             sync.WaitGroup
+}
+
+// NewVolumeWatcher creates a new volume watcher reading volume stats from summaries. Unset
+// alert rule settings take their defaults.
+func NewVolumeWatcher(k8sClient *K8sClient, stateManager *processor.AlertStateManager, ruleEngine *processor.RuleEngine, rules config.AlertRulesConfig, summaries SummaryProvider, workerPool *pool.WorkerPool) *VolumeWatcher {
+	factory := k8sClient.GetInformerFactory()
+	core := factory.Core().V1()
+	classes := factory.Storage().V1().StorageClasses()
+	vw := &VolumeWatcher{
+		client:         k8sClient,
+		pvcInformer:    core.PersistentVolumeClaims().Informer(),
+		classInformer:  classes.Informer(),
+		nodeInformer:   core.Nodes().Informer(),
+		pvcs:           core.PersistentVolumeClaims().Lister(),
+		storageClasses: classes.Lister(),
+		nodes:          core.Nodes().Lister(),
+		summaries:      summaries,
+		stateManager:   stateManager,
+		ruleEngine:     ruleEngine,
+		workerPool:     workerPool,
+		interval:       rules.VolumeCheckInterval,
+		pendingGrace:   rules.PVCPendingGracePeriod,
+		mounted:        make(map[string]map[string]string),
+		stopCh:         make(chan struct{}),
+	}
+	if vw.interval <= 0 {
+		vw.interval = defaultVolumeCheckInterval
+	}
+	if vw.pendingGrace <= 0 {
+		vw.pendingGrace = defaultPVCPendingGracePeriod
+	}
+	vw.queue = newObjectQueue("pvc", workerPool, vw.processPVC)
+	return vw
+}
+
+// Start begins watching claims and checking volume usage
+func (vw *VolumeWatcher) Start(ctx context.Context) {
+	logger.Info().
+		Str("interval", vw.interval.String()).
+		Msg("Starting Volume Watcher with informer and worker pool")
+
+	if err := vw.pvcInformer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		logger.Warn().Err(err).Msg("PVC watch failed, informer will relist with backoff")
+	}); err != nil {
+		logger.Warn().Err(err).Msg("Failed to set PVC watch error handler")
+	}
+	if _, err := vw.pvcInformer.AddEventHandler(vw.queue.handler()); err != nil {
+		logger.Error().Err(err).Msg("Failed to register PVC event handler")
+		return
+	}
+
+	vw.wg.Add(2)
+	go vw.queue.run(ctx, vw.stopCh, &vw.wg, func() bool {
+		return vw.pvcInformer.HasSynced() && vw.classInformer.HasSynced()
+	})
+	go vw.statsLoop(ctx)
+}
+
+// processPVC evaluates the latest cached state of a claim
+func (vw *VolumeWatcher) processPVC(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Warn().Err(err).Str("key", key).Msg("Invalid PVC key")
+		return nil
+	}
+
+	subject := map[string]string{
+		"namespace": namespace,
+		"pvc":       name,
+	}
+
+	pvc, err := vw.pvcs.PersistentVolumeClaims(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		vw.ruleEngine.Forget(SourceK8sVolume, subject)
+		_, err := vw.stateManager.ResolveCleared(ctx, SourceK8sPVC, subject, nil)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	var alerts []*models.Alert
+	switch pvc.Status.Phase {
+	case corev1.ClaimLost:
+		alerts = append(alerts, BuildPVCAlert(pvc, AlertTypePVCLost, 1))
+	case corev1.ClaimPending:
+		if vw.waitingForConsumer(pvc) {
+			break
+		}
+		pending := time.Since(pvc.CreationTimestamp.Time)
+		if pending >= vw.pendingGrace {
+			alerts = append(alerts, BuildPVCAlert(pvc, AlertTypePVCPending, pending.Round(time.Second).Seconds()))
+		} else {
+			// Nothing else may change the claim by the time it has been pending too long
+			vw.queue.addAfter(key, vw.pendingGrace-pending)
+		}
+	}
+
+	vw.processAlerts(ctx, alerts)
+
+	_, err = vw.stateManager.ResolveCleared(ctx, SourceK8sPVC, subject, alerts)
+	return err
+}
+
+// waitingForConsumer reports whether the claim's StorageClass only binds a volume once a pod
+// using the claim is scheduled, and none has been yet. Such claims are pending by design.
+func (vw *VolumeWatcher) waitingForConsumer(pvc *corev1.PersistentVolumeClaim) bool {
+	if _, selected := pvc.Annotations[annSelectedNode]; selected || pvc.Spec.StorageClassName == nil {
+		return false
+	}
+	class, err := vw.storageClasses.Get(*pvc.Spec.StorageClassName)
+	if err != nil {
+		return false
+	}
+	return class.VolumeBindingMode != nil && *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer
+}
+
+// statsLoop periodically checks volume usage once the nodes are known
+func (vw *VolumeWatcher) statsLoop(ctx context.Context) {
+	defer vw.wg.Done()
+
+	if !cache.WaitForCacheSync(vw.stopCh, vw.nodeInformer.HasSynced) {
+		return
+	}
+
+	ticker := time.NewTicker(vw.interval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	vw.submitVolumeCheck(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			vw.submitVolumeCheck(ctx)
+		case <-vw.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (vw *VolumeWatcher) submitVolumeCheck(ctx context.Context) {
+	if err := vw.workerPool.SubmitWithContext(ctx, vw.checkVolumes); err != nil {
+		logger.Warn().Err(err).Msg("Failed to submit volume check (worker pool queue full)")
+	}
+}
+
+// checkVolumes reads the volume stats of every node and evaluates the volume rules against them
+func (vw *VolumeWatcher) checkVolumes(ctx context.Context) error {
+	nodes, err := vw.nodes.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	// Alerts of conditions still pending for their rule's duration stay active, like firing ones
+	var active, held []*models.Alert
+	checked := make(map[string]map[string]string) // claim key -> subject
+	complete := true
+
+	for _, node := range nodes {
+		summary, err := vw.summaries.GetNodeSummary(ctx, node.Name)
+		if err != nil {
+			logger.Warn().Err(err).Str("node", node.Name).Msg("Failed to get node summary")
+			complete = false
+			continue
+		}
+
+		for _, pod := range summary.Pods {
+			for _, volume := range pod.VolumeStats {
+				if volume.PVCRef == nil {
+					continue
+				}
+				// A claim mounted by several pods is reported once per pod
+				key := volume.PVCRef.Namespace + "/" + volume.PVCRef.Name
+				if _, ok := checked[key]; ok {
+					continue
+				}
+				checked[key] = map[string]string{"namespace": volume.PVCRef.Namespace, "pvc": volume.PVCRef.Name}
+
+				obs := vw.observeVolume(ctx, pod.PodRef, volume)
+				active = append(active, vw.ruleEngine.Evaluate(obs)...)
+				pending, _ := vw.ruleEngine.PendingFor(obs)
+				held = append(held, pending...)
+			}
+		}
+	}
+
+	logger.Info().Int("node_count", len(nodes)).Int("pvc_count", len(checked)).Msg("Checked volume usage")
+	vw.processAlerts(ctx, active)
+
+	// Resolve usage alerts that dropped back below threshold. An empty subject also covers claims
+	// no longer mounted, but claims on nodes whose stats could not be read keep their alerts.
+	vw.mountedMu.Lock()
+	defer vw.mountedMu.Unlock()
+	if complete {
+		// Claims no longer mounted start over once they are
+		for key, subject := range vw.mounted {
+			if _, ok := checked[key]; !ok {
+				vw.ruleEngine.Forget(SourceK8sVolume, subject)
+			}
+		}
+		vw.mounted = checked
+
+		_, err := vw.stateManager.ResolveCleared(ctx, SourceK8sVolume, map[string]string{}, append(active, held...))
+		return err
+	}
+	for key, subject := range checked {
+		vw.mounted[key] = subject
+		if _, err := vw.stateManager.ResolveCleared(ctx, SourceK8sVolume, subject, append(active, held...)); err != nil {
+			logger.Error().Err(err).Str("pvc", subject["pvc"]).Msg("Failed to resolve volume alerts")
+		}
+	}
+	return nil
+}
+
+// observeVolume builds the observation of a claim's volume with the annotations of its namespace,
+// so they can override thresholds for the claim
+func (vw *VolumeWatcher) observeVolume(ctx context.Context, pod PodReference, volume VolumeStats) *processor.Observation {
+	pvc, err := vw.pvcs.PersistentVolumeClaims(volume.PVCRef.Namespace).Get(volume.PVCRef.Name)
+	if err != nil {
+		pvc = nil
+	}
+	obs := ObserveVolume(pvc, pod, volume)
+	obs.NamespaceAnnotations = vw.client.GetNamespaceCache().Annotations(ctx, obs.Namespace)
+	return obs
+}
+
+// processAlerts passes each alert through the state manager
+func (vw *VolumeWatcher) processAlerts(ctx context.Context, alerts []*models.Alert) {
+	for _, alert := range alerts {
+		created, err := vw.stateManager.ProcessAlert(ctx, alert)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("alert_type", alert.GetLabelsMap()["alert_type"]).
+				Msg("Failed to process alert")
+			continue
+		}
+
+		if created {
+			logger.Warn().
+				Str("source", alert.Source).
+				Str("severity", alert.Severity).
+				Str("message", alert.Message).
+				Msg("New volume alert created")
+		}
+	}
+}
+
+// Stop gracefully stops the volume watcher
+func (vw *VolumeWatcher) Stop() {
+	close(vw.stopCh)
+	vw.wg.Wait()
+}
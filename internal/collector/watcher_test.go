@@ -0,0 +1,661 @@
+package collector_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/collector"
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/pool"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type watcherFixture struct {
+	ctx          context.Context
+	clientset    *fake.Clientset
+	client       *collector.K8sClient
+	alertRepo    repository.AlertRepo
+	stateManager *processor.AlertStateManager
+	ruleEngine   *processor.RuleEngine
+	workerPool   *pool.WorkerPool
+}
+
+func newWatcherFixture(t *testing.T, objects ...corev1.Node) *watcherFixture {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	clientset := fake.NewSimpleClientset()
+	for i := range objects {
+		_, err := clientset.CoreV1().Nodes().Create(ctx, &objects[i], metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	ruleEngine, err := processor.NewRuleEngine(processor.DefaultRules(config.AlertRulesConfig{PodRestartThreshold: 3}))
+	require.NoError(t, err)
+
+	eventBus := processor.NewEventBus()
+	eventBus.Start(ctx)
+	t.Cleanup(eventBus.Stop)
+
+	workerPool := pool.NewWorkerPool(2, 10)
+	workerPool.Start(ctx)
+	t.Cleanup(workerPool.Stop)
+
+	client := collector.NewK8sClientForClientset(clientset, 0)
+	t.Cleanup(client.Stop)
+
+	alertRepo := repository.NewInMemoryAlertRepo()
+	return &watcherFixture{
+		ctx:          ctx,
+		clientset:    clientset,
+		client:       client,
+		alertRepo:    alertRepo,
+		stateManager: processor.NewAlertStateManager(alertRepo, eventBus),
+		ruleEngine:   ruleEngine,
+		workerPool:   workerPool,
+	}
+}
+
+// activeAlertTypes returns the alert types firing for the subject
+func (f *watcherFixture) activeAlertTypes(t *testing.T, source string, subject map[string]string) []string {
+	alerts, err := f.alertRepo.GetActiveByLabels(f.ctx, source, subject)
+	require.NoError(t, err)
+
+	var types []string
+	for _, alert := range alerts {
+		if alert.Status == models.AlertStatusFiring {
+			types = append(types, alert.GetLabelsMap()["alert_type"])
+		}
+	}
+	return types
+}
+
+func crashLoopingPod(restarts int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "production"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: restarts,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off"},
+				},
+			}},
+		},
+	}
+}
+
+func TestPodWatcher(t *testing.T) {
+	f := newWatcherFixture(t)
+	subject := map[string]string{"namespace": "production", "pod": "api-0"}
+
+	// A pod that exists before the watcher starts is picked up by the informer's initial list
+	_, err := f.clientset.CoreV1().Pods("production").Create(f.ctx, crashLoopingPod(1), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	watcher := collector.NewPodWatcher(f.client, f.stateManager, f.ruleEngine, f.workerPool)
+	watcher.Start(f.ctx)
+	f.client.StartInformers()
+	t.Cleanup(watcher.Stop)
+
+	t.Run("should alert on pods listed at startup", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"pod_crash_loop"}, f.activeAlertTypes(t, collector.SourceK8sPod, subject))
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should evaluate the latest state after updates", func(t *testing.T) {
+		_, err := f.clientset.CoreV1().Pods("production").UpdateStatus(f.ctx, crashLoopingPod(5), metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sPod, subject)) == 2
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should resolve alerts when the pod is deleted", func(t *testing.T) {
+		require.NoError(t, f.clientset.CoreV1().Pods("production").Delete(f.ctx, "api-0", metav1.DeleteOptions{}))
+
+		assert.Eventually(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sPod, subject)) == 0
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+}
+
+func TestPodWatcher_KeepsFiringAlertsWhilePending(t *testing.T) {
+	f := newWatcherFixture(t)
+	subject := map[string]string{"namespace": "production", "pod": "api-0"}
+
+	pod, err := f.clientset.CoreV1().Pods("production").Create(f.ctx, crashLoopingPod(1), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// The alert fired before a restart, which loses the pending state of the rule engine
+	for _, alert := range f.ruleEngine.Evaluate(collector.ObservePod(pod)) {
+		_, err := f.stateManager.ProcessAlert(f.ctx, alert)
+		require.NoError(t, err)
+	}
+	firing, err := f.alertRepo.GetActiveByLabels(f.ctx, collector.SourceK8sPod, subject)
+	require.NoError(t, err)
+	require.Len(t, firing, 1)
+
+	ruleEngine, err := processor.NewRuleEngine(processor.DefaultRules(config.AlertRulesConfig{
+		PodRestartThreshold: 3,
+		For:                 map[string]time.Duration{"pod_crash_loop": 300 * time.Millisecond},
+	}))
+	require.NoError(t, err)
+	watcher := collector.NewPodWatcher(f.client, f.stateManager, ruleEngine, f.workerPool)
+	watcher.Start(f.ctx)
+	f.client.StartInformers()
+	t.Cleanup(watcher.Stop)
+
+	assert.Eventually(t, func() bool {
+		return len(ruleEngine.Pending()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The pod is evaluated again once the condition is due to fire, without changing
+	assert.Eventually(t, func() bool {
+		return len(ruleEngine.Pending()) == 0
+	}, 5*time.Second, 20*time.Millisecond)
+
+	stored, err := f.alertRepo.GetByID(f.ctx, firing[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.AlertStatusFiring, stored.Status)
+	assert.Nil(t, stored.ResolvedAt)
+}
+
+func TestNodeWatcher(t *testing.T) {
+	f := newWatcherFixture(t, corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Reason: "KubeletNotReady"}},
+		},
+	})
+	subject := map[string]string{"node": "worker-1"}
+	maintenance := processor.NewMaintenanceSuppressor(repository.NewInMemoryMaintenanceRepo())
+
+	watcher := collector.NewNodeWatcher(f.client, f.stateManager, f.ruleEngine, maintenance, f.workerPool)
+	watcher.Start(f.ctx)
+	f.client.StartInformers()
+	t.Cleanup(watcher.Stop)
+
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"node_not_ready"}, f.activeAlertTypes(t, collector.SourceK8sNode, subject))
+	}, 5*time.Second, 20*time.Millisecond)
+
+	node, err := f.clientset.CoreV1().Nodes().Get(f.ctx, "worker-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	node.Spec.Unschedulable = true
+	node.Status.Conditions[0].Status = corev1.ConditionTrue
+	_, err = f.clientset.CoreV1().Nodes().Update(f.ctx, node, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return maintenance.IsNodeCordoned("worker-1") && len(f.activeAlertTypes(t, collector.SourceK8sNode, subject)) == 0
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, f.clientset.CoreV1().Nodes().Delete(f.ctx, "worker-1", metav1.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		return !maintenance.IsNodeCordoned("worker-1")
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestWorkloadWatcher(t *testing.T) {
+	f := newWatcherFixture(t)
+	replicas := int32(3)
+	deploymentSubject := map[string]string{"namespace": "production", "workload_kind": "Deployment", "workload": "api"}
+	statefulSetSubject := map[string]string{"namespace": "production", "workload_kind": "StatefulSet", "workload": "db"}
+
+	// A stuck rollout alerts at once; replicas missing mid-rollout only after the grace period
+	_, err := f.clientset.AppsV1().Deployments("production").Create(f.ctx, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "production"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			AvailableReplicas: 1,
+			Conditions: []appsv1.DeploymentCondition{{
+				Type:   appsv1.DeploymentProgressing,
+				Status: corev1.ConditionFalse,
+				Reason: "ProgressDeadlineExceeded",
+			}},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = f.clientset.AppsV1().StatefulSets("production").Create(f.ctx, &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "production"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 2},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	ruleEngine, err := processor.NewRuleEngine(processor.DefaultRules(config.AlertRulesConfig{WorkloadGracePeriod: 300 * time.Millisecond}))
+	require.NoError(t, err)
+	watcher := collector.NewWorkloadWatcher(f.client, f.stateManager, ruleEngine, f.workerPool)
+	watcher.Start(f.ctx)
+	f.client.StartInformers()
+	t.Cleanup(watcher.Stop)
+
+	t.Run("should alert on stuck rollouts without waiting", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"deployment_rollout_stuck"}, f.activeAlertTypes(t, collector.SourceK8sWorkload, deploymentSubject))
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should alert on degraded workloads once the grace period has passed", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sWorkload, deploymentSubject)) == 2 &&
+				assert.ObjectsAreEqual([]string{"statefulset_replicas_not_ready"}, f.activeAlertTypes(t, collector.SourceK8sWorkload, statefulSetSubject))
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should resolve alerts when the workload recovers", func(t *testing.T) {
+		statefulSet, err := f.clientset.AppsV1().StatefulSets("production").Get(f.ctx, "db", metav1.GetOptions{})
+		require.NoError(t, err)
+		statefulSet.Status.ReadyReplicas = 3
+		_, err = f.clientset.AppsV1().StatefulSets("production").UpdateStatus(f.ctx, statefulSet, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sWorkload, statefulSetSubject)) == 0
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should resolve alerts when the workload is deleted", func(t *testing.T) {
+		require.NoError(t, f.clientset.AppsV1().Deployments("production").Delete(f.ctx, "api", metav1.DeleteOptions{}))
+
+		assert.Eventually(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sWorkload, deploymentSubject)) == 0
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+}
+
+func TestJobWatcher(t *testing.T) {
+	f := newWatcherFixture(t)
+	suspend := true
+	hoursAgo := func(hours int) *metav1.Time {
+		at := metav1.NewTime(time.Now().Add(-time.Duration(hours) * time.Hour))
+		return &at
+	}
+	jobSubject := func(name string) map[string]string { return map[string]string{"namespace": "batch", "job": name} }
+	cronJobSubject := func(name string) map[string]string { return map[string]string{"namespace": "batch", "cronjob": name} }
+
+	// An hourly cronjob that last succeeded three hours ago, with a failed run
+	_, err := f.clientset.BatchV1().CronJobs("batch").Create(f.ctx, &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "batch", CreationTimestamp: *hoursAgo(24)},
+		Spec:       batchv1.CronJobSpec{Schedule: "@hourly"},
+		Status:     batchv1.CronJobStatus{LastSuccessfulTime: hoursAgo(3)},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = f.clientset.BatchV1().CronJobs("batch").Create(f.ctx, &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "paused", Namespace: "batch", CreationTimestamp: *hoursAgo(24)},
+		Spec:       batchv1.CronJobSpec{Schedule: "@hourly", Suspend: &suspend},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = f.clientset.BatchV1().Jobs("batch").Create(f.ctx, &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "sync-1",
+			Namespace:       "batch",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: "CronJob", Name: "sync", UID: "sync-uid"}},
+		},
+		Status: batchv1.JobStatus{
+			StartTime:  hoursAgo(1),
+			Failed:     7,
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"}},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = f.clientset.BatchV1().Jobs("batch").Create(f.ctx, &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "backfill", Namespace: "batch"},
+		Status:     batchv1.JobStatus{StartTime: hoursAgo(2), Active: 1},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	watcher := collector.NewJobWatcher(f.client, f.stateManager, config.AlertRulesConfig{JobMaxDuration: time.Hour}, f.workerPool)
+	watcher.Start(f.ctx)
+	f.client.StartInformers()
+	t.Cleanup(watcher.Stop)
+
+	t.Run("should alert on failed and overrunning jobs", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"job_failed"}, f.activeAlertTypes(t, collector.SourceK8sJob, jobSubject("sync-1"))) &&
+				assert.ObjectsAreEqual([]string{"job_running_too_long"}, f.activeAlertTypes(t, collector.SourceK8sJob, jobSubject("backfill")))
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should alert on cronjobs missing runs or suspended", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"cronjob_missed_schedule"}, f.activeAlertTypes(t, collector.SourceK8sCronJob, cronJobSubject("sync"))) &&
+				assert.ObjectsAreEqual([]string{"cronjob_suspended"}, f.activeAlertTypes(t, collector.SourceK8sCronJob, cronJobSubject("paused")))
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should resolve the cronjob and its failed run once a later run succeeds", func(t *testing.T) {
+		cronJob, err := f.clientset.BatchV1().CronJobs("batch").Get(f.ctx, "sync", metav1.GetOptions{})
+		require.NoError(t, err)
+		now := metav1.Now()
+		cronJob.Status.LastSuccessfulTime = &now
+		_, err = f.clientset.BatchV1().CronJobs("batch").UpdateStatus(f.ctx, cronJob, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sCronJob, cronJobSubject("sync"))) == 0 &&
+				len(f.activeAlertTypes(t, collector.SourceK8sJob, jobSubject("sync-1"))) == 0
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+}
+
+// stubSummaries serves node summaries from memory
+type stubSummaries struct {
+	mu        sync.Mutex
+	summaries map[string]*collector.NodeSummary
+}
+
+func (s *stubSummaries) GetNodeSummary(ctx context.Context, nodeName string) (*collector.NodeSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if summary, ok := s.summaries[nodeName]; ok {
+		return summary, nil
+	}
+	return nil, errors.New("node unreachable")
+}
+
+func (s *stubSummaries) setVolumeUsage(nodeName, namespace, pod, pvc string, used uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	capacity, inodes, inodesUsed := uint64(100), uint64(1000), uint64(10)
+	s.summaries[nodeName] = &collector.NodeSummary{
+		Node: collector.NodeStats{NodeName: nodeName},
+		Pods: []collector.PodStats{{
+			PodRef: collector.PodReference{Name: pod, Namespace: namespace},
+			VolumeStats: []collector.VolumeStats{{
+				Name:    "data",
+				PVCRef:  &collector.PVCReference{Name: pvc, Namespace: namespace},
+				FsStats: collector.FsStats{CapacityBytes: &capacity, UsedBytes: &used, Inodes: &inodes, InodesUsed: &inodesUsed},
+			}},
+		}},
+	}
+}
+
+func TestVolumeWatcher(t *testing.T) {
+	f := newWatcherFixture(t, corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}})
+	pvcSubject := func(name string) map[string]string { return map[string]string{"namespace": "db", "pvc": name} }
+	immediate, waitForConsumer := storagev1.VolumeBindingImmediate, storagev1.VolumeBindingWaitForFirstConsumer
+	standard, local := "standard", "local"
+	anHourAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	for _, class := range []*storagev1.StorageClass{
+		{ObjectMeta: metav1.ObjectMeta{Name: standard}, VolumeBindingMode: &immediate},
+		{ObjectMeta: metav1.ObjectMeta{Name: local}, VolumeBindingMode: &waitForConsumer},
+	} {
+		_, err := f.clientset.StorageV1().StorageClasses().Create(f.ctx, class, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	for _, pvc := range []*corev1.PersistentVolumeClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unbound", Namespace: "db", CreationTimestamp: anHourAgo},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &standard},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unused", Namespace: "db", CreationTimestamp: anHourAgo},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &local},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphaned", Namespace: "db", CreationTimestamp: anHourAgo},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &standard, VolumeName: "pv-gone"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimLost},
+		},
+	} {
+		_, err := f.clientset.CoreV1().PersistentVolumeClaims("db").Create(f.ctx, pvc, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	summaries := &stubSummaries{summaries: map[string]*collector.NodeSummary{}}
+	summaries.setVolumeUsage("worker-1", "db", "postgres-0", "data-postgres-0", 92)
+
+	watcher := collector.NewVolumeWatcher(f.client, f.stateManager, f.ruleEngine, config.AlertRulesConfig{VolumeCheckInterval: 50 * time.Millisecond}, summaries, f.workerPool)
+	watcher.Start(f.ctx)
+	f.client.StartInformers()
+	t.Cleanup(watcher.Stop)
+
+	t.Run("should alert on pending and lost claims unless waiting for a consumer", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"pvc_pending"}, f.activeAlertTypes(t, collector.SourceK8sPVC, pvcSubject("unbound"))) &&
+				assert.ObjectsAreEqual([]string{"pvc_lost"}, f.activeAlertTypes(t, collector.SourceK8sPVC, pvcSubject("orphaned")))
+		}, 5*time.Second, 20*time.Millisecond)
+		assert.Empty(t, f.activeAlertTypes(t, collector.SourceK8sPVC, pvcSubject("unused")))
+	})
+
+	t.Run("should alert on volumes above the usage threshold", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"volume_usage_high"}, f.activeAlertTypes(t, collector.SourceK8sVolume, pvcSubject("data-postgres-0")))
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should resolve usage alerts once the volume is freed up", func(t *testing.T) {
+		summaries.setVolumeUsage("worker-1", "db", "postgres-0", "data-postgres-0", 40)
+
+		assert.Eventually(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sVolume, pvcSubject("data-postgres-0"))) == 0
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should resolve claim alerts once the claim is bound", func(t *testing.T) {
+		pvc, err := f.clientset.CoreV1().PersistentVolumeClaims("db").Get(f.ctx, "unbound", metav1.GetOptions{})
+		require.NoError(t, err)
+		pvc.Status.Phase = corev1.ClaimBound
+		_, err = f.clientset.CoreV1().PersistentVolumeClaims("db").UpdateStatus(f.ctx, pvc, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sPVC, pvcSubject("unbound"))) == 0
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+}
+
+func TestSummaryWatcher(t *testing.T) {
+	f := newWatcherFixture(t, corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}})
+	broadcaster := &recordingBroadcaster{}
+	podSubject := map[string]string{"namespace": "production", "pod": "api-0"}
+
+	_, err := f.clientset.CoreV1().Pods("production").Create(f.ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "production"},
+		Spec: corev1.PodSpec{
+			NodeName: "worker-1",
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("1000"),
+				}},
+			}},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	summaries := &stubSummaries{summaries: map[string]*collector.NodeSummary{}}
+	setMemory := func(workingSet uint64) {
+		cpu, rx := uint64(200_000_000), uint64(4096)
+		summaries.mu.Lock()
+		defer summaries.mu.Unlock()
+		summaries.summaries["worker-1"] = &collector.NodeSummary{
+			Node: collector.NodeStats{NodeName: "worker-1"},
+			Pods: []collector.PodStats{{
+				PodRef: collector.PodReference{Name: "api-0", Namespace: "production"},
+				Containers: []collector.ContainerStats{{
+					Name:   "app",
+					CPU:    &collector.CPUStats{UsageNanoCores: &cpu},
+					Memory: &collector.MemoryStats{WorkingSetBytes: &workingSet},
+				}},
+				Network: &collector.NetworkStats{InterfaceStats: collector.InterfaceStats{Name: "eth0", RxBytes: &rx}},
+			}},
+		}
+	}
+	setMemory(960)
+
+	watcher := collector.NewSummaryWatcher(f.client, f.stateManager, f.ruleEngine, summaries, broadcaster,
+		config.AlertRulesConfig{ContainerCheckInterval: 50 * time.Millisecond}, f.workerPool)
+	watcher.Start(f.ctx)
+	f.client.StartInformers()
+	t.Cleanup(watcher.Stop)
+
+	t.Run("should stream container usage and alert on containers near their memory limit", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"container_memory_near_limit"}, f.activeAlertTypes(t, collector.SourceK8sContainerMetrics, podSubject))
+		}, 5*time.Second, 20*time.Millisecond)
+
+		usage := broadcaster.latestUsage()
+		require.NotNil(t, usage)
+		assert.Equal(t, "worker-1", usage.Node)
+		require.Len(t, usage.Pods, 1)
+		assert.Equal(t, uint64(4096), usage.Pods[0].NetworkRxBytes)
+		assert.Equal(t, []collector.ContainerUsage{{
+			Name:                  "app",
+			CPUMillicores:         200,
+			MemoryWorkingSetBytes: 960,
+			MemoryLimitBytes:      1000,
+		}}, usage.Pods[0].Containers)
+	})
+
+	t.Run("should resolve the alert once memory drops", func(t *testing.T) {
+		setMemory(400)
+
+		assert.Eventually(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sContainerMetrics, podSubject)) == 0
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+}
+
+// recordingBroadcaster records the payloads broadcast to it
+type recordingBroadcaster struct {
+	mu       sync.Mutex
+	messages []*collector.K8sEvent
+	usage    []*collector.NodeUsage
+}
+
+func (b *recordingBroadcaster) BroadcastJSON(msgType string, payload interface{}) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch msgType {
+	case collector.WSMessageTypeK8sEvent:
+		b.messages = append(b.messages, payload.(*collector.K8sEvent))
+	case collector.WSMessageTypeMetric:
+		b.usage = append(b.usage, payload.(*collector.NodeUsage))
+	}
+	return nil
+}
+
+func (b *recordingBroadcaster) latestUsage() *collector.NodeUsage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.usage) == 0 {
+		return nil
+	}
+	return b.usage[len(b.usage)-1]
+}
+
+func (b *recordingBroadcaster) reasons() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var reasons []string
+	for _, msg := range b.messages {
+		reasons = append(reasons, msg.Reason)
+	}
+	return reasons
+}
+
+func TestEventWatcher(t *testing.T) {
+	f := newWatcherFixture(t)
+	broadcaster := &recordingBroadcaster{}
+	podSubject := map[string]string{"namespace": "production", "pod": "api-0"}
+
+	event := func(name, eventType, reason string, count int32) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "production"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "production", Name: "api-0"},
+			Type:           eventType,
+			Reason:         reason,
+			Message:        reason + " happened",
+			Count:          count,
+			FirstTimestamp: metav1.Now(),
+			LastTimestamp:  metav1.Now(),
+		}
+	}
+
+	watcher, err := collector.NewEventWatcher(f.client, f.stateManager, broadcaster, config.EventsConfig{
+		Window: 2 * time.Second,
+		Reasons: map[string]config.EventReasonConfig{
+			"FailedScheduling": {Severity: "high"},
+			"Unhealthy":        {Severity: "medium", MinCount: 3},
+		},
+	}, f.workerPool)
+	require.NoError(t, err)
+	watcher.Start(f.ctx)
+	f.client.StartInformers()
+	t.Cleanup(watcher.Stop)
+
+	_, err = f.clientset.CoreV1().Events("production").Create(f.ctx, event("api-0.1", corev1.EventTypeNormal, "Scheduled", 1), metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = f.clientset.CoreV1().Events("production").Create(f.ctx, event("api-0.2", corev1.EventTypeWarning, "FailedScheduling", 1), metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = f.clientset.CoreV1().Events("production").Create(f.ctx, event("api-0.3", corev1.EventTypeWarning, "Unhealthy", 2), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	t.Run("should stream warning events and alert on mapped reasons", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"event_failed_scheduling"}, f.activeAlertTypes(t, collector.SourceK8sEvent, podSubject)) &&
+				len(broadcaster.reasons()) == 2
+		}, 5*time.Second, 20*time.Millisecond)
+		assert.ElementsMatch(t, []string{"FailedScheduling", "Unhealthy"}, broadcaster.reasons())
+	})
+
+	t.Run("should alert once enough events are aggregated", func(t *testing.T) {
+		_, err := f.clientset.CoreV1().Events("production").Create(f.ctx, event("api-0.4", corev1.EventTypeWarning, "Unhealthy", 1), metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sEvent, podSubject)) == 2
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should only count the occurrences of a long-running event within the window", func(t *testing.T) {
+		probeSubject := map[string]string{"namespace": "production", "pod": "api-1"}
+		probe := event("api-1.1", corev1.EventTypeWarning, "Unhealthy", 40)
+		probe.InvolvedObject.Name = "api-1"
+		probe.FirstTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+		probe, err := f.clientset.CoreV1().Events("production").Create(f.ctx, probe, metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		assert.Never(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sEvent, probeSubject)) > 0
+		}, 300*time.Millisecond, 20*time.Millisecond)
+
+		probe.Count = 42
+		probe.LastTimestamp = metav1.Now()
+		_, err = f.clientset.CoreV1().Events("production").Update(f.ctx, probe, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"event_unhealthy"}, f.activeAlertTypes(t, collector.SourceK8sEvent, probeSubject))
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should resolve alerts once the window passes without events", func(t *testing.T) {
+		assert.Eventually(t, func() bool {
+			return len(f.activeAlertTypes(t, collector.SourceK8sEvent, podSubject)) == 0
+		}, 10*time.Second, 50*time.Millisecond)
+	})
+}
@@ -0,0 +1,208 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	listersappsv1 "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/pool"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// defaultProgressDeadline is the progressDeadlineSeconds of a deployment that does not set it
+const defaultProgressDeadline = 600 // seconds, the Kubernetes default
+
+// workloadKind is one kind of workload the workload watcher follows
+type workloadKind struct {
+	kind     string
+	informer cache.SharedIndexInformer
+	queue    *objectQueue
+}
+
+// WorkloadWatcher watches Deployments, StatefulSets and DaemonSets through shared informers
+// and evaluates them against the workload rules on the worker pool
+type WorkloadWatcher struct {
+	client       *K8sClient
+	deployments  listersappsv1.DeploymentLister
+	statefulSets listersappsv1.StatefulSetLister
+	daemonSets   listersappsv1.DaemonSetLister
+	kinds        []workloadKind
+	stateManager *processor.AlertStateManager
+	ruleEngine   *processor.RuleEngine
+	stopCh       chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewWorkloadWatcher creates a new workload watcher
+func NewWorkloadWatcher(k8sClient *K8sClient, stateManager *processor.AlertStateManager, ruleEngine *processor.RuleEngine, workerPool *pool.WorkerPool) *WorkloadWatcher {
+	apps := k8sClient.GetInformerFactory().Apps().V1()
+	ww := &WorkloadWatcher{
+		client:       k8sClient,
+		deployments:  apps.Deployments().Lister(),
+		statefulSets: apps.StatefulSets().Lister(),
+		daemonSets:   apps.DaemonSets().Lister(),
+		stateManager: stateManager,
+		ruleEngine:   ruleEngine,
+		stopCh:       make(chan struct{}),
+	}
+
+	ww.kinds = []workloadKind{
+		{kind: K8sResourceTypeDeployment, informer: apps.Deployments().Informer()},
+		{kind: K8sResourceTypeStatefulSet, informer: apps.StatefulSets().Informer()},
+		{kind: K8sResourceTypeDaemonSet, informer: apps.DaemonSets().Informer()},
+	}
+	ww.kinds[0].queue = newObjectQueue("deployment", workerPool, ww.processDeployment)
+	ww.kinds[1].queue = newObjectQueue("statefulset", workerPool, ww.processStatefulSet)
+	ww.kinds[2].queue = newObjectQueue("daemonset", workerPool, ww.processDaemonSet)
+	return ww
+}
+
+// Start begins watching workloads. Each informer lists its workloads, then watches from that
+// resourceVersion, relisting with backoff whenever the watch breaks.
+func (ww *WorkloadWatcher) Start(ctx context.Context) {
+	logger.Info().Msg("Starting Workload Watcher with informers and worker pool")
+
+	for _, k := range ww.kinds {
+		kind := k.kind
+		if err := k.informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+			logger.Warn().Err(err).Str("kind", kind).Msg("Workload watch failed, informer will relist with backoff")
+		}); err != nil {
+			logger.Warn().Err(err).Str("kind", kind).Msg("Failed to set workload watch error handler")
+		}
+		if _, err := k.informer.AddEventHandler(k.queue.handler()); err != nil {
+			logger.Error().Err(err).Str("kind", kind).Msg("Failed to register workload event handler")
+			return
+		}
+	}
+
+	for _, k := range ww.kinds {
+		ww.wg.Add(1)
+		go k.queue.run(ctx, ww.stopCh, &ww.wg, k.informer.HasSynced)
+	}
+}
+
+// processDeployment evaluates the latest cached state of a deployment
+func (ww *WorkloadWatcher) processDeployment(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Warn().Err(err).Str("key", key).Msg("Invalid deployment key")
+		return nil
+	}
+
+	deployment, err := ww.deployments.Deployments(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return ww.forget(ctx, K8sResourceTypeDeployment, namespace, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ww.evaluate(ctx, ww.kinds[0], ObserveDeployment(deployment))
+}
+
+// processStatefulSet evaluates the latest cached state of a statefulset
+func (ww *WorkloadWatcher) processStatefulSet(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Warn().Err(err).Str("key", key).Msg("Invalid statefulset key")
+		return nil
+	}
+
+	statefulSet, err := ww.statefulSets.StatefulSets(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return ww.forget(ctx, K8sResourceTypeStatefulSet, namespace, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ww.evaluate(ctx, ww.kinds[1], ObserveStatefulSet(statefulSet))
+}
+
+// processDaemonSet evaluates the latest cached state of a daemonset
+func (ww *WorkloadWatcher) processDaemonSet(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Warn().Err(err).Str("key", key).Msg("Invalid daemonset key")
+		return nil
+	}
+
+	daemonSet, err := ww.daemonSets.DaemonSets(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return ww.forget(ctx, K8sResourceTypeDaemonSet, namespace, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ww.evaluate(ctx, ww.kinds[2], ObserveDaemonSet(daemonSet))
+}
+
+// evaluate raises the alerts of the rules that fire for the workload and resolves its other
+// alerts. Namespace annotations are included so they can override thresholds for the workload.
+// A condition still pending for its rule's duration keeps an alert that already fires for it, so
+// restarting the watcher does not resolve and re-raise it, and the workload is evaluated again
+// once the condition is due to fire.
+func (ww *WorkloadWatcher) evaluate(ctx context.Context, k workloadKind, obs *processor.Observation) error {
+	namespace, name := obs.Namespace, obs.Workload
+	obs.NamespaceAnnotations = ww.client.GetNamespaceCache().Annotations(ctx, namespace)
+
+	alerts := ww.ruleEngine.Evaluate(obs)
+	pending, firesAt := ww.ruleEngine.PendingFor(obs)
+	if !firesAt.IsZero() {
+		k.queue.addAfter(namespace+"/"+name, time.Until(firesAt))
+	}
+
+	for _, alert := range alerts {
+		created, err := ww.stateManager.ProcessAlert(ctx, alert)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("kind", k.kind).
+				Str("workload", name).
+				Str("alert_type", alert.GetLabelsMap()["alert_type"]).
+				Msg("Failed to process alert")
+			continue
+		}
+
+		if created {
+			logger.Warn().
+				Str("kind", k.kind).
+				Str("workload", name).
+				Str("namespace", namespace).
+				Str("severity", alert.Severity).
+				Str("message", alert.Message).
+				Msg("New workload alert created")
+		}
+	}
+
+	// Resolve alerts whose condition is no longer present
+	_, err := ww.stateManager.ResolveCleared(ctx, SourceK8sWorkload, workloadSubject(k.kind, namespace, name), append(alerts, pending...))
+	return err
+}
+
+// forget resolves everything firing for a deleted workload, which can no longer be unhealthy
+func (ww *WorkloadWatcher) forget(ctx context.Context, kind, namespace, name string) error {
+	subject := workloadSubject(kind, namespace, name)
+	ww.ruleEngine.Forget(SourceK8sWorkload, subject)
+	_, err := ww.stateManager.ResolveCleared(ctx, SourceK8sWorkload, subject, nil)
+	return err
+}
+
+// Stop gracefully stops the workload watcher
+func (ww *WorkloadWatcher) Stop() {
+	close(ww.stopCh)
+	ww.wg.Wait()
+}
+
+func workloadSubject(kind, namespace, name string) map[string]string {
+	return map[string]string{
+		"namespace":     namespace,
+		"workload_kind": kind,
+		"workload":      name,
+	}
+}
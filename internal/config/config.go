@@ -12,12 +12,19 @@ import (
 
 // Config represents the entire application configuration
 type Config struct {
-	Server       ServerConfig       `yaml:"server"`
-	Postgres     PostgresConfig     `yaml:"postgres"`
-	Kubernetes   KubernetesConfig   `yaml:"kubernetes"`
-	Logging      LoggingConfig      `yaml:"logging"`
-	Email        EmailConfig        `yaml:"email"`
-	AlertRules   AlertRulesConfig   `yaml:"alert_rules"`
+	Server       ServerConfig        `yaml:"server"`
+	Postgres     PostgresConfig      `yaml:"postgres"`
+	Kubernetes   KubernetesConfig    `yaml:"kubernetes"`
+	Logging      LoggingConfig       `yaml:"logging"`
+	Email        EmailConfig         `yaml:"email"`
+	Slack        SlackConfig         `yaml:"slack"`
+	Webhooks     WebhooksConfig      `yaml:"webhooks"`
+	PagerDuty    PagerDutyConfig     `yaml:"pagerduty"`
+	Routing      RoutingConfig       `yaml:"routing"`
+	Delivery     DeliveryConfig      `yaml:"delivery"`
+	AlertRules   AlertRulesConfig    `yaml:"alert_rules"`
+	Events       EventsConfig        `yaml:"events"`
+	InhibitRules []InhibitRuleConfig `yaml:"inhibit_rules"`
 }
 
 type ServerConfig struct {
@@ -40,6 +47,7 @@ type KubernetesConfig struct {
 	InCluster       bool   `yaml:"in_cluster"`
 	ConfigPath      string `yaml:"config_path"`
 	MetricsInterval int    `yaml:"metrics_interval"`
+	ResyncPeriod    int    `yaml:"resync_period"` // Seconds between informer resyncs that re-evaluate every pod and node
 }
 
 type LoggingConfig struct {
@@ -49,13 +57,151 @@ type LoggingConfig struct {
 }
 
 type EmailConfig struct {
-	Enabled  bool     `yaml:"enabled"`
-	SMTPHost string   `yaml:"smtp_host"`
-	SMTPPort int      `yaml:"smtp_port"`
-	Username string   `yaml:"username"`
-	Password string   `yaml:"password"`
-	From     string   `yaml:"from"`
-	To       []string `yaml:"to"`
+	Enabled            bool              `yaml:"enabled"`
+	SMTPHost           string            `yaml:"smtp_host"`
+	SMTPPort           int               `yaml:"smtp_port"`
+	Username           string            `yaml:"username"`
+	Password           string            `yaml:"password"`
+	From               string            `yaml:"from"`
+	To                 []string          `yaml:"to"`
+	TLS                string            `yaml:"tls"`                  // auto uses STARTTLS when offered (default), starttls requires it, tls is implicit TLS, none never encrypts
+	InsecureSkipVerify bool              `yaml:"insecure_skip_verify"` // accept any server certificate, for testing only
+	TemplateFile       string            `yaml:"template_file"`        // overrides the email.subject, email.text and email.html templates
+	Digest             EmailDigestConfig `yaml:"digest"`
+}
+
+// EmailDigestConfig sends one summary of the alerts of each interval instead of one email per alert
+type EmailDigestConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"` // defaults to 1h
+}
+
+// SlackConfig configures Slack notifications. With only a webhook URL alerts are posted
+// through an incoming webhook; with a bot token they are posted with chat.postMessage,
+// which lets resolutions update the original message and reply in its thread.
+type SlackConfig struct {
+	Enabled          bool              `yaml:"enabled"`
+	WebhookURL       string            `yaml:"webhook_url"`
+	SeverityWebhooks map[string]string `yaml:"severity_webhooks"` // severity -> incoming webhook URL
+	BotToken         string            `yaml:"bot_token"`
+	Channel          string            `yaml:"channel"`           // default channel for the bot token
+	SeverityChannels map[string]string `yaml:"severity_channels"` // severity -> channel for the bot token
+	APIURL           string            `yaml:"api_url"`           // Slack Web API base URL, defaults to https://slack.com/api
+	DashboardURL     string            `yaml:"dashboard_url"`     // linked from messages
+}
+
+// WebhooksConfig configures generic JSON webhook notifications
+type WebhooksConfig struct {
+	Enabled   bool              `yaml:"enabled"`
+	Endpoints []WebhookEndpoint `yaml:"endpoints"`
+}
+
+// WebhookEndpoint is one HTTP endpoint receiving alert notifications. URL, header values and
+// the secret may refer to environment variables as ${NAME}.
+type WebhookEndpoint struct {
+	Name         string            `yaml:"name"`
+	URL          string            `yaml:"url"`
+	Method       string            `yaml:"method"` // defaults to POST
+	Headers      map[string]string `yaml:"headers"`
+	Secret       string            `yaml:"secret"`        // signs the body with HMAC-SHA256 when set
+	Template     string            `yaml:"template"`      // text/template body; defaults to the alert as JSON
+	Timeout      time.Duration     `yaml:"timeout"`       // per attempt, defaults to 10s
+	MaxRetries   int               `yaml:"max_retries"`   // retries after the first attempt
+	RetryBackoff time.Duration     `yaml:"retry_backoff"` // doubled after every retry, defaults to 1s
+	Severities   []string          `yaml:"severities"`    // only these severities, all when empty
+	Sources      []string          `yaml:"sources"`       // only these alert sources, all when empty
+}
+
+// PagerDutyConfig configures paging through the PagerDuty Events API v2
+type PagerDutyConfig struct {
+	Enabled      bool     `yaml:"enabled"`
+	RoutingKey   string   `yaml:"routing_key"`   // integration key of the PagerDuty service
+	EventsURL    string   `yaml:"events_url"`    // defaults to https://events.pagerduty.com/v2/enqueue
+	Severities   []string `yaml:"severities"`    // alert severities that page, defaults to critical
+	DashboardURL string   `yaml:"dashboard_url"` // linked from incidents
+}
+
+// DeliveryConfig configures the notification outbox. Notifications are queued with the alert
+// change that caused them and delivered by a background worker that retries failures.
+type DeliveryConfig struct {
+	PollInterval   time.Duration `yaml:"poll_interval"`   // how often the outbox is checked for due notifications, default 2s
+	Timeout        time.Duration `yaml:"timeout"`         // per delivery attempt, default 30s
+	MaxAttempts    int           `yaml:"max_attempts"`    // attempts before a notification is dead-lettered, default 8
+	InitialBackoff time.Duration `yaml:"initial_backoff"` // delay before the first retry, doubled per attempt, default 10s
+	MaxBackoff     time.Duration `yaml:"max_backoff"`     // longest delay between retries, default 10m
+	BatchSize      int           `yaml:"batch_size"`      // notifications delivered per poll, default 50
+}
+
+// EventsConfig configures the Kubernetes Event watcher. Warning events are streamed to
+// WebSocket clients and aggregated per involved object and reason; reasons listed here alert
+// once enough of their events are seen within the window.
+type EventsConfig struct {
+	Enabled bool                         `yaml:"enabled"`
+	Window  time.Duration                `yaml:"window"`  // how long events count towards an alert, default 10m; alerts resolve when it passes without events
+	Reasons map[string]EventReasonConfig `yaml:"reasons"` // event reason -> alert; defaults to common pod and volume failures
+}
+
+// EventReasonConfig is the alert raised for Warning events with one reason
+type EventReasonConfig struct {
+	Severity string `yaml:"severity"`
+	MinCount int    `yaml:"min_count"` // events within the window before the alert fires, default 1
+}
+
+// RoutingConfig configures the notification routing tree. When enabled, alerts are sent to
+// the receivers their route selects instead of to every configured notifier.
+type RoutingConfig struct {
+	Enabled   bool             `yaml:"enabled"`
+	Route     RouteConfig      `yaml:"route"`
+	Receivers []ReceiverConfig `yaml:"receivers"`
+}
+
+// RouteConfig is a node of the routing tree. An alert descends into the first child route
+// whose matchers all match, and into later siblings too while matched routes set continue.
+// Receiver, group_by and the timing settings are inherited from the parent when unset.
+type RouteConfig struct {
+	Receiver       string        `yaml:"receiver"`
+	Matchers       []string      `yaml:"matchers"` // label=value, label!=value, label=~regex or label!~regex
+	Continue       bool          `yaml:"continue"`
+	GroupBy        []string      `yaml:"group_by"`        // labels whose values group alerts into one notification; empty notifies per alert
+	GroupWait      time.Duration `yaml:"group_wait"`      // delay before the first notification of a group
+	GroupInterval  time.Duration `yaml:"group_interval"`  // minimum time between notifications about changes to a group
+	RepeatInterval time.Duration `yaml:"repeat_interval"` // resend groups with unacknowledged firing alerts this often, 0 never
+	Routes         []RouteConfig `yaml:"routes"`
+}
+
+// ReceiverConfig is a named set of notification targets. Connection settings such as the
+// SMTP server, Slack bot token or PagerDuty events URL come from the top-level sections.
+type ReceiverConfig struct {
+	Name      string                   `yaml:"name"`
+	Email     *EmailReceiverConfig     `yaml:"email"`
+	Slack     *SlackReceiverConfig     `yaml:"slack"`
+	PagerDuty *PagerDutyReceiverConfig `yaml:"pagerduty"`
+	Webhooks  []WebhookEndpoint        `yaml:"webhooks"`
+}
+
+// EmailReceiverConfig sends a receiver's alerts to its own recipients
+type EmailReceiverConfig struct {
+	To []string `yaml:"to"`
+}
+
+// SlackReceiverConfig posts a receiver's alerts to its own channel or incoming webhook
+type SlackReceiverConfig struct {
+	Channel    string `yaml:"channel"`
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+// PagerDutyReceiverConfig pages a receiver's own PagerDuty service
+type PagerDutyReceiverConfig struct {
+	RoutingKey string   `yaml:"routing_key"`
+	Severities []string `yaml:"severities"`
+}
+
+// InhibitRuleConfig mutes alerts matching all target matchers while an active alert matching
+// all source matchers has the same values for the equal labels
+type InhibitRuleConfig struct {
+	SourceMatchers []string `yaml:"source_matchers"` // label=value, label!=value, label=~regex or label!~regex
+	TargetMatchers []string `yaml:"target_matchers"`
+	Equal          []string `yaml:"equal"` // labels whose values must match, e.g. node
 }
 
 type AlertRulesConfig struct {
@@ -69,6 +215,43 @@ type AlertRulesConfig struct {
 	PodMemoryPercent      float64 `yaml:"-"` // Computed from PodMemoryThreshold
 	NodeCPUPercent        float64 `yaml:"-"` // Computed from NodeCPUThreshold
 	NodeMemoryPercent     float64 `yaml:"-"` // Computed from NodeMemoryThreshold
+	RulesFile             string  `yaml:"rules_file"` // Optional YAML file overriding or extending the built-in rules
+
+	// WorkloadAvailableRatio is the fraction of a deployment's desired replicas that must be available
+	WorkloadAvailableRatio float64 `yaml:"workload_available_ratio"`
+	// WorkloadGracePeriod is how long a workload may stay degraded, e.g. during a rollout, before it alerts
+	WorkloadGracePeriod time.Duration `yaml:"workload_grace_period"`
+	// JobMaxDuration is how long a Job may run before it alerts
+	JobMaxDuration time.Duration `yaml:"job_max_duration"`
+	// CronJobMissedSchedules is how many scheduled runs of a CronJob may pass without a success before it alerts
+	CronJobMissedSchedules int `yaml:"cronjob_missed_schedules"`
+	// PVCPendingGracePeriod is how long a PersistentVolumeClaim may stay unbound before it alerts
+	PVCPendingGracePeriod time.Duration `yaml:"pvc_pending_grace_period"`
+	// VolumeUsageThreshold is the percentage of a claim's capacity in use at which it alerts
+	VolumeUsageThreshold float64 `yaml:"volume_usage_threshold"`
+	// VolumeInodesThreshold is the percentage of a claim's inodes in use at which it alerts
+	VolumeInodesThreshold float64 `yaml:"volume_inodes_threshold"`
+	// VolumeCheckInterval is how often volume usage is read from the kubelets
+	VolumeCheckInterval time.Duration `yaml:"volume_check_interval"`
+	// ContainerMemoryLimitThreshold is the percentage of a container's memory limit in use at which it alerts
+	ContainerMemoryLimitThreshold int `yaml:"container_memory_limit_threshold"`
+	// EphemeralStorageThreshold is the percentage of a container's ephemeral-storage limit in use at which it alerts
+	EphemeralStorageThreshold int `yaml:"ephemeral_storage_threshold"`
+	// ContainerCheckInterval is how often container usage is read from the kubelets
+	ContainerCheckInterval time.Duration `yaml:"container_check_interval"`
+
+	// For holds, per alert type, how long a condition must hold before the alert fires
+	For map[string]time.Duration `yaml:"for"`
+
+	// Namespaces overrides thresholds and disables alert types for the pods of a namespace.
+	// Pod and namespace annotations take precedence over these.
+	Namespaces map[string]NamespaceAlertRules `yaml:"namespaces"`
+}
+
+// NamespaceAlertRules holds alert rule overrides for one namespace
+type NamespaceAlertRules struct {
+	Thresholds map[string]float64 `yaml:"thresholds"` // alert type -> threshold
+	Disabled   []string           `yaml:"disabled"`   // alert types not raised in the namespace
 }
 
 // overrideFromEnv overrides config values with environment variables
@@ -117,6 +300,9 @@ func overrideFromEnv(cfg *Config) {
 	if metricsInterval := os.Getenv("K8S_METRICS_INTERVAL"); metricsInterval != "" {
 		fmt.Sscanf(metricsInterval, "%d", &cfg.Kubernetes.MetricsInterval)
 	}
+	if resyncPeriod := os.Getenv("K8S_RESYNC_PERIOD"); resyncPeriod != "" {
+		fmt.Sscanf(resyncPeriod, "%d", &cfg.Kubernetes.ResyncPeriod)
+	}
 
 	// Logging configuration
 	if level := os.Getenv("LOG_LEVEL"); level != "" {
@@ -151,6 +337,47 @@ func overrideFromEnv(cfg *Config) {
 	if to := os.Getenv("SMTP_TO"); to != "" {
 		cfg.Email.To = strings.Split(to, ",")
 	}
+	if tlsMode := os.Getenv("SMTP_TLS"); tlsMode != "" {
+		cfg.Email.TLS = tlsMode
+	}
+
+	// Slack configuration
+	if enabled := os.Getenv("SLACK_ENABLED"); enabled != "" {
+		cfg.Slack.Enabled = strings.ToLower(enabled) == "true"
+	}
+	if webhookURL := os.Getenv("SLACK_WEBHOOK_URL"); webhookURL != "" {
+		cfg.Slack.WebhookURL = webhookURL
+	}
+	if botToken := os.Getenv("SLACK_BOT_TOKEN"); botToken != "" {
+		cfg.Slack.BotToken = botToken
+	}
+	if channel := os.Getenv("SLACK_CHANNEL"); channel != "" {
+		cfg.Slack.Channel = channel
+	}
+	if dashboardURL := os.Getenv("SLACK_DASHBOARD_URL"); dashboardURL != "" {
+		cfg.Slack.DashboardURL = dashboardURL
+	}
+
+	// Event watcher configuration
+	if enabled := os.Getenv("EVENTS_ENABLED"); enabled != "" {
+		cfg.Events.Enabled = strings.ToLower(enabled) == "true"
+	}
+
+	// Webhook configuration
+	if enabled := os.Getenv("WEBHOOKS_ENABLED"); enabled != "" {
+		cfg.Webhooks.Enabled = strings.ToLower(enabled) == "true"
+	}
+
+	// PagerDuty configuration
+	if enabled := os.Getenv("PAGERDUTY_ENABLED"); enabled != "" {
+		cfg.PagerDuty.Enabled = strings.ToLower(enabled) == "true"
+	}
+	if routingKey := os.Getenv("PAGERDUTY_ROUTING_KEY"); routingKey != "" {
+		cfg.PagerDuty.RoutingKey = routingKey
+	}
+	if eventsURL := os.Getenv("PAGERDUTY_EVENTS_URL"); eventsURL != "" {
+		cfg.PagerDuty.EventsURL = eventsURL
+	}
 
 	// Alert rules configuration
 	if podRestartThreshold := os.Getenv("ALERT_POD_RESTART_THRESHOLD"); podRestartThreshold != "" {
@@ -171,6 +398,27 @@ func overrideFromEnv(cfg *Config) {
 	if metricsCheckInterval := os.Getenv("ALERT_METRICS_CHECK_INTERVAL"); metricsCheckInterval != "" {
 		fmt.Sscanf(metricsCheckInterval, "%d", &cfg.AlertRules.MetricsCheckInterval)
 	}
+	if rulesFile := os.Getenv("ALERT_RULES_FILE"); rulesFile != "" {
+		cfg.AlertRules.RulesFile = rulesFile
+	}
+	if availableRatio := os.Getenv("ALERT_WORKLOAD_AVAILABLE_RATIO"); availableRatio != "" {
+		fmt.Sscanf(availableRatio, "%g", &cfg.AlertRules.WorkloadAvailableRatio)
+	}
+	if missedSchedules := os.Getenv("ALERT_CRONJOB_MISSED_SCHEDULES"); missedSchedules != "" {
+		fmt.Sscanf(missedSchedules, "%d", &cfg.AlertRules.CronJobMissedSchedules)
+	}
+	if volumeUsage := os.Getenv("ALERT_VOLUME_USAGE_THRESHOLD"); volumeUsage != "" {
+		fmt.Sscanf(volumeUsage, "%g", &cfg.AlertRules.VolumeUsageThreshold)
+	}
+	if volumeInodes := os.Getenv("ALERT_VOLUME_INODES_THRESHOLD"); volumeInodes != "" {
+		fmt.Sscanf(volumeInodes, "%g", &cfg.AlertRules.VolumeInodesThreshold)
+	}
+	if memoryLimit := os.Getenv("ALERT_CONTAINER_MEMORY_LIMIT_THRESHOLD"); memoryLimit != "" {
+		fmt.Sscanf(memoryLimit, "%d", &cfg.AlertRules.ContainerMemoryLimitThreshold)
+	}
+	if ephemeralStorage := os.Getenv("ALERT_EPHEMERAL_STORAGE_THRESHOLD"); ephemeralStorage != "" {
+		fmt.Sscanf(ephemeralStorage, "%d", &cfg.AlertRules.EphemeralStorageThreshold)
+	}
 }
 
 // Load reads and parses the config file
@@ -56,6 +56,9 @@ alert_rules:
   node_cpu_threshold: 70
   node_memory_threshold: 75
   metrics_check_interval: 60
+  for:
+    pod_cpu_high: 3m
+    node_memory_high: 90s
 `
 		tmpFile := filepath.Join(t.TempDir(), "config.yaml")
 		err := os.WriteFile(tmpFile, []byte(configContent), 0644)
@@ -90,6 +93,10 @@ alert_rules:
 		assert.Equal(t, 85.0, cfg.AlertRules.PodMemoryPercent)
 		assert.Equal(t, 70.0, cfg.AlertRules.NodeCPUPercent)
 		assert.Equal(t, 75.0, cfg.AlertRules.NodeMemoryPercent)
+
+		// Verify pending durations
+		assert.Equal(t, 3*time.Minute, cfg.AlertRules.For["pod_cpu_high"])
+		assert.Equal(t, 90*time.Second, cfg.AlertRules.For["node_memory_high"])
 	})
 
 	t.Run("should apply default timeouts", func(t *testing.T) {
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,23 +16,94 @@ import (
 type AlertStatus string
 
 const (
-	AlertStatusFiring   AlertStatus = "firing"
-	AlertStatusResolved AlertStatus = "resolved"
+	AlertStatusFiring       AlertStatus = "firing"
+	AlertStatusAcknowledged AlertStatus = "acknowledged"
+	AlertStatusResolved     AlertStatus = "resolved"
 )
 
+// ActiveAlertStatuses are the statuses of an alert whose condition is still present
+var ActiveAlertStatuses = []AlertStatus{AlertStatusFiring, AlertStatusAcknowledged}
+
+// ErrInvalidTransition is returned when an alert cannot move to the requested status
+var ErrInvalidTransition = errors.New("invalid alert status transition")
+
+// fingerprintLabels are hashed into every fingerprint, in this order, with an empty value
+// unless they identify the alert's subject. Together with optionalFingerprintLabels they give
+// every alert the canonical form of the first fingerprints, so stored fingerprints stay valid.
+var fingerprintLabels = []string{"alert_type", "namespace", "pod", "container", "node"}
+
+// optionalFingerprintLabels identify subjects added later. They are only hashed when they
+// identify the alert's subject and are set.
+var optionalFingerprintLabels = []string{"workload_kind", "workload", "job", "cronjob", "object_kind", "object", "pvc"}
+
+// fingerprintSubject is a kind of alert subject and the labels that identify it
+type fingerprintSubject struct {
+	key    string // label set on alerts about this kind of subject
+	labels []string
+}
+
+// fingerprintSubjects are the kinds of subject alerts are about, most specific first: an alert
+// is about the first kind whose key label is set. Volatile labels (reason, metric values,
+// messages) and labels that only relate the subject to others never identify it:
+//   - a claim outlives the pods mounting it, so the pod does not identify volume alerts
+//   - a pod is bound to a node after it may already be alerting, so the node does not identify
+//     pod alerts, nor does the Job the pod ran for
+//   - pod and node events are about the pod or node, other events about the involved object
+var fingerprintSubjects = []fingerprintSubject{
+	{key: "pvc", labels: []string{"alert_type", "namespace", "pvc"}},
+	{key: "pod", labels: []string{"alert_type", "namespace", "pod", "container"}},
+	{key: "workload", labels: []string{"alert_type", "namespace", "workload_kind", "workload"}},
+	{key: "job", labels: []string{"alert_type", "namespace", "job", "cronjob"}},
+	{key: "cronjob", labels: []string{"alert_type", "namespace", "cronjob"}},
+	{key: "object", labels: []string{"alert_type", "namespace", "object_kind", "object", "node"}},
+	{key: "node", labels: []string{"alert_type", "node"}},
+}
+
+// clusterFingerprintLabels identify alerts about none of fingerprintSubjects
+var clusterFingerprintLabels = []string{"alert_type", "namespace"}
+
+// identifyingLabels returns the labels that identify the subject of an alert with the given labels
+func identifyingLabels(labels map[string]string) map[string]bool {
+	keys := clusterFingerprintLabels
+	for _, subject := range fingerprintSubjects {
+		if labels[subject.key] != "" {
+			keys = subject.labels
+			break
+		}
+	}
+	identifying := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		identifying[key] = true
+	}
+	return identifying
+}
+
 // Alert represents a triggered alert
 type Alert struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	Status      AlertStatus    `gorm:"type:varchar(20);not null;default:'firing';index" json:"status"`
-	Severity    string         `gorm:"type:varchar(50);not null;index" json:"severity"`    // critical, high, medium, low
-	Message     string         `gorm:"type:text;not null" json:"message"`
-	Source      string         `gorm:"type:varchar(100);not null;index" json:"source"`     // k8s_pod, k8s_node, k8s_metrics
-	Labels      datatypes.JSON `gorm:"type:jsonb;default:'{}'" json:"labels"`
-	Value       float64        `gorm:"type:double precision" json:"value"`
-	TriggeredAt time.Time      `gorm:"not null;index:,sort:desc" json:"triggered_at"`
-	ResolvedAt  *time.Time     `gorm:"type:timestamp with time zone" json:"resolved_at,omitempty"`
-	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Status          AlertStatus    `gorm:"type:varchar(20);not null;default:'firing';index" json:"status"`
+	Severity        string         `gorm:"type:varchar(50);not null;index" json:"severity"` // critical, high, medium, low
+	Message         string         `gorm:"type:text;not null" json:"message"`
+	Source          string         `gorm:"type:varchar(100);not null;index" json:"source"` // k8s_pod, k8s_node, k8s_metrics
+	Labels          datatypes.JSON `gorm:"type:jsonb;default:'{}'" json:"labels"`
+	Value           float64        `gorm:"type:double precision" json:"value"`
+	Fingerprint     string         `gorm:"type:varchar(64);index" json:"fingerprint"`
+	OccurrenceCount int            `gorm:"not null;default:1" json:"occurrence_count"` // observations while firing
+	TriggeredAt     time.Time      `gorm:"not null;index:,sort:desc" json:"triggered_at"`
+	LastSeenAt      time.Time      `gorm:"type:timestamp with time zone" json:"last_seen_at"`
+	ResolvedAt      *time.Time     `gorm:"type:timestamp with time zone" json:"resolved_at,omitempty"`
+	ResolvedBy      string         `gorm:"type:varchar(255)" json:"resolved_by,omitempty"`
+	AcknowledgedAt  *time.Time     `gorm:"type:timestamp with time zone" json:"acknowledged_at,omitempty"`
+	AcknowledgedBy  string         `gorm:"type:varchar(255)" json:"acknowledged_by,omitempty"`
+	Comment         string         `gorm:"type:text" json:"comment,omitempty"`             // latest operator comment
+	Suppressed      bool           `gorm:"not null;default:false;index" json:"suppressed"` // stored but not notified
+	SilenceID       *uuid.UUID     `gorm:"type:uuid" json:"silence_id,omitempty"`
+	Maintenance     bool           `gorm:"not null;default:false" json:"maintenance"` // raised during a maintenance window or on a cordoned node
+	MaintenanceID   *uuid.UUID     `gorm:"type:uuid" json:"maintenance_id,omitempty"`
+	Inhibited       bool           `gorm:"not null;default:false" json:"inhibited"` // a firing source alert covers this one
+	InhibitedBy     *uuid.UUID     `gorm:"type:uuid" json:"inhibited_by,omitempty"`
+	CreatedAt       time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 // TableName specifies the table name for GORM
@@ -46,20 +121,72 @@ func NewAlert(severity, message, source string, value float64, labels map[string
 	}
 
 	return &Alert{
-		ID:          uuid.New(),
-		Status:      AlertStatusFiring,
-		Severity:    severity,
-		Message:     message,
-		Source:      source,
-		Labels:      labelsJSON,
-		Value:       value,
-		TriggeredAt: now,
-		ResolvedAt:  nil,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:              uuid.New(),
+		Status:          AlertStatusFiring,
+		Severity:        severity,
+		Message:         message,
+		Source:          source,
+		Labels:          labelsJSON,
+		Value:           value,
+		Fingerprint:     ComputeFingerprint(source, labels),
+		OccurrenceCount: 1,
+		TriggeredAt:     now,
+		LastSeenAt:      now,
+		ResolvedAt:      nil,
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
 }
 
+// ComputeFingerprint returns a stable identity for an alert built from its source and the
+// labels identifying its subject, see fingerprintSubjects
+func ComputeFingerprint(source string, labels map[string]string) string {
+	identifying := identifyingLabels(labels)
+
+	var b strings.Builder
+	b.WriteString(source)
+	for _, key := range fingerprintLabels {
+		value := ""
+		if identifying[key] {
+			value = labels[key]
+		}
+		b.WriteString("|")
+		b.WriteString(key)
+		b.WriteString("=")
+		b.WriteString(value)
+	}
+	for _, key := range optionalFingerprintLabels {
+		if value := labels[key]; value != "" && identifying[key] {
+			b.WriteString("|")
+			b.WriteString(key)
+			b.WriteString("=")
+			b.WriteString(value)
+		}
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// EnsureFingerprint computes the fingerprint from source and labels if it is not set
+func (a *Alert) EnsureFingerprint() string {
+	if a.Fingerprint == "" {
+		a.Fingerprint = ComputeFingerprint(a.Source, a.GetLabelsMap())
+	}
+	return a.Fingerprint
+}
+
+// Touch records another observation of a firing alert
+func (a *Alert) Touch(value float64, message string) {
+	now := time.Now()
+	a.Value = value
+	if message != "" {
+		a.Message = message
+	}
+	a.OccurrenceCount++
+	a.LastSeenAt = now
+	a.UpdatedAt = now
+}
+
 // Resolve marks an alert as resolved
 func (a *Alert) Resolve() {
 	now := time.Now()
@@ -68,11 +195,85 @@ func (a *Alert) Resolve() {
 	a.UpdatedAt = now
 }
 
+// Acknowledge marks a firing alert as acknowledged by an operator
+func (a *Alert) Acknowledge(by, comment string) error {
+	if a.Status != AlertStatusFiring {
+		return ErrInvalidTransition
+	}
+	now := time.Now()
+	a.Status = AlertStatusAcknowledged
+	a.AcknowledgedAt = &now
+	a.AcknowledgedBy = by
+	a.Comment = comment
+	a.UpdatedAt = now
+	return nil
+}
+
+// Unacknowledge returns an acknowledged alert to firing
+func (a *Alert) Unacknowledge(comment string) error {
+	if a.Status != AlertStatusAcknowledged {
+		return ErrInvalidTransition
+	}
+	a.Status = AlertStatusFiring
+	a.AcknowledgedAt = nil
+	a.AcknowledgedBy = ""
+	a.Comment = comment
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+// ResolveBy manually resolves an active alert on behalf of an operator
+func (a *Alert) ResolveBy(by, comment string) error {
+	if !a.IsActive() {
+		return ErrInvalidTransition
+	}
+	a.Resolve()
+	a.ResolvedBy = by
+	a.Comment = comment
+	return nil
+}
+
+// Silence flags the alert as suppressed by the given silence
+func (a *Alert) Silence(silenceID uuid.UUID) {
+	a.Suppressed = true
+	a.SilenceID = &silenceID
+}
+
+// EnterMaintenance flags the alert as suppressed by maintenance.
+// windowID is nil for implicit maintenance such as a cordoned node.
+func (a *Alert) EnterMaintenance(windowID *uuid.UUID) {
+	a.Suppressed = true
+	a.Maintenance = true
+	a.MaintenanceID = windowID
+}
+
+// Inhibit flags the alert as suppressed while the given source alert is active
+func (a *Alert) Inhibit(sourceID uuid.UUID) {
+	a.Suppressed = true
+	a.Inhibited = true
+	a.InhibitedBy = &sourceID
+}
+
+// ClearSuppression removes any suppression so the alert is notified again
+func (a *Alert) ClearSuppression() {
+	a.Suppressed = false
+	a.SilenceID = nil
+	a.Maintenance = false
+	a.MaintenanceID = nil
+	a.Inhibited = false
+	a.InhibitedBy = nil
+}
+
 // IsFiring returns true if the alert is currently firing
 func (a *Alert) IsFiring() bool {
 	return a.Status == AlertStatusFiring
 }
 
+// IsActive returns true if the alert is firing or acknowledged
+func (a *Alert) IsActive() bool {
+	return a.Status == AlertStatusFiring || a.Status == AlertStatusAcknowledged
+}
+
 // GetLabelsMap returns labels as a map
 func (a *Alert) GetLabelsMap() map[string]string {
 	var labels map[string]string
@@ -81,3 +282,19 @@ func (a *Alert) GetLabelsMap() map[string]string {
 	}
 	return labels
 }
+
+// MatchLabels returns the alert labels plus its severity and source, the label set that
+// routes, inhibition rules and silences match against
+func (a *Alert) MatchLabels() map[string]string {
+	labels := a.GetLabelsMap()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if _, ok := labels["severity"]; !ok {
+		labels["severity"] = a.Severity
+	}
+	if _, ok := labels["source"]; !ok {
+		labels["source"] = a.Source
+	}
+	return labels
+}
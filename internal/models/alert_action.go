@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertActionType is an operator action taken on an alert
+type AlertActionType string
+
+const (
+	AlertActionAcknowledge   AlertActionType = "acknowledge"
+	AlertActionUnacknowledge AlertActionType = "unacknowledge"
+	AlertActionResolve       AlertActionType = "resolve"
+)
+
+// AlertAction records who acted on an alert, when, and why
+type AlertAction struct {
+	ID        uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	AlertID   uuid.UUID       `gorm:"type:uuid;not null;index" json:"alert_id"`
+	Action    AlertActionType `gorm:"type:varchar(20);not null" json:"action"`
+	Actor     string          `gorm:"type:varchar(255);not null" json:"actor"`
+	Comment   string          `gorm:"type:text" json:"comment,omitempty"`
+	CreatedAt time.Time       `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (AlertAction) TableName() string {
+	return "alert_actions"
+}
+
+// NewAlertAction creates a new alert action record
+func NewAlertAction(alertID uuid.UUID, action AlertActionType, actor, comment string) *AlertAction {
+	return &AlertAction{
+		ID:        uuid.New(),
+		AlertID:   alertID,
+		Action:    action,
+		Actor:     actor,
+		Comment:   comment,
+		CreatedAt: time.Now(),
+	}
+}
@@ -109,6 +109,64 @@ var _ = Describe("Alert", func() {
 		})
 	})
 
+	Describe("Fingerprint", func() {
+		It("should be stable for the same source and identifying labels", func() {
+			labels := map[string]string{"alert_type": "pod_crash_loop", "namespace": "default", "pod": "web", "reason": "a"}
+			other := map[string]string{"alert_type": "pod_crash_loop", "namespace": "default", "pod": "web", "reason": "b"}
+
+			first := models.NewAlert("high", "Test", "k8s_pod", 0, labels)
+			second := models.NewAlert("high", "Other message", "k8s_pod", 1, other)
+
+			Expect(first.Fingerprint).NotTo(BeEmpty())
+			Expect(first.Fingerprint).To(Equal(second.Fingerprint))
+		})
+
+		It("should differ for different pods", func() {
+			first := models.NewAlert("high", "Test", "k8s_pod", 0, map[string]string{"pod": "a"})
+			second := models.NewAlert("high", "Test", "k8s_pod", 0, map[string]string{"pod": "b"})
+
+			Expect(first.Fingerprint).NotTo(Equal(second.Fingerprint))
+		})
+
+		It("should identify volume alerts by claim rather than by pod", func() {
+			labels := map[string]string{"alert_type": "volume_usage_high", "namespace": "db", "pvc": "data-0", "pod": "db-0"}
+			moved := map[string]string{"alert_type": "volume_usage_high", "namespace": "db", "pvc": "data-0", "pod": "db-1"}
+			other := map[string]string{"alert_type": "volume_usage_high", "namespace": "db", "pvc": "logs-0", "pod": "db-0"}
+
+			first := models.NewAlert("high", "Test", "k8s_volume", 90, labels)
+			Expect(first.Fingerprint).To(Equal(models.NewAlert("high", "Test", "k8s_volume", 90, moved).Fingerprint))
+			Expect(first.Fingerprint).NotTo(Equal(models.NewAlert("high", "Test", "k8s_volume", 90, other).Fingerprint))
+		})
+
+		It("should not identify pod alerts by node or job", func() {
+			labels := map[string]string{"alert_type": "pod_failed", "namespace": "batch", "pod": "report-x1"}
+			scheduled := map[string]string{"alert_type": "pod_failed", "namespace": "batch", "pod": "report-x1", "node": "node-1", "job": "report"}
+
+			Expect(models.ComputeFingerprint("k8s_pod", labels)).To(Equal(models.ComputeFingerprint("k8s_pod", scheduled)))
+		})
+
+		It("should keep the fingerprints stored by earlier versions", func() {
+			pod := map[string]string{"alert_type": "pod_crash_loop", "namespace": "a", "pod": "p", "container": "c", "reason": "x"}
+			node := map[string]string{"alert_type": "node_not_ready", "node": "n1"}
+
+			Expect(models.ComputeFingerprint("k8s_pod", pod)).To(Equal("e0025acd6e2bc7cb9b4f83dfb526bdd9fcd9c4ad1d8772aed0a57ead718b9eb5"))
+			Expect(models.ComputeFingerprint("k8s_node", node)).To(Equal("72588d2b81db0d5f8af1e8ce1c18782aac45c2423f4130f041f491b59980c9fc"))
+		})
+	})
+
+	Describe("Touch", func() {
+		It("should record another observation", func() {
+			alert := models.NewAlert("high", "Test", "k8s_pod", 0, nil)
+
+			alert.Touch(12.5, "Updated")
+
+			Expect(alert.OccurrenceCount).To(Equal(2))
+			Expect(alert.Value).To(Equal(12.5))
+			Expect(alert.Message).To(Equal("Updated"))
+			Expect(alert.LastSeenAt).To(BeTemporally("~", time.Now(), time.Second))
+		})
+	})
+
 	Describe("GetLabelsMap", func() {
 		It("should return labels as a map", func() {
 			labels := map[string]string{
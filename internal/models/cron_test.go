@@ -0,0 +1,77 @@
+package models_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+)
+
+var _ = Describe("CronSchedule", func() {
+	from := time.Date(2024, time.January, 31, 10, 7, 30, 0, time.UTC) // a Wednesday
+
+	Describe("Next", func() {
+		DescribeTable("should return the next scheduled run",
+			func(expr string, want time.Time) {
+				schedule, err := models.ParseCron(expr)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(schedule.Next(from)).To(Equal(want))
+			},
+			Entry("steps", "*/15 * * * *", time.Date(2024, time.January, 31, 10, 15, 0, 0, time.UTC)),
+			Entry("daily", "0 2 * * *", time.Date(2024, time.February, 1, 2, 0, 0, 0, time.UTC)),
+			Entry("macros", "@hourly", time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)),
+			Entry("day names", "30 9 * * mon-fri", time.Date(2024, time.February, 1, 9, 30, 0, 0, time.UTC)),
+			Entry("Sunday as 7", "0 0 * * 7", time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)),
+			Entry("month names", "0 0 29 feb *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)),
+			Entry("lists", "0 6 1,15 * *", time.Date(2024, time.February, 1, 6, 0, 0, 0, time.UTC)),
+			// Restricting both day fields runs on days matching either
+			Entry("both day fields", "0 0 13 * fri", time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC)),
+		)
+
+		It("should match times in the location of the given time", func() {
+			berlin, err := time.LoadLocation("Europe/Berlin")
+			Expect(err).NotTo(HaveOccurred())
+			schedule, err := models.ParseCron("0 2 * * *")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(schedule.Next(from.In(berlin)).Equal(time.Date(2024, time.February, 1, 1, 0, 0, 0, time.UTC))).To(BeTrue())
+		})
+
+		It("should return zero for schedules that never run", func() {
+			schedule, err := models.ParseCron("0 0 30 2 *")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule.Next(from).IsZero()).To(BeTrue())
+		})
+	})
+
+	Describe("Prev", func() {
+		DescribeTable("should return the last scheduled run",
+			func(expr string, want time.Time) {
+				schedule, err := models.ParseCron(expr)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(schedule.Prev(from)).To(Equal(want))
+			},
+			Entry("steps", "*/15 * * * *", time.Date(2024, time.January, 31, 10, 0, 0, 0, time.UTC)),
+			Entry("the current minute", "7 10 * * *", time.Date(2024, time.January, 31, 10, 7, 0, 0, time.UTC)),
+			Entry("daily", "0 12 * * *", time.Date(2024, time.January, 30, 12, 0, 0, 0, time.UTC)),
+			Entry("day names", "30 9 * * sat,sun", time.Date(2024, time.January, 28, 9, 30, 0, 0, time.UTC)),
+			Entry("month names", "0 0 29 feb *", time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)),
+			Entry("both day fields", "0 0 13 * fri", time.Date(2024, time.January, 26, 0, 0, 0, 0, time.UTC)),
+		)
+
+		It("should return zero for schedules that never run", func() {
+			schedule, err := models.ParseCron("0 0 30 2 *")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule.Prev(from).IsZero()).To(BeTrue())
+		})
+	})
+
+	It("should reject invalid expressions", func() {
+		for _, expr := range []string{"* * * *", "60 * * * *", "0 0 * * funday", "*/0 * * * *", "5-1 * * * *"} {
+			_, err := models.ParseCron(expr)
+			Expect(err).To(HaveOccurred(), expr)
+		}
+	})
+})
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// ErrInvalidMaintenanceWindow is returned when a maintenance window fails validation
+var ErrInvalidMaintenanceWindow = errors.New("invalid maintenance window")
+
+// maxMaintenanceDuration bounds how long a recurring window stays open after each scheduled start
+const maxMaintenanceDuration = 7 * 24 * time.Hour
+
+// MaintenanceWindow is a planned period during which alerts for its scope are recorded but not notified.
+// A one-off window runs from StartsAt to EndsAt. A recurring window opens whenever Schedule (a five-field
+// cron expression evaluated in Timezone) fires and stays open for DurationMinutes; StartsAt and EndsAt,
+// when set, bound the period in which it recurs.
+// The scope is the intersection of the non-empty Nodes, Namespaces and Matchers.
+type MaintenanceWindow struct {
+	ID              uuid.UUID                    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name            string                       `gorm:"type:varchar(255);not null" json:"name"`
+	Nodes           datatypes.JSONSlice[string]  `gorm:"type:jsonb" json:"nodes,omitempty"`
+	Namespaces      datatypes.JSONSlice[string]  `gorm:"type:jsonb" json:"namespaces,omitempty"`
+	Matchers        datatypes.JSONSlice[Matcher] `gorm:"type:jsonb" json:"matchers,omitempty"`
+	StartsAt        time.Time                    `gorm:"not null" json:"starts_at"`
+	EndsAt          *time.Time                   `json:"ends_at,omitempty"`
+	Schedule        string                       `gorm:"type:varchar(100)" json:"schedule,omitempty"` // cron expression for recurring windows
+	DurationMinutes int                          `json:"duration_minutes,omitempty"`
+	Timezone        string                       `gorm:"type:varchar(64)" json:"timezone,omitempty"` // IANA name, defaults to UTC
+	CreatedBy       string                       `gorm:"type:varchar(255);not null" json:"created_by"`
+	Comment         string                       `gorm:"type:text" json:"comment"`
+	CreatedAt       time.Time                    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time                    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (MaintenanceWindow) TableName() string {
+	return "maintenance_windows"
+}
+
+// IsRecurring returns true if the window repeats on a cron schedule
+func (w *MaintenanceWindow) IsRecurring() bool {
+	return w.Schedule != ""
+}
+
+// Validate checks the window has a name, creator, scope and a consistent one-off or recurring schedule
+func (w *MaintenanceWindow) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidMaintenanceWindow)
+	}
+	if w.CreatedBy == "" {
+		return fmt.Errorf("%w: created_by is required", ErrInvalidMaintenanceWindow)
+	}
+	if len(w.Nodes) == 0 && len(w.Namespaces) == 0 && len(w.Matchers) == 0 {
+		return fmt.Errorf("%w: at least one of nodes, namespaces or matchers is required", ErrInvalidMaintenanceWindow)
+	}
+	for _, matcher := range w.Matchers {
+		if err := matcher.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidMaintenanceWindow, err)
+		}
+	}
+	if w.EndsAt != nil && !w.EndsAt.After(w.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidMaintenanceWindow)
+	}
+	if _, err := w.location(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMaintenanceWindow, err)
+	}
+
+	if !w.IsRecurring() {
+		if w.EndsAt == nil {
+			return fmt.Errorf("%w: ends_at is required for a one-off window", ErrInvalidMaintenanceWindow)
+		}
+		return nil
+	}
+
+	if _, err := ParseCron(w.Schedule); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMaintenanceWindow, err)
+	}
+	duration := time.Duration(w.DurationMinutes) * time.Minute
+	if duration <= 0 || duration > maxMaintenanceDuration {
+		return fmt.Errorf("%w: duration_minutes must be between 1 and %d", ErrInvalidMaintenanceWindow, int(maxMaintenanceDuration.Minutes()))
+	}
+	return nil
+}
+
+// IsActive returns true if the window is open at the given time
+func (w *MaintenanceWindow) IsActive(now time.Time) bool {
+	if now.Before(w.StartsAt) || (w.EndsAt != nil && !now.Before(*w.EndsAt)) {
+		return false
+	}
+	if !w.IsRecurring() {
+		return true
+	}
+
+	schedule, err := ParseCron(w.Schedule)
+	if err != nil {
+		return false
+	}
+	loc, err := w.location()
+	if err != nil {
+		return false
+	}
+
+	// Open if the last scheduled start was less than the window duration ago
+	start := schedule.Prev(now.In(loc))
+	return !start.IsZero() && now.Sub(start) < time.Duration(w.DurationMinutes)*time.Minute
+}
+
+// Matches returns true if the alert labels fall within the window's scope
+func (w *MaintenanceWindow) Matches(labels map[string]string) bool {
+	if len(w.Nodes) > 0 && !contains(w.Nodes, labels["node"]) {
+		return false
+	}
+	if len(w.Namespaces) > 0 && !contains(w.Namespaces, labels["namespace"]) {
+		return false
+	}
+	for _, matcher := range w.Matchers {
+		if !matcher.Matches(labels) {
+			return false
+		}
+	}
+	return len(w.Nodes) > 0 || len(w.Namespaces) > 0 || len(w.Matchers) > 0
+}
+
+func (w *MaintenanceWindow) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(w.Timezone)
+}
+
+func contains(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
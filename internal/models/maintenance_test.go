@@ -0,0 +1,130 @@
+package models_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"gorm.io/datatypes"
+)
+
+var _ = Describe("MaintenanceWindow", func() {
+	at := func(value string) time.Time {
+		t, err := time.Parse(time.RFC3339, value)
+		Expect(err).NotTo(HaveOccurred())
+		return t
+	}
+
+	Describe("IsActive", func() {
+		It("should be active between starts_at and ends_at for a one-off window", func() {
+			endsAt := at("2025-01-01T04:00:00Z")
+			window := &models.MaintenanceWindow{StartsAt: at("2025-01-01T02:00:00Z"), EndsAt: &endsAt}
+
+			Expect(window.IsActive(at("2025-01-01T01:59:00Z"))).To(BeFalse())
+			Expect(window.IsActive(at("2025-01-01T03:00:00Z"))).To(BeTrue())
+			Expect(window.IsActive(at("2025-01-01T04:00:00Z"))).To(BeFalse())
+		})
+
+		It("should open for the duration after each scheduled start of a recurring window", func() {
+			// Sundays at 02:00 for two hours; 2025-01-05 is a Sunday
+			window := &models.MaintenanceWindow{
+				StartsAt:        at("2025-01-01T00:00:00Z"),
+				Schedule:        "0 2 * * 0",
+				DurationMinutes: 120,
+			}
+
+			Expect(window.IsActive(at("2025-01-05T01:59:00Z"))).To(BeFalse())
+			Expect(window.IsActive(at("2025-01-05T02:00:00Z"))).To(BeTrue())
+			Expect(window.IsActive(at("2025-01-05T03:59:30Z"))).To(BeTrue())
+			Expect(window.IsActive(at("2025-01-05T04:00:00Z"))).To(BeFalse())
+			Expect(window.IsActive(at("2025-01-06T02:30:00Z"))).To(BeFalse())
+		})
+
+		It("should stay open across days after the last scheduled start", func() {
+			// Saturdays at 22:00 for three days; 2025-01-04 is a Saturday
+			window := &models.MaintenanceWindow{
+				StartsAt:        at("2025-01-01T00:00:00Z"),
+				Schedule:        "0 22 * * sat",
+				DurationMinutes: 3 * 24 * 60,
+			}
+
+			Expect(window.IsActive(at("2025-01-06T12:00:00Z"))).To(BeTrue())
+			Expect(window.IsActive(at("2025-01-07T22:00:00Z"))).To(BeFalse())
+		})
+
+		It("should evaluate the schedule in the window timezone", func() {
+			window := &models.MaintenanceWindow{
+				StartsAt:        at("2025-01-01T00:00:00Z"),
+				Schedule:        "30 1 * * *",
+				DurationMinutes: 30,
+				Timezone:        "Asia/Kolkata", // UTC+05:30
+			}
+
+			Expect(window.IsActive(at("2025-01-02T20:10:00Z"))).To(BeTrue())
+			Expect(window.IsActive(at("2025-01-02T01:40:00Z"))).To(BeFalse())
+		})
+	})
+
+	Describe("Matches", func() {
+		It("should match the intersection of nodes, namespaces and matchers", func() {
+			window := &models.MaintenanceWindow{
+				Nodes:      datatypes.NewJSONSlice([]string{"node-1"}),
+				Namespaces: datatypes.NewJSONSlice([]string{"payments"}),
+			}
+
+			Expect(window.Matches(map[string]string{"node": "node-1", "namespace": "payments"})).To(BeTrue())
+			Expect(window.Matches(map[string]string{"node": "node-1", "namespace": "default"})).To(BeFalse())
+			Expect(window.Matches(map[string]string{"node": "node-2", "namespace": "payments"})).To(BeFalse())
+		})
+
+		It("should match label selectors", func() {
+			window := &models.MaintenanceWindow{
+				Matchers: datatypes.NewJSONSlice([]models.Matcher{{Name: "pod", Operator: models.MatchRegexp, Value: "db-.*"}}),
+			}
+
+			Expect(window.Matches(map[string]string{"pod": "db-0"})).To(BeTrue())
+			Expect(window.Matches(map[string]string{"pod": "api-0"})).To(BeFalse())
+		})
+	})
+
+	Describe("Validate", func() {
+		valid := func() *models.MaintenanceWindow {
+			return &models.MaintenanceWindow{
+				Name:            "weekly reboot",
+				Nodes:           datatypes.NewJSONSlice([]string{"node-1"}),
+				StartsAt:        time.Now(),
+				Schedule:        "0 2 * * 0",
+				DurationMinutes: 60,
+				CreatedBy:       "alice",
+			}
+		}
+
+		It("should accept a recurring window", func() {
+			Expect(valid().Validate()).To(Succeed())
+		})
+
+		It("should reject invalid windows", func() {
+			noScope := valid()
+			noScope.Nodes = nil
+			Expect(noScope.Validate()).To(MatchError(models.ErrInvalidMaintenanceWindow))
+
+			badCron := valid()
+			badCron.Schedule = "0 25 * * *"
+			Expect(badCron.Validate()).To(MatchError(models.ErrInvalidMaintenanceWindow))
+
+			noDuration := valid()
+			noDuration.DurationMinutes = 0
+			Expect(noDuration.Validate()).To(MatchError(models.ErrInvalidMaintenanceWindow))
+
+			oneOffWithoutEnd := valid()
+			oneOffWithoutEnd.Schedule = ""
+			Expect(oneOffWithoutEnd.Validate()).To(MatchError(models.ErrInvalidMaintenanceWindow))
+
+			badTimezone := valid()
+			badTimezone.Timezone = "Mars/Olympus"
+			Expect(badTimezone.Validate()).To(MatchError(models.ErrInvalidMaintenanceWindow))
+		})
+	})
+})
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// NotificationState is the delivery state of a notification
+type NotificationState string
+
+const (
+	NotificationStatePending NotificationState = "pending"
+	NotificationStateSuccess NotificationState = "success"
+	NotificationStateFailed  NotificationState = "failed"
+	NotificationStateDead    NotificationState = "dead"    // given up after the maximum number of attempts
+	NotificationStateSkipped NotificationState = "skipped" // not sent on purpose, e.g. the alert resolved before its group was notified
+)
+
+// Valid reports whether the state is a known notification state
+func (s NotificationState) Valid() bool {
+	switch s {
+	case NotificationStatePending, NotificationStateSuccess, NotificationStateFailed, NotificationStateDead, NotificationStateSkipped:
+		return true
+	}
+	return false
+}
+
+// Notification channel types
+const (
+	ChannelTypeEmail     = "email"
+	ChannelTypeSlack     = "slack"
+	ChannelTypeWebhook   = "webhook"
+	ChannelTypePagerDuty = "pagerduty"
+	ChannelTypeRouter    = "router" // queued for the routing tree as a whole by earlier versions, routed per receiver on delivery
+)
+
+// Notification records the delivery of one alert event to one notification channel
+type Notification struct {
+	ID            uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	AlertID       uuid.UUID         `gorm:"type:uuid;not null;index" json:"alert_id"`
+	Channel       string            `gorm:"type:varchar(50);not null" json:"channel"`     // email, slack, webhook, pagerduty
+	Receiver      string            `gorm:"type:varchar(255)" json:"receiver,omitempty"`  // routing receiver or webhook endpoint, e.g. "ops/statuspage"
+	GroupKey      string            `gorm:"type:varchar(255)" json:"group_key,omitempty"` // batched with the due notifications of the same key
+	Event         string            `gorm:"type:varchar(20);not null" json:"event"`       // firing, resolved, acknowledged, ...
+	State         NotificationState `gorm:"type:varchar(20);not null;default:'pending';index" json:"state"`
+	Error         string            `gorm:"type:text" json:"error,omitempty"` // error of the last failed attempt
+	Attempts      int               `gorm:"not null;default:0" json:"attempts"`
+	LastAttemptAt *time.Time        `gorm:"type:timestamp with time zone" json:"last_attempt_at,omitempty"`
+	NextAttemptAt *time.Time        `gorm:"type:timestamp with time zone" json:"next_attempt_at,omitempty"` // set while queued in the outbox
+	DeliveredAt   *time.Time        `gorm:"type:timestamp with time zone" json:"delivered_at,omitempty"`
+	Payload       datatypes.JSON    `gorm:"type:jsonb" json:"-"` // the alert as it was at the event
+	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NewNotification creates a pending notification of an alert event on a channel
+func NewNotification(alertID uuid.UUID, channel, receiver, event string) *Notification {
+	now := time.Now()
+	return &Notification{
+		ID:        uuid.New(),
+		AlertID:   alertID,
+		Channel:   channel,
+		Receiver:  receiver,
+		Event:     event,
+		State:     NotificationStatePending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Snapshot stores the alert as it was at the event. The notification delivers this state, so a
+// retry after the alert changed still reports the event it was queued for.
+func (n *Notification) Snapshot(alert *Alert) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	n.Payload = datatypes.JSON(payload)
+	return nil
+}
+
+// SnapshotAlert returns the alert as it was at the event, or nil if no snapshot was stored
+func (n *Notification) SnapshotAlert() (*Alert, error) {
+	if len(n.Payload) == 0 {
+		return nil, nil
+	}
+	var alert Alert
+	if err := json.Unmarshal(n.Payload, &alert); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// RecordAttempt records a delivery attempt and its outcome; a nil error means the notification was delivered
+func (n *Notification) RecordAttempt(err error) {
+	now := time.Now()
+	n.Attempts++
+	n.LastAttemptAt = &now
+	n.UpdatedAt = now
+	if err != nil {
+		n.State = NotificationStateFailed
+		n.Error = err.Error()
+		return
+	}
+	n.State = NotificationStateSuccess
+	n.Error = ""
+	n.DeliveredAt = &now
+	n.NextAttemptAt = nil
+}
+
+// IsQueued reports whether the outbox still has to deliver the notification
+func (n *Notification) IsQueued() bool {
+	return n.NextAttemptAt != nil && (n.State == NotificationStatePending || n.State == NotificationStateFailed)
+}
+
+// ScheduleAttempt queues the notification in the outbox for delivery at the given time
+func (n *Notification) ScheduleAttempt(at time.Time) {
+	n.NextAttemptAt = &at
+}
+
+// GiveUp dead-letters a notification that failed too often to be retried
+func (n *Notification) GiveUp() {
+	n.State = NotificationStateDead
+	n.NextAttemptAt = nil
+	n.UpdatedAt = time.Now()
+}
+
+// Skip records that the notification was deliberately not sent
+func (n *Notification) Skip() {
+	now := time.Now()
+	n.State = NotificationStateSkipped
+	n.Error = ""
+	n.NextAttemptAt = nil
+	n.UpdatedAt = now
+}
+
+// Requeue queues a failed or dead notification for immediate delivery with a fresh set of attempts
+func (n *Notification) Requeue() error {
+	if n.State != NotificationStateFailed && n.State != NotificationStateDead {
+		return ErrInvalidTransition
+	}
+	now := time.Now()
+	n.State = NotificationStatePending
+	n.Attempts = 0
+	n.NextAttemptAt = &now
+	n.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// MatchOperator is the comparison a silence matcher applies to a label value
+type MatchOperator string
+
+const (
+	MatchEqual     MatchOperator = "="
+	MatchNotEqual  MatchOperator = "!="
+	MatchRegexp    MatchOperator = "=~"
+	MatchNotRegexp MatchOperator = "!~"
+)
+
+// ErrInvalidSilence is returned when a silence fails validation
+var ErrInvalidSilence = errors.New("invalid silence")
+
+// Matcher matches a single alert label
+type Matcher struct {
+	Name     string        `json:"name"`
+	Value    string        `json:"value"`
+	Operator MatchOperator `json:"operator"`
+}
+
+// Validate checks the matcher has a label name, a known operator and a compilable regex
+func (m Matcher) Validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("%w: matcher label name is required", ErrInvalidSilence)
+	}
+	switch m.Operator {
+	case MatchEqual, MatchNotEqual:
+	case MatchRegexp, MatchNotRegexp:
+		if _, err := regexp.Compile(anchored(m.Value)); err != nil {
+			return fmt.Errorf("%w: matcher %q has invalid regex: %v", ErrInvalidSilence, m.Name, err)
+		}
+	default:
+		return fmt.Errorf("%w: matcher %q has unknown operator %q", ErrInvalidSilence, m.Name, m.Operator)
+	}
+	return nil
+}
+
+// Matches reports whether the labels satisfy the matcher.
+// A missing label is treated as an empty value, so negative matchers match it.
+// Regular expressions are anchored to the whole value.
+func (m Matcher) Matches(labels map[string]string) bool {
+	value := labels[m.Name]
+	switch m.Operator {
+	case MatchEqual:
+		return value == m.Value
+	case MatchNotEqual:
+		return value != m.Value
+	case MatchRegexp, MatchNotRegexp:
+		re, err := regexp.Compile(anchored(m.Value))
+		if err != nil {
+			return false
+		}
+		return re.MatchString(value) == (m.Operator == MatchRegexp)
+	default:
+		return false
+	}
+}
+
+// ParseMatcher parses a label matcher written as name=value, name!=value, name=~regex or
+// name!~regex. The value may be double quoted.
+func ParseMatcher(expr string) (Matcher, error) {
+	i := strings.IndexAny(expr, "=!")
+	if i <= 0 {
+		return Matcher{}, fmt.Errorf("invalid matcher %q", expr)
+	}
+
+	matcher := Matcher{Name: strings.TrimSpace(expr[:i])}
+	rest := expr[i:]
+	for _, op := range []MatchOperator{MatchRegexp, MatchNotRegexp, MatchNotEqual, MatchEqual} {
+		if strings.HasPrefix(rest, string(op)) {
+			matcher.Operator = op
+			rest = rest[len(op):]
+			break
+		}
+	}
+	if matcher.Operator == "" || matcher.Name == "" {
+		return Matcher{}, fmt.Errorf("invalid matcher %q", expr)
+	}
+
+	value := strings.TrimSpace(rest)
+	if unquoted, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+		value = unquoted
+	}
+	matcher.Value = value
+
+	if matcher.Operator == MatchRegexp || matcher.Operator == MatchNotRegexp {
+		if _, err := regexp.Compile(anchored(value)); err != nil {
+			return Matcher{}, fmt.Errorf("matcher %q has invalid regex: %w", expr, err)
+		}
+	}
+	return matcher, nil
+}
+
+func anchored(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+// Silence mutes notifications for alerts whose labels match all matchers between StartsAt and EndsAt
+type Silence struct {
+	ID        uuid.UUID                    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Matchers  datatypes.JSONSlice[Matcher] `gorm:"type:jsonb;not null" json:"matchers"`
+	StartsAt  time.Time                    `gorm:"not null;index" json:"starts_at"`
+	EndsAt    time.Time                    `gorm:"not null;index" json:"ends_at"`
+	CreatedBy string                       `gorm:"type:varchar(255);not null" json:"created_by"`
+	Comment   string                       `gorm:"type:text" json:"comment"`
+	CreatedAt time.Time                    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time                    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Silence) TableName() string {
+	return "silences"
+}
+
+// NewSilence creates a new silence
+func NewSilence(matchers []Matcher, startsAt, endsAt time.Time, createdBy, comment string) *Silence {
+	return &Silence{
+		ID:        uuid.New(),
+		Matchers:  datatypes.NewJSONSlice(matchers),
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		CreatedBy: createdBy,
+		Comment:   comment,
+	}
+}
+
+// Validate checks the silence has a creator, at least one valid matcher and ends after it starts
+func (s *Silence) Validate() error {
+	if s.CreatedBy == "" {
+		return fmt.Errorf("%w: created_by is required", ErrInvalidSilence)
+	}
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("%w: at least one matcher is required", ErrInvalidSilence)
+	}
+	for _, matcher := range s.Matchers {
+		if err := matcher.Validate(); err != nil {
+			return err
+		}
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidSilence)
+	}
+	return nil
+}
+
+// IsActive returns true if the silence is in effect at the given time
+func (s *Silence) IsActive(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+// Matches returns true if the labels satisfy every matcher
+func (s *Silence) Matches(labels map[string]string) bool {
+	if len(s.Matchers) == 0 {
+		return false
+	}
+	for _, matcher := range s.Matchers {
+		if !matcher.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package models_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+)
+
+var _ = Describe("Silence", func() {
+	labels := map[string]string{
+		"alert_type": "pod_crash_loop",
+		"namespace":  "staging",
+		"pod":        "api-7f9c",
+	}
+
+	Describe("Matcher", func() {
+		It("should match equality and negation", func() {
+			Expect(models.Matcher{Name: "namespace", Operator: models.MatchEqual, Value: "staging"}.Matches(labels)).To(BeTrue())
+			Expect(models.Matcher{Name: "namespace", Operator: models.MatchNotEqual, Value: "staging"}.Matches(labels)).To(BeFalse())
+			Expect(models.Matcher{Name: "node", Operator: models.MatchNotEqual, Value: "node-1"}.Matches(labels)).To(BeTrue())
+		})
+
+		It("should anchor regular expressions to the whole value", func() {
+			Expect(models.Matcher{Name: "pod", Operator: models.MatchRegexp, Value: "api-.*"}.Matches(labels)).To(BeTrue())
+			Expect(models.Matcher{Name: "pod", Operator: models.MatchRegexp, Value: "api"}.Matches(labels)).To(BeFalse())
+			Expect(models.Matcher{Name: "pod", Operator: models.MatchNotRegexp, Value: "web-.*"}.Matches(labels)).To(BeTrue())
+		})
+
+		It("should reject invalid matchers", func() {
+			Expect(models.Matcher{Operator: models.MatchEqual, Value: "x"}.Validate()).To(MatchError(models.ErrInvalidSilence))
+			Expect(models.Matcher{Name: "pod", Operator: "~", Value: "x"}.Validate()).To(MatchError(models.ErrInvalidSilence))
+			Expect(models.Matcher{Name: "pod", Operator: models.MatchRegexp, Value: "("}.Validate()).To(MatchError(models.ErrInvalidSilence))
+		})
+	})
+
+	Describe("Matches", func() {
+		It("should require every matcher to match", func() {
+			silence := models.NewSilence([]models.Matcher{
+				{Name: "namespace", Operator: models.MatchEqual, Value: "staging"},
+				{Name: "alert_type", Operator: models.MatchEqual, Value: "pod_oom_killed"},
+			}, time.Now(), time.Now().Add(time.Hour), "alice", "")
+
+			Expect(silence.Matches(labels)).To(BeFalse())
+		})
+	})
+
+	Describe("IsActive", func() {
+		It("should be active only between starts_at and ends_at", func() {
+			now := time.Now()
+			silence := models.NewSilence(nil, now, now.Add(time.Hour), "alice", "")
+
+			Expect(silence.IsActive(now.Add(-time.Second))).To(BeFalse())
+			Expect(silence.IsActive(now.Add(time.Minute))).To(BeTrue())
+			Expect(silence.IsActive(now.Add(time.Hour))).To(BeFalse())
+		})
+	})
+
+	Describe("Validate", func() {
+		It("should require matchers and an end after the start", func() {
+			now := time.Now()
+			matchers := []models.Matcher{{Name: "namespace", Operator: models.MatchEqual, Value: "staging"}}
+
+			Expect(models.NewSilence(nil, now, now.Add(time.Hour), "alice", "").Validate()).To(MatchError(models.ErrInvalidSilence))
+			Expect(models.NewSilence(matchers, now, now, "alice", "").Validate()).To(MatchError(models.ErrInvalidSilence))
+			Expect(models.NewSilence(matchers, now, now.Add(time.Hour), "", "").Validate()).To(MatchError(models.ErrInvalidSilence))
+			Expect(models.NewSilence(matchers, now, now.Add(time.Hour), "alice", "").Validate()).To(Succeed())
+		})
+	})
+})
@@ -2,72 +2,335 @@ package notifier
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
 	"net/smtp"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/monitoring-engine/monitoring-tool/internal/config"
-	"github.com/monitoring-engine/monitoring-tool/internal/processor"
 	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
 )
 
-// EmailDispatcher sends alerts via email
+// EmailDispatcher sends alerts as MIME emails with text and HTML parts, or as periodic digests
 type EmailDispatcher struct {
-	config config.EmailConfig
+	config    config.EmailConfig
+	templates *emailTemplates
+	domain    string // of the sender, used in Message-IDs
 }
 
-func NewEmailDispatcher(cfg config.EmailConfig) *EmailDispatcher {
-	return &EmailDispatcher{
-		config: cfg,
+// NewEmailDispatcher creates an email dispatcher, failing on an unknown TLS mode or an invalid template file
+func NewEmailDispatcher(cfg config.EmailConfig) (*EmailDispatcher, error) {
+	switch cfg.TLS {
+	case "", emailTLSAuto, emailTLSStartTLS, emailTLSImplicit, emailTLSNone:
+	default:
+		return nil, fmt.Errorf("email: unknown tls mode %q", cfg.TLS)
+	}
+	if cfg.Digest.Interval <= 0 {
+		cfg.Digest.Interval = time.Hour
+	}
+
+	templates, err := loadEmailTemplates(cfg.TemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("email: %w", err)
 	}
+
+	return &EmailDispatcher{
+		config:    cfg,
+		templates: templates,
+		domain:    messageDomain(cfg.From),
+	}, nil
+}
+
+// Email TLS modes
+const (
+	emailTLSAuto     = "auto"     // STARTTLS when the server offers it
+	emailTLSStartTLS = "starttls" // STARTTLS, failing if the server does not offer it
+	emailTLSImplicit = "tls"      // TLS from the start of the connection, usually port 465
+	emailTLSNone     = "none"     // never encrypt
+)
+
+// Accepts reports whether the event is emailed. Acknowledgements are for dashboards only,
+// the on-call engineer already knows.
+func (ed *EmailDispatcher) Accepts(event *processor.AlertEvent) bool {
+	return !event.IsAcknowledgement()
 }
 
-// OnAlert implements AlertObserver interface
+// OnAlert implements AlertObserver interface. In digest mode the alert is sent as a digest of its own;
+// the outbox batches digest notifications and sends them with NotifyBatch.
 func (ed *EmailDispatcher) OnAlert(ctx context.Context, event *processor.AlertEvent) error {
 	if ed.config.SMTPHost == "" || ed.config.Username == "" {
 		logger.Warn().Msg("Email configuration incomplete, skipping email dispatch")
 		return nil
 	}
 
-	// Format email
-	subject := fmt.Sprintf("Alert: %s - %s", event.Alert.Severity, event.Alert.Source)
-	body := fmt.Sprintf(`
-Monitoring Alert
+	if !ed.Accepts(event) {
+		return nil
+	}
+
+	if ed.config.Digest.Enabled {
+		return ed.NotifyBatch(ctx, []*processor.AlertEvent{event})
+	}
+
+	message, err := ed.alertMessage(event)
+	if err != nil {
+		return err
+	}
+	if err := ed.send(ctx, message); err != nil {
+		return err
+	}
+
+	logger.Info().
+		Strs("to", ed.config.To).
+		Str("severity", string(event.Alert.Severity)).
+		Bool("resolved", event.IsResolved()).
+		Msg("Alert email sent")
+	return nil
+}
+
+// NotifyGroup implements GroupNotifier interface, sending one email for the whole group.
+// In digest mode the group is sent as a digest.
+func (ed *EmailDispatcher) NotifyGroup(ctx context.Context, group *AlertGroup) error {
+	if ed.config.SMTPHost == "" || ed.config.Username == "" {
+		logger.Warn().Msg("Email configuration incomplete, skipping email dispatch")
+		return nil
+	}
+
+	if ed.config.Digest.Enabled {
+		return ed.NotifyBatch(ctx, group.events(time.Now()))
+	}
 
-Severity: %s
-Source: %s
-Message: %s
-Value: %.2f
-Timestamp: %s
+	message, err := ed.groupMessage(group)
+	if err != nil {
+		return err
+	}
+	if err := ed.send(ctx, message); err != nil {
+		return err
+	}
 
-Labels:
-%v
+	logger.Info().
+		Strs("to", ed.config.To).
+		Int("firing", len(group.Firing)).
+		Int("resolved", len(group.Resolved)).
+		Msg("Alert group email sent")
+	return nil
+}
+
+// Batch implements processor.Batcher interface. In digest mode every alert event joins the
+// digest of the email.digest.interval window it happens in, due when the window ends.
+func (ed *EmailDispatcher) Batch(event *processor.AlertEvent, now time.Time) (string, time.Time) {
+	if !ed.config.Digest.Enabled {
+		return "", time.Time{}
+	}
+	dueAt := now.Truncate(ed.config.Digest.Interval).Add(ed.config.Digest.Interval)
+	return "digest|" + dueAt.UTC().Format(time.RFC3339), dueAt
+}
 
---
-Monitoring Engine
-`, event.Alert.Severity, event.Alert.Source, event.Alert.Message,
-		event.Alert.Value, event.Alert.CreatedAt.Format(time.RFC3339), event.Alert.Labels)
+// NotifyBatch implements processor.Batcher interface, emailing one digest of the latest state of
+// each alert of the events. The digest covers the alerts since the oldest event.
+func (ed *EmailDispatcher) NotifyBatch(ctx context.Context, events []*processor.AlertEvent) error {
+	var since time.Time
+	firing := make(map[uuid.UUID]*models.Alert)
+	resolved := make(map[uuid.UUID]*models.Alert)
+	for _, event := range events {
+		if !ed.Accepts(event) {
+			continue
+		}
+		if since.IsZero() || (!event.Timestamp.IsZero() && event.Timestamp.Before(since)) {
+			since = event.Timestamp
+		}
+		if event.IsResolved() {
+			delete(firing, event.Alert.ID)
+			resolved[event.Alert.ID] = event.Alert
+		} else {
+			delete(resolved, event.Alert.ID)
+			firing[event.Alert.ID] = event.Alert
+		}
+	}
+	if len(firing)+len(resolved) == 0 {
+		return nil
+	}
+	if since.IsZero() {
+		since = time.Now()
+	}
 
-	message := []byte(fmt.Sprintf("Subject: %s\r\n\r\n%s", subject, body))
+	message, err := ed.digestMessage(since, firing, resolved)
+	if err != nil {
+		return err
+	}
+	if err := ed.send(ctx, message); err != nil {
+		return err
+	}
 
-	// Setup authentication
-	auth := smtp.PlainAuth("", ed.config.Username, ed.config.Password, ed.config.SMTPHost)
+	logger.Info().
+		Strs("to", ed.config.To).
+		Int("firing", len(firing)).
+		Int("resolved", len(resolved)).
+		Msg("Alert digest email sent")
+	return nil
+}
 
-	// Send email with retry
-	addr := fmt.Sprintf("%s:%d", ed.config.SMTPHost, ed.config.SMTPPort)
+// alertMessage renders the email of an alert event. Resolution emails reply to the firing email.
+func (ed *EmailDispatcher) alertMessage(event *processor.AlertEvent) (*emailMessage, error) {
+	alert := event.Alert
+	data := &EmailTemplateData{Event: event.Type, Timestamp: time.Now()}
+	message := &emailMessage{messageID: alertMessageID(alert.ID, ed.domain)}
 
+	if event.IsResolved() {
+		data.Title = fmt.Sprintf("Resolved: %s - %s", alert.Severity, alert.Source)
+		data.Resolved = newEmailAlerts([]*models.Alert{alert}, true)
+		message.messageID = randomMessageID(ed.domain)
+		message.inReplyTo = alertMessageID(alert.ID, ed.domain)
+		message.references = []string{message.inReplyTo}
+	} else {
+		data.Title = fmt.Sprintf("Alert: %s - %s", alert.Severity, alert.Source)
+		data.Firing = newEmailAlerts([]*models.Alert{alert}, false)
+	}
+	return ed.render(message, data)
+}
+
+// groupMessage renders one email listing the firing and resolved alerts of a group
+func (ed *EmailDispatcher) groupMessage(group *AlertGroup) (*emailMessage, error) {
+	data := &EmailTemplateData{
+		Title:     group.title(),
+		Firing:    newEmailAlerts(group.Firing, false),
+		Resolved:  newEmailAlerts(group.Resolved, true),
+		Timestamp: time.Now(),
+	}
+	return ed.render(&emailMessage{messageID: randomMessageID(ed.domain)}, data)
+}
+
+// digestMessage renders the summary of the alerts of a digest window
+func (ed *EmailDispatcher) digestMessage(since time.Time, firing, resolved map[uuid.UUID]*models.Alert) (*emailMessage, error) {
+	firingAlerts := make([]*models.Alert, 0, len(firing))
+	for _, alert := range firing {
+		firingAlerts = append(firingAlerts, alert)
+	}
+	resolvedAlerts := make([]*models.Alert, 0, len(resolved))
+	for _, alert := range resolved {
+		resolvedAlerts = append(resolvedAlerts, alert)
+	}
+	sortAlerts(firingAlerts)
+	sortAlerts(resolvedAlerts)
+
+	data := &EmailTemplateData{
+		Title:     fmt.Sprintf("Alert digest: %d firing, %d resolved", len(firing), len(resolved)),
+		Firing:    newEmailAlerts(firingAlerts, false),
+		Resolved:  newEmailAlerts(resolvedAlerts, true),
+		Digest:    true,
+		Since:     since,
+		Timestamp: time.Now(),
+	}
+	return ed.render(&emailMessage{messageID: randomMessageID(ed.domain)}, data)
+}
+
+func (ed *EmailDispatcher) render(message *emailMessage, data *EmailTemplateData) (*emailMessage, error) {
+	var err error
+	message.subject, message.text, message.html, err = ed.templates.render(data)
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// send sends a mail to the configured recipients, retrying once unless ctx is done first
+func (ed *EmailDispatcher) send(ctx context.Context, message *emailMessage) error {
 	var err error
 	for attempt := 0; attempt < 2; attempt++ {
-		err = smtp.SendMail(addr, auth, ed.config.From, ed.config.To, message)
-		if err == nil {
-			logger.Info().
-				Strs("to", ed.config.To).
-				Str("severity", string(event.Alert.Severity)).
-				Msg("Alert email sent")
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("email dispatch failed: %w (last error: %v)", ctx.Err(), err)
+			case <-time.After(1 * time.Second):
+			}
+		}
+		var data []byte
+		if data, err = message.build(ed.config.From, ed.config.To, time.Now()); err != nil {
+			return fmt.Errorf("failed to build email: %w", err)
+		}
+		if err = ed.sendMail(ctx, data); err == nil {
 			return nil
 		}
-		time.Sleep(1 * time.Second)
 	}
 
 	return fmt.Errorf("email dispatch failed after retries: %w", err)
 }
+
+// sendMail delivers the message over SMTP, encrypting the connection as configured. The SMTP
+// conversation ends at ctx's deadline, or as soon as ctx is cancelled.
+func (ed *EmailDispatcher) sendMail(ctx context.Context, message []byte) error {
+	addr := net.JoinHostPort(ed.config.SMTPHost, strconv.Itoa(ed.config.SMTPPort))
+	tlsConfig := &tls.Config{
+		ServerName:         ed.config.SMTPHost,
+		InsecureSkipVerify: ed.config.InsecureSkipVerify,
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if ed.config.TLS == emailTLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	// Unblock the conversation when ctx is cancelled before its deadline
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, ed.config.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ed.config.TLS != emailTLSImplicit && ed.config.TLS != emailTLSNone {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		} else if ed.config.TLS == emailTLSStartTLS {
+			return fmt.Errorf("smtp server %s does not support STARTTLS", ed.config.SMTPHost)
+		}
+	}
+
+	if ok, _ := client.Extension("AUTH"); ok && ed.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", ed.config.Username, ed.config.Password, ed.config.SMTPHost)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(ed.config.From); err != nil {
+		return err
+	}
+	for _, to := range ed.config.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
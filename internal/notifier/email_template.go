@@ -0,0 +1,279 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// defaultEmailTemplates renders every email. A template_file may redefine any of them.
+const defaultEmailTemplates = `
+{{define "email.subject"}}{{.Title}}{{end}}
+
+{{define "email.text"}}
+{{- if .Digest}}Monitoring Alert Digest
+{{.Since.Format "2006-01-02 15:04 MST"}} - {{.Timestamp.Format "2006-01-02 15:04 MST"}}
+{{else if .Resolved}}{{if .Firing}}Monitoring Alert Group{{else}}Monitoring Alert Resolved{{end}}
+{{else}}Monitoring Alert
+{{end}}
+{{- if .Firing}}
+Firing ({{len .Firing}}):
+{{range .Firing}}
+[{{.Severity}}] {{.Message}}
+  Source: {{.Source}}
+  Value: {{printf "%.2f" .Value}}
+  Since: {{.TriggeredAt.Format "2006-01-02T15:04:05Z07:00"}}
+{{- range $name, $value := .Labels}}
+  {{$name}}: {{$value}}
+{{- end}}
+{{end}}{{end}}
+{{- if .Resolved}}
+Resolved ({{len .Resolved}}):
+{{range .Resolved}}
+[{{.Severity}}] {{.Message}}
+  Source: {{.Source}}
+  Triggered: {{.TriggeredAt.Format "2006-01-02T15:04:05Z07:00"}}
+  Duration: {{.Duration}}
+{{- range $name, $value := .Labels}}
+  {{$name}}: {{$value}}
+{{- end}}
+{{end}}{{end}}
+--
+Monitoring Engine
+{{end}}
+
+{{define "email.alerts"}}
+<h3 style="margin:16px 0 8px">{{.Heading}}</h3>
+<table style="border-collapse:collapse;width:100%">
+{{- range .Alerts}}
+<tr><td style="border-left:4px solid {{severityColor .Severity}};padding:8px 12px;background:#f6f8fa">
+<strong>[{{.Severity}}] {{.Message}}</strong><br>
+<span style="color:#57606a">{{.Source}} &middot; {{if .Resolved}}resolved after {{.Duration}}{{else}}value {{printf "%.2f" .Value}} &middot; since {{.TriggeredAt.Format "2006-01-02 15:04:05 MST"}}{{end}}</span>
+{{- if .Labels}}<br><span style="font-family:monospace;font-size:12px">{{range $name, $value := .Labels}}{{$name}}={{$value}} {{end}}</span>{{end}}
+</td></tr>
+<tr><td style="height:6px"></td></tr>
+{{- end}}
+</table>
+{{end}}
+
+{{define "email.html"}}<!DOCTYPE html>
+<html>
+<body style="font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;font-size:14px;color:#24292f">
+<h2 style="margin:0 0 4px">{{.Title}}</h2>
+{{- if .Digest}}
+<p style="color:#57606a;margin:0">{{.Since.Format "2006-01-02 15:04 MST"}} - {{.Timestamp.Format "2006-01-02 15:04 MST"}}</p>
+{{- end}}
+{{- if .Firing}}{{template "email.alerts" (section (printf "Firing (%d)" (len .Firing)) .Firing)}}{{end}}
+{{- if .Resolved}}{{template "email.alerts" (section (printf "Resolved (%d)" (len .Resolved)) .Resolved)}}{{end}}
+<p style="color:#57606a;font-size:12px">Monitoring Engine</p>
+</body>
+</html>
+{{end}}
+`
+
+// EmailAlert is an alert as email templates see it, with its labels as a map
+type EmailAlert struct {
+	*models.Alert
+	Labels   map[string]string
+	Resolved bool
+	Duration time.Duration // how long a resolved alert fired
+}
+
+// EmailTemplateData is the data email templates are rendered with
+type EmailTemplateData struct {
+	Title     string                   // the default subject
+	Event     processor.AlertEventType // event of a single alert email, empty for groups and digests
+	Firing    []EmailAlert
+	Resolved  []EmailAlert
+	Digest    bool
+	Since     time.Time // start of the digest window
+	Timestamp time.Time
+}
+
+// emailSection is one list of alerts in the HTML template
+type emailSection struct {
+	Heading string
+	Alerts  []EmailAlert
+}
+
+var emailFuncs = map[string]interface{}{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"severityColor": func(severity string) string {
+		switch severity {
+		case "critical":
+			return "#cf222e"
+		case "high":
+			return "#fb8500"
+		case "medium":
+			return "#d4a72c"
+		default:
+			return "#2da44e"
+		}
+	},
+	"section": func(heading string, alerts []EmailAlert) emailSection {
+		return emailSection{Heading: heading, Alerts: alerts}
+	},
+}
+
+// emailTemplates are the parsed subject, text and HTML templates
+type emailTemplates struct {
+	text *template.Template
+	html *htmltemplate.Template
+}
+
+// loadEmailTemplates parses the default templates and the optional template file redefining them
+func loadEmailTemplates(file string) (*emailTemplates, error) {
+	text, err := template.New("email").Funcs(emailFuncs).Parse(defaultEmailTemplates)
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.New("email").Funcs(emailFuncs).Parse(defaultEmailTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	if file != "" {
+		overrides, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read email template file: %w", err)
+		}
+		if text, err = text.Parse(string(overrides)); err != nil {
+			return nil, fmt.Errorf("invalid email template file: %w", err)
+		}
+		if html, err = html.Parse(string(overrides)); err != nil {
+			return nil, fmt.Errorf("invalid email template file: %w", err)
+		}
+	}
+	return &emailTemplates{text: text, html: html}, nil
+}
+
+// render returns the subject, plain text and HTML bodies of an email
+func (t *emailTemplates) render(data *EmailTemplateData) (string, string, string, error) {
+	var subject, text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, "email.subject", data); err != nil {
+		return "", "", "", fmt.Errorf("failed to render email subject: %w", err)
+	}
+	if err := t.text.ExecuteTemplate(&text, "email.text", data); err != nil {
+		return "", "", "", fmt.Errorf("failed to render email text: %w", err)
+	}
+	if err := t.html.ExecuteTemplate(&html, "email.html", data); err != nil {
+		return "", "", "", fmt.Errorf("failed to render email html: %w", err)
+	}
+	// Header values must be a single line
+	return strings.Join(strings.Fields(subject.String()), " "), text.String(), html.String(), nil
+}
+
+func newEmailAlerts(alerts []*models.Alert, resolved bool) []EmailAlert {
+	emailAlerts := make([]EmailAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		emailAlert := EmailAlert{Alert: alert, Labels: alert.GetLabelsMap(), Resolved: resolved}
+		if resolved {
+			emailAlert.Duration = resolvedDuration(alert)
+		}
+		emailAlerts = append(emailAlerts, emailAlert)
+	}
+	return emailAlerts
+}
+
+// emailMessage is a rendered email with the headers that thread it
+type emailMessage struct {
+	subject    string
+	text       string
+	html       string
+	messageID  string
+	inReplyTo  string // Message-ID of the email this one follows up, e.g. the firing email of a resolution
+	references []string
+}
+
+// alertMessageID is the Message-ID of the firing email of an alert. Resolution emails reply to it
+// so mail clients show them in one thread.
+func alertMessageID(alertID uuid.UUID, domain string) string {
+	return fmt.Sprintf("<alert.%s@%s>", alertID, domain)
+}
+
+// randomMessageID returns a unique Message-ID
+func randomMessageID(domain string) string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(b[:]), domain)
+}
+
+// messageDomain returns the domain of the sender address, used in Message-IDs
+func messageDomain(from string) string {
+	if address, err := mail.ParseAddress(from); err == nil {
+		from = address.Address
+	}
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		return from[at+1:]
+	}
+	return "monitoring-engine.local"
+}
+
+// build returns the message as a MIME multipart/alternative email with text and HTML parts
+func (m *emailMessage) build(from string, to []string, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		// Clients show the last alternative they support, so HTML goes last
+		{"text/plain; charset=UTF-8", m.text},
+		{"text/html; charset=UTF-8", m.html},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&message, "%s: %s\r\n", name, value)
+	}
+	header("From", from)
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", m.messageID)
+	if m.inReplyTo != "" {
+		header("In-Reply-To", m.inReplyTo)
+	}
+	if len(m.references) > 0 {
+		header("References", strings.Join(m.references, " "))
+	}
+	header("MIME-Version", "1.0")
+	header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", parts.Boundary()))
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
@@ -1,7 +1,18 @@
 package notifier_test
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,6 +22,7 @@ import (
 	"github.com/monitoring-engine/monitoring-tool/internal/notifier"
 	"github.com/monitoring-engine/monitoring-tool/internal/processor"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/datatypes"
 )
 
@@ -26,13 +38,15 @@ func TestNewEmailDispatcher(t *testing.T) {
 			To:       []string{"admin@example.com"},
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		assert.NotNil(t, dispatcher)
 	})
 
 	t.Run("should create dispatcher with empty config", func(t *testing.T) {
 		cfg := config.EmailConfig{}
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		assert.NotNil(t, dispatcher)
 	})
 }
@@ -44,7 +58,8 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Username: "user@example.com",
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		ctx := context.Background()
 
 		alert := &models.Alert{
@@ -64,7 +79,7 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Timestamp: time.Now(),
 		}
 
-		err := dispatcher.OnAlert(ctx, event)
+		err = dispatcher.OnAlert(ctx, event)
 		assert.NoError(t, err)
 	})
 
@@ -74,7 +89,8 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Username: "",
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		ctx := context.Background()
 
 		alert := &models.Alert{
@@ -94,7 +110,7 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Timestamp: time.Now(),
 		}
 
-		err := dispatcher.OnAlert(ctx, event)
+		err = dispatcher.OnAlert(ctx, event)
 		assert.NoError(t, err)
 	})
 
@@ -104,7 +120,8 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Username: "",
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		ctx := context.Background()
 
 		alert := &models.Alert{
@@ -124,7 +141,7 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Timestamp: time.Now(),
 		}
 
-		err := dispatcher.OnAlert(ctx, event)
+		err = dispatcher.OnAlert(ctx, event)
 		assert.NoError(t, err)
 	})
 
@@ -134,7 +151,8 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Username: "",
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel() // Cancel immediately
 
@@ -156,7 +174,7 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 		}
 
 		// Should not panic or hang
-		err := dispatcher.OnAlert(ctx, event)
+		err = dispatcher.OnAlert(ctx, event)
 		assert.NoError(t, err) // Skips due to incomplete config
 	})
 
@@ -166,7 +184,8 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Username: "",
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		ctx := context.Background()
 
 		now := time.Now()
@@ -187,7 +206,7 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Timestamp: now,
 		}
 
-		err := dispatcher.OnAlert(ctx, event)
+		err = dispatcher.OnAlert(ctx, event)
 		assert.NoError(t, err)
 	})
 
@@ -197,7 +216,8 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Username: "",
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		ctx := context.Background()
 
 		alert := &models.Alert{
@@ -217,7 +237,7 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Timestamp: time.Now(),
 		}
 
-		err := dispatcher.OnAlert(ctx, event)
+		err = dispatcher.OnAlert(ctx, event)
 		assert.NoError(t, err)
 	})
 
@@ -227,7 +247,8 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Username: "",
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		ctx := context.Background()
 
 		alert := &models.Alert{
@@ -247,7 +268,7 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Timestamp: time.Now(),
 		}
 
-		err := dispatcher.OnAlert(ctx, event)
+		err = dispatcher.OnAlert(ctx, event)
 		assert.NoError(t, err)
 	})
 
@@ -257,7 +278,8 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Username: "",
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		ctx := context.Background()
 
 		alert := &models.Alert{
@@ -277,7 +299,7 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Timestamp: time.Now(),
 		}
 
-		err := dispatcher.OnAlert(ctx, event)
+		err = dispatcher.OnAlert(ctx, event)
 		assert.NoError(t, err)
 	})
 
@@ -287,7 +309,8 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Username: "",
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		ctx := context.Background()
 
 		alert := &models.Alert{
@@ -307,7 +330,7 @@ func TestEmailDispatcher_OnAlert(t *testing.T) {
 			Timestamp: time.Now(),
 		}
 
-		err := dispatcher.OnAlert(ctx, event)
+		err = dispatcher.OnAlert(ctx, event)
 		assert.NoError(t, err)
 	})
 }
@@ -362,7 +385,8 @@ func TestEmailDispatcher_ConfigVariations(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			dispatcher := notifier.NewEmailDispatcher(tt.config)
+			dispatcher, err := notifier.NewEmailDispatcher(tt.config)
+			require.NoError(t, err)
 			assert.NotNil(t, dispatcher)
 
 			ctx := context.Background()
@@ -384,7 +408,7 @@ func TestEmailDispatcher_ConfigVariations(t *testing.T) {
 			}
 
 			// All incomplete configs should skip sending without error
-			err := dispatcher.OnAlert(ctx, event)
+			err = dispatcher.OnAlert(ctx, event)
 			if tt.config.SMTPHost == "" || tt.config.Username == "" {
 				assert.NoError(t, err)
 			}
@@ -402,7 +426,8 @@ func TestEmailDispatcher_AlertSeverities(t *testing.T) {
 				Username: "",
 			}
 
-			dispatcher := notifier.NewEmailDispatcher(cfg)
+			dispatcher, err := notifier.NewEmailDispatcher(cfg)
+			require.NoError(t, err)
 			ctx := context.Background()
 
 			alert := &models.Alert{
@@ -422,7 +447,7 @@ func TestEmailDispatcher_AlertSeverities(t *testing.T) {
 				Timestamp: time.Now(),
 			}
 
-			err := dispatcher.OnAlert(ctx, event)
+			err = dispatcher.OnAlert(ctx, event)
 			assert.NoError(t, err)
 		})
 	}
@@ -445,7 +470,8 @@ func TestEmailDispatcher_AlertSources(t *testing.T) {
 				Username: "",
 			}
 
-			dispatcher := notifier.NewEmailDispatcher(cfg)
+			dispatcher, err := notifier.NewEmailDispatcher(cfg)
+			require.NoError(t, err)
 			ctx := context.Background()
 
 			alert := &models.Alert{
@@ -465,7 +491,7 @@ func TestEmailDispatcher_AlertSources(t *testing.T) {
 				Timestamp: time.Now(),
 			}
 
-			err := dispatcher.OnAlert(ctx, event)
+			err = dispatcher.OnAlert(ctx, event)
 			assert.NoError(t, err)
 		})
 	}
@@ -479,7 +505,8 @@ func TestEmailDispatcher_MultipleRecipients(t *testing.T) {
 			To:       []string{"admin@example.com"},
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		assert.NotNil(t, dispatcher)
 	})
 
@@ -494,7 +521,8 @@ func TestEmailDispatcher_MultipleRecipients(t *testing.T) {
 			},
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		assert.NotNil(t, dispatcher)
 	})
 
@@ -505,7 +533,286 @@ func TestEmailDispatcher_MultipleRecipients(t *testing.T) {
 			To:       []string{},
 		}
 
-		dispatcher := notifier.NewEmailDispatcher(cfg)
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
 		assert.NotNil(t, dispatcher)
 	})
 }
+
+// fakeSMTP is a plain-text SMTP server that records the messages it receives
+type fakeSMTP struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []*mail.Message
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeSMTP{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		switch command := strings.ToUpper(strings.Fields(line + " ")[0]); command {
+		case "EHLO":
+			text.PrintfLine("250-localhost\r\n250 AUTH PLAIN")
+		case "AUTH":
+			text.PrintfLine("235 Authentication successful")
+		case "DATA":
+			text.PrintfLine("354 Go ahead")
+			data, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+			message, err := mail.ReadMessage(bytes.NewReader(data))
+			if err != nil {
+				text.PrintfLine("554 Invalid message")
+				continue
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, message)
+			s.mu.Unlock()
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("250 OK")
+		}
+	}
+}
+
+func (s *fakeSMTP) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTP) received() []*mail.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*mail.Message(nil), s.messages...)
+}
+
+// emailParts returns the decoded text and HTML parts of a multipart/alternative message
+func emailParts(t *testing.T, message *mail.Message) (string, string) {
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(part) // quoted-printable is decoded by the reader
+		require.NoError(t, err)
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[partType] = string(content)
+	}
+	return parts["text/plain"], parts["text/html"]
+}
+
+func TestEmailDispatcher_Send(t *testing.T) {
+	newConfig := func(server *fakeSMTP) config.EmailConfig {
+		return config.EmailConfig{
+			Enabled:  true,
+			SMTPHost: "127.0.0.1",
+			SMTPPort: server.port(),
+			Username: "alerts@example.com",
+			Password: "secret",
+			From:     "Monitoring <alerts@example.com>",
+			To:       []string{"oncall@example.com", "team@example.com"},
+		}
+	}
+
+	t.Run("should send a multipart email with headers and threaded resolution", func(t *testing.T) {
+		server := newFakeSMTP(t)
+		dispatcher, err := notifier.NewEmailDispatcher(newConfig(server))
+		require.NoError(t, err)
+
+		alert := newNotifiedAlert("critical")
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Type: processor.AlertEventFiring, Alert: alert}))
+		alert.Resolve()
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Type: processor.AlertEventResolved, Alert: alert}))
+
+		messages := server.received()
+		require.Len(t, messages, 2)
+		firing, resolved := messages[0], messages[1]
+
+		assert.Equal(t, "Monitoring <alerts@example.com>", firing.Header.Get("From"))
+		assert.Equal(t, "oncall@example.com, team@example.com", firing.Header.Get("To"))
+		assert.Equal(t, "Alert: critical - k8s_pod", firing.Header.Get("Subject"))
+		assert.Equal(t, "1.0", firing.Header.Get("MIME-Version"))
+		_, err = firing.Header.Date()
+		assert.NoError(t, err)
+		assert.Equal(t, "<alert."+alert.ID.String()+"@example.com>", firing.Header.Get("Message-ID"))
+
+		text, html := emailParts(t, firing)
+		assert.Contains(t, text, "CRASH LOOP BACKOFF")
+		assert.Contains(t, text, "namespace: default")
+		assert.Contains(t, html, "<strong>[critical] Pod default/api-0 container &#39;app&#39; is in CRASH LOOP BACKOFF</strong>")
+		assert.Contains(t, html, "pod=api-0")
+		assert.Contains(t, html, "border-left:4px solid #cf222e")
+
+		assert.Equal(t, "Resolved: critical - k8s_pod", resolved.Header.Get("Subject"))
+		assert.NotEqual(t, firing.Header.Get("Message-ID"), resolved.Header.Get("Message-ID"))
+		assert.Equal(t, firing.Header.Get("Message-ID"), resolved.Header.Get("In-Reply-To"))
+		assert.Equal(t, firing.Header.Get("Message-ID"), resolved.Header.Get("References"))
+		text, _ = emailParts(t, resolved)
+		assert.Contains(t, text, "Resolved (1)")
+	})
+
+	t.Run("should render templates redefined in the template file", func(t *testing.T) {
+		server := newFakeSMTP(t)
+		templateFile := filepath.Join(t.TempDir(), "email.tmpl")
+		require.NoError(t, os.WriteFile(templateFile, []byte(`{{define "email.subject"}}[{{upper (index .Firing 0).Severity}}] {{(index .Firing 0).Labels.pod}}{{end}}`), 0o600))
+
+		cfg := newConfig(server)
+		cfg.TemplateFile = templateFile
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
+
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Type: processor.AlertEventFiring, Alert: newNotifiedAlert("high")}))
+		messages := server.received()
+		require.Len(t, messages, 1)
+		assert.Equal(t, "[HIGH] api-0", messages[0].Header.Get("Subject"))
+		text, _ := emailParts(t, messages[0])
+		assert.Contains(t, text, "Monitoring Alert", "templates that are not redefined keep the default")
+	})
+
+	t.Run("should batch the alerts of a digest interval", func(t *testing.T) {
+		cfg := newConfig(newFakeSMTP(t))
+		cfg.Digest = config.EmailDigestConfig{Enabled: true, Interval: time.Hour}
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
+
+		event := &processor.AlertEvent{Type: processor.AlertEventFiring, Alert: newNotifiedAlert("high")}
+		windowStart := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		key, dueAt := dispatcher.Batch(event, windowStart.Add(5*time.Minute))
+		assert.NotEmpty(t, key)
+		assert.Equal(t, windowStart.Add(time.Hour), dueAt)
+
+		sameKey, _ := dispatcher.Batch(event, windowStart.Add(59*time.Minute))
+		nextKey, _ := dispatcher.Batch(event, windowStart.Add(61*time.Minute))
+		assert.Equal(t, key, sameKey)
+		assert.NotEqual(t, key, nextKey)
+
+		cfg.Digest.Enabled = false
+		immediate, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
+		key, _ = immediate.Batch(event, windowStart)
+		assert.Empty(t, key, "without digests every alert is emailed on its own")
+	})
+
+	t.Run("should send one digest with the latest state of each alert", func(t *testing.T) {
+		server := newFakeSMTP(t)
+		cfg := newConfig(server)
+		cfg.Digest = config.EmailDigestConfig{Enabled: true, Interval: time.Hour}
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
+
+		crashLoop := newNotifiedAlert("critical")
+		oomKilled := newNotifiedAlert("high")
+		oomKilled.Message = "Pod default/api-1 was OOM killed"
+		resolvedOOM := *oomKilled
+		resolvedOOM.Resolve()
+		require.NoError(t, dispatcher.NotifyBatch(context.Background(), []*processor.AlertEvent{
+			{Type: processor.AlertEventFiring, Alert: crashLoop, Timestamp: time.Now().Add(-time.Minute)},
+			{Type: processor.AlertEventFiring, Alert: oomKilled, Timestamp: time.Now().Add(-time.Minute)},
+			{Type: processor.AlertEventResolved, Alert: &resolvedOOM, Timestamp: time.Now()},
+		}))
+
+		messages := server.received()
+		require.Len(t, messages, 1)
+		assert.Equal(t, "Alert digest: 1 firing, 1 resolved", messages[0].Header.Get("Subject"))
+		text, _ := emailParts(t, messages[0])
+		assert.Contains(t, text, "Monitoring Alert Digest")
+		assert.Contains(t, text, "CRASH LOOP BACKOFF")
+		assert.Contains(t, text, "OOM killed")
+	})
+
+	t.Run("should return the error of a digest that could not be sent", func(t *testing.T) {
+		server := newFakeSMTP(t)
+		cfg := newConfig(server)
+		cfg.Digest = config.EmailDigestConfig{Enabled: true, Interval: time.Hour}
+		server.listener.Close()
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		err = dispatcher.NotifyBatch(ctx, []*processor.AlertEvent{{Type: processor.AlertEventFiring, Alert: newNotifiedAlert("medium")}})
+		assert.Error(t, err, "the outbox keeps the notifications queued and retries the digest")
+	})
+
+	t.Run("should give up when the context ends during the SMTP conversation", func(t *testing.T) {
+		// A server that accepts connections but never greets
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { listener.Close() })
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				t.Cleanup(func() { conn.Close() })
+			}
+		}()
+
+		cfg := newConfig(&fakeSMTP{listener: listener})
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		err = dispatcher.OnAlert(ctx, &processor.AlertEvent{Type: processor.AlertEventFiring, Alert: newNotifiedAlert("high")})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(started), 900*time.Millisecond, "no retry after the deadline")
+	})
+
+	t.Run("should fail when STARTTLS is required but not offered", func(t *testing.T) {
+		server := newFakeSMTP(t)
+		cfg := newConfig(server)
+		cfg.TLS = "starttls"
+		dispatcher, err := notifier.NewEmailDispatcher(cfg)
+		require.NoError(t, err)
+
+		err = dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Type: processor.AlertEventFiring, Alert: newNotifiedAlert("low")})
+		assert.ErrorContains(t, err, "STARTTLS")
+		assert.Empty(t, server.received())
+	})
+
+	t.Run("should reject unknown TLS modes and invalid template files", func(t *testing.T) {
+		_, err := notifier.NewEmailDispatcher(config.EmailConfig{TLS: "ssl3"})
+		assert.Error(t, err)
+
+		templateFile := filepath.Join(t.TempDir(), "email.tmpl")
+		require.NoError(t, os.WriteFile(templateFile, []byte(`{{define "email.subject"}}{{.Title}`), 0o600))
+		_, err = notifier.NewEmailDispatcher(config.EmailConfig{TemplateFile: templateFile})
+		assert.Error(t, err)
+	})
+}
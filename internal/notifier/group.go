@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// AlertGroup is one notification about the alerts of a route that share the route's
+// group_by label values
+type AlertGroup struct {
+	Key      string            // identifies the group across notifications
+	Receiver string            // receiver the group is delivered to
+	Labels   map[string]string // the group_by label values
+	Firing   []*models.Alert   // every alert of the group still firing
+	New      []*models.Alert   // firing alerts not notified before, a subset of Firing
+	Resolved []*models.Alert   // alerts resolved since the last notification
+}
+
+// IsRepeat reports whether the group is resent unchanged after its repeat interval
+func (g *AlertGroup) IsRepeat() bool {
+	return len(g.New) == 0 && len(g.Resolved) == 0
+}
+
+// IsResolved reports whether every alert of the group has resolved
+func (g *AlertGroup) IsResolved() bool {
+	return len(g.Firing) == 0
+}
+
+// events returns the group as individual alert events: the new firing alerts, or all firing
+// alerts when the group is repeated, followed by the resolved ones
+func (g *AlertGroup) events(now time.Time) []*processor.AlertEvent {
+	firing := g.New
+	if g.IsRepeat() {
+		firing = g.Firing
+	}
+
+	events := make([]*processor.AlertEvent, 0, len(firing)+len(g.Resolved))
+	for _, alert := range firing {
+		events = append(events, &processor.AlertEvent{Type: processor.AlertEventFiring, Alert: alert, Timestamp: now})
+	}
+	for _, alert := range g.Resolved {
+		events = append(events, &processor.AlertEvent{Type: processor.AlertEventResolved, Alert: alert, Timestamp: now})
+	}
+	return events
+}
+
+// GroupNotifier is implemented by notifiers that send a whole alert group as one notification.
+// Other notifiers receive each new, resolved or repeated alert of the group as its own event.
+type GroupNotifier interface {
+	NotifyGroup(ctx context.Context, group *AlertGroup) error
+}
+
+// severityRanks orders alerts in group notifications, most severe first
+var severityRanks = map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+
+// sortAlerts orders alerts by severity, then by trigger time
+func sortAlerts(alerts []*models.Alert) {
+	sort.SliceStable(alerts, func(i, j int) bool {
+		ri, rj := severityRank(alerts[i].Severity), severityRank(alerts[j].Severity)
+		if ri != rj {
+			return ri < rj
+		}
+		return alerts[i].TriggeredAt.Before(alerts[j].TriggeredAt)
+	})
+}
+
+func severityRank(severity string) int {
+	if rank, ok := severityRanks[severity]; ok {
+		return rank
+	}
+	return len(severityRanks)
+}
+
+// highestSeverity returns the most severe severity among the firing alerts, or of the
+// resolved ones if none is firing
+func (g *AlertGroup) highestSeverity() string {
+	alerts := g.Firing
+	if len(alerts) == 0 {
+		alerts = g.Resolved
+	}
+	highest := ""
+	for _, alert := range alerts {
+		if highest == "" || severityRank(alert.Severity) < severityRank(highest) {
+			highest = alert.Severity
+		}
+	}
+	return highest
+}
+
+// title summarises the group, e.g. "[FIRING:3] node=worker-1" or "[RESOLVED] node=worker-1"
+func (g *AlertGroup) title() string {
+	keys := make([]string, 0, len(g.Labels))
+	for key := range g.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+g.Labels[key])
+	}
+	subject := strings.Join(pairs, " ")
+	if subject == "" && len(g.Firing)+len(g.Resolved) == 1 {
+		subject = append(g.Firing, g.Resolved...)[0].GetLabelsMap()["alert_type"]
+	}
+
+	if g.IsResolved() {
+		return fmt.Sprintf("[RESOLVED] %s", subject)
+	}
+	return fmt.Sprintf("[FIRING:%d] %s", len(g.Firing), subject)
+}
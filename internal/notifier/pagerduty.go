@@ -0,0 +1,233 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+const defaultPagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty event actions
+const (
+	PagerDutyTrigger     = "trigger"
+	PagerDutyAcknowledge = "acknowledge"
+	PagerDutyResolve     = "resolve"
+)
+
+// pagerDutySeverities maps alert severities to the PagerDuty severities critical, error, warning and info
+var pagerDutySeverities = map[string]string{
+	"critical": "critical",
+	"high":     "error",
+	"medium":   "warning",
+	"low":      "info",
+}
+
+// PagerDutyDispatcher pages through the PagerDuty Events API v2. Every alert is one PagerDuty
+// alert keyed by its fingerprint, so repeated triggers deduplicate and resolutions close it.
+type PagerDutyDispatcher struct {
+	config     config.PagerDutyConfig
+	severities map[string]bool
+	httpClient *http.Client
+}
+
+// NewPagerDutyDispatcher creates a new PagerDuty dispatcher
+func NewPagerDutyDispatcher(cfg config.PagerDutyConfig) *PagerDutyDispatcher {
+	if cfg.EventsURL == "" {
+		cfg.EventsURL = defaultPagerDutyEventsURL
+	}
+	if len(cfg.Severities) == 0 {
+		cfg.Severities = []string{"critical"}
+	}
+	return &PagerDutyDispatcher{
+		config:     cfg,
+		severities: toSet(cfg.Severities),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// pagerDutyEvent is an Events API v2 request
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"` // trigger only
+	Client      string            `json:"client,omitempty"`
+	ClientURL   string            `json:"client_url,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	Component     string                 `json:"component,omitempty"`
+	Group         string                 `json:"group,omitempty"`
+	Class         string                 `json:"class,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// Accepts reports whether the event is sent to PagerDuty: events of paging severities,
+// except unacknowledgements
+func (pd *PagerDutyDispatcher) Accepts(event *processor.AlertEvent) bool {
+	// PagerDuty cannot reopen an acknowledged alert; the next trigger escalates it again
+	return pd.severities[event.Alert.Severity] && event.Type != processor.AlertEventUnacknowledged
+}
+
+// OnAlert implements AlertObserver interface
+func (pd *PagerDutyDispatcher) OnAlert(ctx context.Context, event *processor.AlertEvent) error {
+	if pd.config.RoutingKey == "" {
+		logger.Warn().Msg("PagerDuty routing key missing, skipping PagerDuty dispatch")
+		return nil
+	}
+	if !pd.Accepts(event) {
+		return nil
+	}
+
+	var request pagerDutyEvent
+	switch event.Type {
+	case processor.AlertEventResolved:
+		request = pd.buildEvent(event.Alert, PagerDutyResolve)
+	case processor.AlertEventAcknowledged:
+		request = pd.buildEvent(event.Alert, PagerDutyAcknowledge)
+	default:
+		request = pd.buildEvent(event.Alert, PagerDutyTrigger)
+	}
+
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("pagerduty dispatch cancelled: %w", ctx.Err())
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+
+		var retry bool
+		retry, err = pd.send(ctx, request)
+		if err == nil {
+			logger.Info().
+				Str("action", request.EventAction).
+				Str("dedup_key", request.DedupKey).
+				Str("severity", event.Alert.Severity).
+				Msg("Alert sent to PagerDuty")
+			return nil
+		}
+		if !retry {
+			break
+		}
+	}
+	return fmt.Errorf("pagerduty dispatch failed: %w", err)
+}
+
+// DedupKey returns the PagerDuty dedup key of an alert, derived from its fingerprint so every
+// occurrence of the same condition updates the same PagerDuty alert
+func DedupKey(alert *models.Alert) string {
+	fingerprint := alert.Fingerprint
+	if fingerprint == "" {
+		fingerprint = models.ComputeFingerprint(alert.Source, alert.GetLabelsMap())
+	}
+	return "monitoring-tool/" + fingerprint
+}
+
+// buildEvent builds an Events API request; only triggers carry a payload
+func (pd *PagerDutyDispatcher) buildEvent(alert *models.Alert, action string) pagerDutyEvent {
+	request := pagerDutyEvent{
+		RoutingKey:  pd.config.RoutingKey,
+		EventAction: action,
+		DedupKey:    DedupKey(alert),
+	}
+	if action != PagerDutyTrigger {
+		return request
+	}
+
+	labels := alert.GetLabelsMap()
+	details := make(map[string]interface{}, len(labels)+3)
+	for key, value := range labels {
+		details[key] = value
+	}
+	details["value"] = alert.Value
+	details["alert_id"] = alert.ID.String()
+	details["occurrences"] = alert.OccurrenceCount
+
+	source := alert.Source
+	component := ""
+	switch {
+	case labels["pod"] != "":
+		source = labels["namespace"] + "/" + labels["pod"]
+		component = labels["container"]
+	case labels["node"] != "":
+		source = labels["node"]
+	}
+
+	summary := alert.Message
+	if len(summary) > 1024 {
+		summary = summary[:1021] + "..."
+	}
+
+	severity, ok := pagerDutySeverities[alert.Severity]
+	if !ok {
+		severity = "error"
+	}
+
+	request.Payload = &pagerDutyPayload{
+		Summary:       summary,
+		Source:        source,
+		Severity:      severity,
+		Timestamp:     alert.TriggeredAt.UTC().Format(time.RFC3339),
+		Component:     component,
+		Group:         labels["namespace"],
+		Class:         labels["alert_type"],
+		CustomDetails: details,
+	}
+	request.Client = "monitoring-tool"
+	if pd.config.DashboardURL != "" {
+		request.ClientURL = pd.config.DashboardURL
+		request.Links = []pagerDutyLink{{Href: pd.config.DashboardURL, Text: "View in dashboard"}}
+	}
+	return request
+}
+
+// send posts one event, reporting whether a failure is worth retrying
+func (pd *PagerDutyDispatcher) send(ctx context.Context, request pagerDutyEvent) (bool, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pd.config.EventsURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := pd.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return false, nil
+}
@@ -0,0 +1,162 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/notifier"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagerDutyMock accepts Events API v2 requests
+type pagerDutyMock struct {
+	mu     sync.Mutex
+	events []map[string]interface{}
+	status int
+}
+
+func (m *pagerDutyMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var event map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&event)
+
+	m.mu.Lock()
+	m.events = append(m.events, event)
+	status := m.status
+	m.mu.Unlock()
+
+	if status == 0 {
+		status = http.StatusAccepted
+	}
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "success", "dedup_key": event["dedup_key"].(string)})
+}
+
+func (m *pagerDutyMock) received() []map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]map[string]interface{}(nil), m.events...)
+}
+
+func TestPagerDutyDispatcher_OnAlert(t *testing.T) {
+	t.Run("should trigger with labels as custom details and resolve with the same dedup key", func(t *testing.T) {
+		mock := &pagerDutyMock{}
+		server := httptest.NewServer(mock)
+		defer server.Close()
+
+		dispatcher := notifier.NewPagerDutyDispatcher(config.PagerDutyConfig{
+			RoutingKey:   "routing-key",
+			EventsURL:    server.URL,
+			DashboardURL: "http://monitoring.example.com",
+		})
+
+		alert := newNotifiedAlert("critical")
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: alert, Type: processor.AlertEventFiring}))
+
+		resolvedAt := time.Now()
+		alert.ResolvedAt = &resolvedAt
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: alert, Type: processor.AlertEventResolved}))
+
+		events := mock.received()
+		require.Len(t, events, 2)
+
+		trigger := events[0]
+		assert.Equal(t, "routing-key", trigger["routing_key"])
+		assert.Equal(t, "trigger", trigger["event_action"])
+		assert.Equal(t, notifier.DedupKey(alert), trigger["dedup_key"])
+
+		payload := trigger["payload"].(map[string]interface{})
+		assert.Equal(t, "critical", payload["severity"])
+		assert.Equal(t, "default/api-0", payload["source"])
+		assert.Equal(t, "pod_crash_loop", payload["class"])
+		assert.Equal(t, alert.Message, payload["summary"])
+		details := payload["custom_details"].(map[string]interface{})
+		assert.Equal(t, "api-0", details["pod"])
+		assert.Equal(t, "worker-1", details["node"])
+
+		resolve := events[1]
+		assert.Equal(t, "resolve", resolve["event_action"])
+		assert.Equal(t, trigger["dedup_key"], resolve["dedup_key"])
+		assert.Nil(t, resolve["payload"])
+	})
+
+	t.Run("should derive the same dedup key for repeated occurrences of a condition", func(t *testing.T) {
+		first := newNotifiedAlert("critical")
+		second := newNotifiedAlert("critical")
+
+		assert.NotEqual(t, first.ID, second.ID)
+		assert.Equal(t, notifier.DedupKey(first), notifier.DedupKey(second))
+	})
+
+	t.Run("should map severities", func(t *testing.T) {
+		mock := &pagerDutyMock{}
+		server := httptest.NewServer(mock)
+		defer server.Close()
+
+		dispatcher := notifier.NewPagerDutyDispatcher(config.PagerDutyConfig{
+			RoutingKey: "routing-key",
+			EventsURL:  server.URL,
+			Severities: []string{"critical", "high", "medium", "low"},
+		})
+
+		for _, severity := range []string{"critical", "high", "medium", "low"} {
+			require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert(severity), Type: processor.AlertEventFiring}))
+		}
+
+		var mapped []string
+		for _, event := range mock.received() {
+			mapped = append(mapped, event["payload"].(map[string]interface{})["severity"].(string))
+		}
+		assert.Equal(t, []string{"critical", "error", "warning", "info"}, mapped)
+	})
+
+	t.Run("should only page configured severities", func(t *testing.T) {
+		mock := &pagerDutyMock{}
+		server := httptest.NewServer(mock)
+		defer server.Close()
+
+		dispatcher := notifier.NewPagerDutyDispatcher(config.PagerDutyConfig{RoutingKey: "routing-key", EventsURL: server.URL})
+
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("high"), Type: processor.AlertEventFiring}))
+		assert.Empty(t, mock.received())
+	})
+
+	t.Run("should acknowledge acknowledged alerts", func(t *testing.T) {
+		mock := &pagerDutyMock{}
+		server := httptest.NewServer(mock)
+		defer server.Close()
+
+		dispatcher := notifier.NewPagerDutyDispatcher(config.PagerDutyConfig{RoutingKey: "routing-key", EventsURL: server.URL})
+
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("critical"), Type: processor.AlertEventAcknowledged}))
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("critical"), Type: processor.AlertEventUnacknowledged}))
+
+		events := mock.received()
+		require.Len(t, events, 1)
+		assert.Equal(t, "acknowledge", events[0]["event_action"])
+	})
+
+	t.Run("should return an error for rejected events without retrying", func(t *testing.T) {
+		mock := &pagerDutyMock{status: http.StatusBadRequest}
+		server := httptest.NewServer(mock)
+		defer server.Close()
+
+		dispatcher := notifier.NewPagerDutyDispatcher(config.PagerDutyConfig{RoutingKey: "routing-key", EventsURL: server.URL})
+
+		err := dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("critical"), Type: processor.AlertEventFiring})
+		assert.ErrorContains(t, err, "unexpected status 400")
+		assert.Len(t, mock.received(), 1)
+	})
+
+	t.Run("should skip without routing key", func(t *testing.T) {
+		dispatcher := notifier.NewPagerDutyDispatcher(config.PagerDutyConfig{})
+		assert.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("critical"), Type: processor.AlertEventFiring}))
+	})
+}
@@ -0,0 +1,110 @@
+package notifier
+
+import (
+	"fmt"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// Receiver is a named set of notifiers that routed alerts are delivered to
+type Receiver struct {
+	Name      string
+	Notifiers []ReceiverNotifier
+}
+
+// ReceiverNotifier is a notifier of a receiver. The outbox queues a notification per notifier,
+// recorded under its channel and target.
+type ReceiverNotifier struct {
+	Channel  string
+	Name     string // tells notifiers of the same channel apart, e.g. webhook endpoints
+	Notifier processor.AlertObserver
+}
+
+// target returns the receiver notifications of the notifier are recorded under: the receiver's
+// name, followed by the notifier's name if it has one, e.g. "ops/statuspage"
+func (n ReceiverNotifier) target(receiver string) string {
+	if n.Name == "" {
+		return receiver
+	}
+	return receiver + "/" + n.Name
+}
+
+// BuildReceivers creates the notifiers of every configured receiver. Each receiver only names
+// its recipients; SMTP, Slack and PagerDuty connection settings come from the top-level sections.
+func BuildReceivers(cfg *config.Config) (map[string]*Receiver, error) {
+	receivers := make(map[string]*Receiver, len(cfg.Routing.Receivers))
+
+	for _, receiverCfg := range cfg.Routing.Receivers {
+		if receiverCfg.Name == "" {
+			return nil, fmt.Errorf("receiver name is required")
+		}
+		if _, exists := receivers[receiverCfg.Name]; exists {
+			return nil, fmt.Errorf("receiver %q is defined twice", receiverCfg.Name)
+		}
+		receiver := &Receiver{Name: receiverCfg.Name}
+		add := func(notifier processor.AlertObserver, channel string) {
+			receiver.Notifiers = append(receiver.Notifiers, ReceiverNotifier{Channel: channel, Notifier: notifier})
+		}
+
+		if receiverCfg.Email != nil {
+			if cfg.Email.SMTPHost == "" || cfg.Email.Username == "" {
+				return nil, fmt.Errorf("receiver %q: email needs the SMTP settings of the email section", receiverCfg.Name)
+			}
+			if len(receiverCfg.Email.To) == 0 {
+				return nil, fmt.Errorf("receiver %q: email needs at least one recipient", receiverCfg.Name)
+			}
+			emailCfg := cfg.Email
+			emailCfg.To = receiverCfg.Email.To
+			emailDispatcher, err := NewEmailDispatcher(emailCfg)
+			if err != nil {
+				return nil, fmt.Errorf("receiver %q: %w", receiverCfg.Name, err)
+			}
+			add(emailDispatcher, models.ChannelTypeEmail)
+		}
+
+		if receiverCfg.Slack != nil {
+			slackCfg := cfg.Slack
+			if receiverCfg.Slack.Channel != "" {
+				slackCfg.Channel = receiverCfg.Slack.Channel
+				slackCfg.SeverityChannels = nil
+			}
+			if receiverCfg.Slack.WebhookURL != "" {
+				slackCfg.WebhookURL = receiverCfg.Slack.WebhookURL
+				slackCfg.SeverityWebhooks = nil
+			}
+			if slackCfg.WebhookURL == "" && slackCfg.BotToken == "" {
+				return nil, fmt.Errorf("receiver %q: slack needs a webhook_url or the bot token of the slack section", receiverCfg.Name)
+			}
+			add(NewSlackDispatcher(slackCfg), models.ChannelTypeSlack)
+		}
+
+		if receiverCfg.PagerDuty != nil {
+			pagerDutyCfg := cfg.PagerDuty
+			if receiverCfg.PagerDuty.RoutingKey != "" {
+				pagerDutyCfg.RoutingKey = receiverCfg.PagerDuty.RoutingKey
+			}
+			if len(receiverCfg.PagerDuty.Severities) > 0 {
+				pagerDutyCfg.Severities = receiverCfg.PagerDuty.Severities
+			}
+			if pagerDutyCfg.RoutingKey == "" {
+				return nil, fmt.Errorf("receiver %q: pagerduty needs a routing_key", receiverCfg.Name)
+			}
+			add(NewPagerDutyDispatcher(pagerDutyCfg), models.ChannelTypePagerDuty)
+		}
+
+		if len(receiverCfg.Webhooks) > 0 {
+			webhookDispatcher, err := NewWebhookDispatcher(config.WebhooksConfig{Enabled: true, Endpoints: receiverCfg.Webhooks})
+			if err != nil {
+				return nil, fmt.Errorf("receiver %q: %w", receiverCfg.Name, err)
+			}
+			for _, endpoint := range webhookDispatcher.Endpoints() {
+				receiver.Notifiers = append(receiver.Notifiers, ReceiverNotifier{Channel: models.ChannelTypeWebhook, Name: endpoint.Name(), Notifier: endpoint})
+			}
+		}
+
+		receivers[receiver.Name] = receiver
+	}
+	return receivers, nil
+}
@@ -0,0 +1,489 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// route is a node of the routing tree with inherited settings resolved
+type route struct {
+	id             string // position in the tree, e.g. "root.1.0"
+	receiver       string
+	matchers       []models.Matcher
+	continues      bool
+	groupBy        []string
+	groupWait      time.Duration
+	groupInterval  time.Duration
+	repeatInterval time.Duration
+	routes         []*route
+}
+
+// matches reports whether the labels satisfy every matcher of the route
+func (r *route) matches(labels map[string]string) bool {
+	for _, matcher := range r.matchers {
+		if !matcher.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// match returns the routes that handle an alert with the labels: the deepest matching routes
+// below r, or r itself if no child matches
+func (r *route) match(labels map[string]string) []*route {
+	var matched []*route
+	for _, child := range r.routes {
+		if !child.matches(labels) {
+			continue
+		}
+		matched = append(matched, child.match(labels)...)
+		if !child.continues {
+			break
+		}
+	}
+	if len(matched) == 0 {
+		return []*route{r}
+	}
+	return matched
+}
+
+// alertGroup is the notification state of the alerts of one route sharing its group_by labels.
+// The notifications themselves are queued in the outbox; the group only decides when they are due.
+type alertGroup struct {
+	key        string
+	route      *route
+	firing     map[string]*groupedAlert // by fingerprint
+	changed    bool                     // alerts joined or resolved since the last notification
+	flushAt    time.Time                // when the pending change notification is due
+	notifiedAt time.Time                // last notification, zero before the first
+}
+
+// groupedAlert is a firing alert of a group
+type groupedAlert struct {
+	alert        models.Alert // latest copy, so later changes by the state manager are not raced
+	notified     bool
+	acknowledged bool
+}
+
+// repeatable reports whether the group has unacknowledged firing alerts to repeat
+func (g *alertGroup) repeatable() bool {
+	for _, grouped := range g.firing {
+		if !grouped.acknowledged {
+			return true
+		}
+	}
+	return false
+}
+
+// markChanged schedules a notification about a change: after group_wait for a new group,
+// otherwise group_interval after the previous notification, but not before now
+func (g *alertGroup) markChanged(now time.Time) {
+	if g.changed {
+		return
+	}
+	g.changed = true
+	g.flushAt = now.Add(g.route.groupWait)
+	if !g.notifiedAt.IsZero() {
+		g.flushAt = latest(now, g.notifiedAt.Add(g.route.groupInterval))
+	}
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// flush marks the group notified once its pending change notification is due
+func (g *alertGroup) flush(now time.Time) {
+	if !g.changed || now.Before(g.flushAt) {
+		return
+	}
+	for _, grouped := range g.firing {
+		grouped.notified = true
+	}
+	g.changed = false
+	g.notifiedAt = g.flushAt
+	g.flushAt = time.Time{}
+}
+
+// Router routes alert events through the routing tree to receivers. The outbox queues a
+// notification per route the router returns, so every notifier of a receiver is retried and
+// dead-lettered on its own, and delivers notifications of one group that are due together as one
+// batch. Alerts of a route are grouped by its group_by labels: a group is first notified after
+// group_wait, changes to it after group_interval, and it is repeated every repeat_interval while
+// it has unacknowledged firing alerts. Alerts that resolve before they were notified are skipped.
+// Group membership is kept in memory, so after a restart groups start over with the queued
+// notifications.
+type Router struct {
+	root      *route
+	routes    map[string]*route // by id
+	receivers map[string]*Receiver
+	now       func() time.Time
+
+	mu     sync.Mutex
+	groups map[string]*alertGroup // by key
+}
+
+// NewRouter builds the routing tree, failing on unknown receivers and invalid matchers
+func NewRouter(cfg config.RoutingConfig, receivers map[string]*Receiver) (*Router, error) {
+	if cfg.Route.Receiver == "" {
+		return nil, fmt.Errorf("routing: the root route needs a receiver")
+	}
+	root, err := buildRoute(cfg.Route, nil, "root", receivers)
+	if err != nil {
+		return nil, err
+	}
+
+	router := &Router{
+		root:      root,
+		routes:    make(map[string]*route),
+		receivers: receivers,
+		now:       time.Now,
+		groups:    make(map[string]*alertGroup),
+	}
+	router.index(root)
+	return router, nil
+}
+
+// index records the route and its children by id
+func (r *Router) index(rt *route) {
+	r.routes[rt.id] = rt
+	for _, child := range rt.routes {
+		r.index(child)
+	}
+}
+
+// buildRoute resolves a route config against its parent
+func buildRoute(cfg config.RouteConfig, parent *route, id string, receivers map[string]*Receiver) (*route, error) {
+	r := &route{
+		id:             id,
+		receiver:       cfg.Receiver,
+		continues:      cfg.Continue,
+		groupBy:        cfg.GroupBy,
+		groupWait:      cfg.GroupWait,
+		groupInterval:  cfg.GroupInterval,
+		repeatInterval: cfg.RepeatInterval,
+	}
+	if parent != nil {
+		if r.receiver == "" {
+			r.receiver = parent.receiver
+		}
+		if r.groupBy == nil {
+			r.groupBy = parent.groupBy
+		}
+		if r.groupWait == 0 {
+			r.groupWait = parent.groupWait
+		}
+		if r.groupInterval == 0 {
+			r.groupInterval = parent.groupInterval
+		}
+		if r.repeatInterval == 0 {
+			r.repeatInterval = parent.repeatInterval
+		}
+	}
+	if _, ok := receivers[r.receiver]; !ok {
+		return nil, fmt.Errorf("routing: route %s refers to unknown receiver %q", id, r.receiver)
+	}
+
+	for _, expr := range cfg.Matchers {
+		matcher, err := models.ParseMatcher(expr)
+		if err != nil {
+			return nil, fmt.Errorf("routing: route %s: %w", id, err)
+		}
+		r.matchers = append(r.matchers, matcher)
+	}
+
+	for i, childCfg := range cfg.Routes {
+		child, err := buildRoute(childCfg, r, id+"."+strconv.Itoa(i), receivers)
+		if err != nil {
+			return nil, err
+		}
+		r.routes = append(r.routes, child)
+	}
+	return r, nil
+}
+
+// groupKey identifies the group of an alert on a route. Without group_by every alert is its own group.
+func (r *route) groupKey(labels map[string]string, fingerprint string) (string, map[string]string) {
+	if len(r.groupBy) == 0 {
+		return r.id + "|" + fingerprint, map[string]string{}
+	}
+
+	groupLabels := make(map[string]string, len(r.groupBy))
+	var b strings.Builder
+	b.WriteString(r.id)
+	for _, name := range r.groupBy {
+		groupLabels[name] = labels[name]
+		b.WriteString("|")
+		b.WriteString(name)
+		b.WriteString("=")
+		b.WriteString(labels[name])
+	}
+	return b.String(), groupLabels
+}
+
+// Route implements processor.NotificationRouter. It returns a route per notifier of every
+// receiver the event is routed to. New and resolved alerts of a group are due when the group's
+// next notification is; acknowledgements, and recoveries of alerts the router does not track,
+// are due immediately.
+func (r *Router) Route(event *processor.AlertEvent) []processor.Route {
+	alert := *event.Alert
+	alert.EnsureFingerprint()
+	labels := alert.MatchLabels()
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var routes []processor.Route
+	for _, rt := range r.root.match(labels) {
+		key, _ := rt.groupKey(labels, alert.Fingerprint)
+		group := r.groups[key]
+		var grouped *groupedAlert
+		if group != nil {
+			group.flush(now)
+			grouped = group.firing[alert.Fingerprint]
+		}
+
+		switch event.Type {
+		case processor.AlertEventResolved:
+			if grouped == nil {
+				// Not tracked, e.g. it fired before a restart: announce the recovery on its own
+				routes = append(routes, r.routesOf(rt, event, "", time.Time{})...)
+				continue
+			}
+			delete(group.firing, alert.Fingerprint)
+			// A recovery due together with the alert's firing notification cancels it out
+			group.markChanged(now)
+			routes = append(routes, r.routesOf(rt, event, key, group.flushAt)...)
+
+		case processor.AlertEventAcknowledged, processor.AlertEventUnacknowledged:
+			if grouped != nil {
+				grouped.alert = alert
+				grouped.acknowledged = event.Type == processor.AlertEventAcknowledged
+				if !grouped.notified {
+					continue
+				}
+			}
+			routes = append(routes, r.routesOf(rt, event, "", time.Time{})...)
+
+		default:
+			if group == nil {
+				group = &alertGroup{key: key, route: rt, firing: make(map[string]*groupedAlert)}
+				r.groups[key] = group
+			}
+			if grouped != nil {
+				grouped.alert = alert
+				continue
+			}
+			group.firing[alert.Fingerprint] = &groupedAlert{alert: alert}
+			group.markChanged(now)
+			routes = append(routes, r.routesOf(rt, event, key, group.flushAt)...)
+		}
+	}
+	return routes
+}
+
+// Repeats implements processor.NotificationRouter. It returns a repeated event per firing alert
+// of every group whose repeat interval has passed without a change and that still has
+// unacknowledged alerts.
+func (r *Router) Repeats(now time.Time) []processor.RoutedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var repeats []processor.RoutedEvent
+	for key, group := range r.groups {
+		group.flush(now)
+		if len(group.firing) == 0 && !group.changed {
+			delete(r.groups, key)
+			continue
+		}
+
+		repeatInterval := group.route.repeatInterval
+		if group.changed || group.notifiedAt.IsZero() || repeatInterval <= 0 || !group.repeatable() ||
+			now.Before(group.notifiedAt.Add(repeatInterval)) {
+			continue
+		}
+		group.notifiedAt = now
+		for _, grouped := range group.firing {
+			alert := grouped.alert
+			event := &processor.AlertEvent{Type: processor.AlertEventRepeated, Alert: &alert, Timestamp: now}
+			for _, route := range r.routesOf(group.route, event, key, now) {
+				repeats = append(repeats, processor.RoutedEvent{Route: route, Event: event})
+			}
+		}
+	}
+	return repeats
+}
+
+// routesOf returns a route of the event for each notifier of the route's receiver that accepts it.
+// Notifiers that batch, e.g. email digests, get the event in their batch instead of the group.
+func (r *Router) routesOf(rt *route, event *processor.AlertEvent, key string, dueAt time.Time) []processor.Route {
+	receiver := r.receivers[rt.receiver]
+	accepted := delivered(event)
+
+	var routes []processor.Route
+	for _, target := range receiver.Notifiers {
+		if filter, ok := target.Notifier.(processor.EventFilter); ok && !filter.Accepts(accepted) {
+			continue
+		}
+		route := processor.Route{Channel: target.Channel, Receiver: target.target(receiver.Name), GroupKey: key, DueAt: dueAt}
+		if batcher, ok := target.Notifier.(processor.Batcher); ok {
+			if batchKey, batchDueAt := batcher.Batch(accepted, r.now()); batchKey != "" {
+				route.GroupKey, route.DueAt = batchKey, batchDueAt
+			}
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// delivered returns the event as notifiers receive it: repeats are sent as firing events
+func delivered(event *processor.AlertEvent) *processor.AlertEvent {
+	if event.Type != processor.AlertEventRepeated {
+		return event
+	}
+	firing := *event
+	firing.Type = processor.AlertEventFiring
+	return &firing
+}
+
+// Deliver implements processor.NotificationRouter. Batching notifiers get all events of their
+// batch; other events without a group key are sent as they are, and the events of a group are
+// sent as one group notification to notifiers that support it, otherwise as an event per new,
+// resolved or repeated alert.
+func (r *Router) Deliver(ctx context.Context, route processor.Route, events []*processor.AlertEvent) error {
+	receiver, notifier, ok := r.notifier(route.Channel, route.Receiver)
+	if !ok {
+		return processor.ErrNoTarget
+	}
+
+	if batcher, ok := notifier.(processor.Batcher); ok && len(events) > 0 {
+		if batchKey, _ := batcher.Batch(events[0], r.now()); batchKey != "" {
+			batch := make([]*processor.AlertEvent, 0, len(events))
+			for _, event := range events {
+				batch = append(batch, delivered(event))
+			}
+			return batcher.NotifyBatch(ctx, batch)
+		}
+	}
+
+	if route.GroupKey == "" {
+		var errs []error
+		for _, event := range events {
+			if err := notifier.OnAlert(ctx, delivered(event)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	group := r.group(receiver, route, events)
+	if group == nil {
+		return processor.ErrNotificationSkipped
+	}
+	if groupNotifier, ok := notifier.(GroupNotifier); ok && len(group.Labels) > 0 {
+		return groupNotifier.NotifyGroup(ctx, group)
+	}
+	return r.notifyEach(ctx, notifier, group)
+}
+
+// notifier returns the receiver and notifier notifications on the channel and target are delivered to
+func (r *Router) notifier(channel, targetName string) (string, processor.AlertObserver, bool) {
+	for _, receiver := range r.receivers {
+		for _, target := range receiver.Notifiers {
+			if target.Channel == channel && target.target(receiver.Name) == targetName {
+				return receiver.Name, target.Notifier, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// group builds the notification of a group from the events due together and the group's
+// notified alerts. Alerts that fire and resolve within the batch were never announced and are
+// left out; it returns nil if nothing is left to send.
+func (r *Router) group(receiver string, route processor.Route, events []*processor.AlertEvent) *AlertGroup {
+	added := make(map[string]*models.Alert)
+	repeated := make(map[string]*models.Alert)
+	resolved := make(map[string]*models.Alert)
+	for _, event := range events {
+		alert := *event.Alert
+		alert.EnsureFingerprint()
+		switch event.Type {
+		case processor.AlertEventResolved:
+			if _, ok := added[alert.Fingerprint]; ok {
+				delete(added, alert.Fingerprint)
+				continue
+			}
+			resolved[alert.Fingerprint] = &alert
+		case processor.AlertEventRepeated:
+			repeated[alert.Fingerprint] = &alert
+		default:
+			delete(resolved, alert.Fingerprint)
+			added[alert.Fingerprint] = &alert
+		}
+	}
+	if len(added) == 0 && len(resolved) == 0 && len(repeated) == 0 {
+		return nil
+	}
+
+	group := &AlertGroup{Key: route.GroupKey, Receiver: receiver, Labels: map[string]string{}}
+	firing := make(map[string]*models.Alert)
+	r.mu.Lock()
+	if tracked := r.groups[route.GroupKey]; tracked != nil {
+		for fingerprint, grouped := range tracked.firing {
+			if grouped.notified {
+				alert := grouped.alert
+				firing[fingerprint] = &alert
+			}
+		}
+	}
+	r.mu.Unlock()
+	for fingerprint, alert := range repeated {
+		firing[fingerprint] = alert
+	}
+	for fingerprint, alert := range added {
+		firing[fingerprint] = alert
+		group.New = append(group.New, alert)
+	}
+	for fingerprint, alert := range resolved {
+		delete(firing, fingerprint)
+		group.Resolved = append(group.Resolved, alert)
+	}
+	for _, alert := range firing {
+		group.Firing = append(group.Firing, alert)
+	}
+	sortAlerts(group.Firing)
+	sortAlerts(group.New)
+	sortAlerts(group.Resolved)
+
+	// The route's group_by labels, unless the routing tree changed since the events were queued
+	routeID, _, _ := strings.Cut(route.GroupKey, "|")
+	if rt, ok := r.routes[routeID]; ok && len(events) > 0 {
+		_, group.Labels = rt.groupKey(events[0].Alert.MatchLabels(), "")
+	}
+	return group
+}
+
+// notifyEach sends the alerts of a group as individual events
+func (r *Router) notifyEach(ctx context.Context, notifier processor.AlertObserver, group *AlertGroup) error {
+	var errs []error
+	for _, event := range group.events(r.now()) {
+		if err := notifier.OnAlert(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
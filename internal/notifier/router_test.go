@@ -0,0 +1,444 @@
+package notifier_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/notifier"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier records the events delivered to a receiver, failing the first failures deliveries
+type recordingNotifier struct {
+	mu       sync.Mutex
+	events   []processor.AlertEvent
+	failures int
+}
+
+func (n *recordingNotifier) OnAlert(ctx context.Context, event *processor.AlertEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("receiver unavailable")
+	}
+	n.events = append(n.events, *event)
+	return nil
+}
+
+func (n *recordingNotifier) received() []processor.AlertEvent {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]processor.AlertEvent(nil), n.events...)
+}
+
+func (n *recordingNotifier) count() int {
+	return len(n.received())
+}
+
+// newReceiver creates a receiver delivering to the notifier over webhooks
+func newReceiver(name string, observer processor.AlertObserver) *notifier.Receiver {
+	return &notifier.Receiver{Name: name, Notifiers: []notifier.ReceiverNotifier{{Channel: models.ChannelTypeWebhook, Notifier: observer}}}
+}
+
+// newRecordingReceivers creates a receiver with a recording notifier for each name
+func newRecordingReceivers(names ...string) (map[string]*notifier.Receiver, map[string]*recordingNotifier) {
+	receivers := make(map[string]*notifier.Receiver, len(names))
+	recorders := make(map[string]*recordingNotifier, len(names))
+	for _, name := range names {
+		recorders[name] = &recordingNotifier{}
+		receivers[name] = newReceiver(name, recorders[name])
+	}
+	return receivers, recorders
+}
+
+func newRoutedAlert(severity string, labels map[string]string) *models.Alert {
+	return models.NewAlert(severity, "test alert", "k8s_pod", 1, labels)
+}
+
+// routerFixture routes the alert changes of a state manager through the outbox
+type routerFixture struct {
+	manager       *processor.AlertStateManager
+	notifications repository.NotificationRepo
+}
+
+func startRouter(t *testing.T, cfg config.RoutingConfig, receivers map[string]*notifier.Receiver) *routerFixture {
+	router, err := notifier.NewRouter(cfg, receivers)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	repo := repository.NewInMemoryAlertRepo()
+	notifications := repository.NewInMemoryNotificationRepo()
+	eventBus := processor.NewEventBus()
+	eventBus.Start(ctx)
+
+	outbox := processor.NewOutbox(repository.NewInMemoryTransactor(repo, notifications), notifications, repo, config.DeliveryConfig{
+		PollInterval:   5 * time.Millisecond,
+		Timeout:        time.Second,
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+	})
+	outbox.UseRouter(router)
+	manager := processor.NewAlertStateManager(repo, eventBus)
+	manager.UseOutbox(outbox)
+	outbox.Start(ctx)
+
+	t.Cleanup(func() {
+		outbox.Stop()
+		eventBus.Stop()
+		cancel()
+	})
+	return &routerFixture{manager: manager, notifications: notifications}
+}
+
+func (f *routerFixture) fire(t *testing.T, alerts ...*models.Alert) {
+	for _, alert := range alerts {
+		_, err := f.manager.ProcessAlert(context.Background(), alert)
+		require.NoError(t, err)
+	}
+}
+
+func (f *routerFixture) resolve(t *testing.T, alerts ...*models.Alert) {
+	for _, alert := range alerts {
+		_, err := f.manager.Resolve(context.Background(), alert.ID, "alice", "")
+		require.NoError(t, err)
+	}
+}
+
+func (f *routerFixture) acknowledge(t *testing.T, alert *models.Alert) {
+	_, err := f.manager.Acknowledge(context.Background(), alert.ID, "alice", "")
+	require.NoError(t, err)
+}
+
+// notificationsOf returns the notifications of an alert by receiver
+func (f *routerFixture) notificationsOf(t *testing.T, alert *models.Alert) map[string][]*models.Notification {
+	notifications, err := f.notifications.GetByAlert(context.Background(), alert.ID)
+	require.NoError(t, err)
+	byReceiver := make(map[string][]*models.Notification)
+	for _, notification := range notifications {
+		byReceiver[notification.Receiver] = append(byReceiver[notification.Receiver], notification)
+	}
+	return byReceiver
+}
+
+func TestParseMatcher(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected models.Matcher
+	}{
+		{"namespace=team-a", models.Matcher{Name: "namespace", Operator: models.MatchEqual, Value: "team-a"}},
+		{"severity != low", models.Matcher{Name: "severity", Operator: models.MatchNotEqual, Value: "low"}},
+		{`namespace=~"team-a-.*"`, models.Matcher{Name: "namespace", Operator: models.MatchRegexp, Value: "team-a-.*"}},
+		{"alert_type!~node_.*", models.Matcher{Name: "alert_type", Operator: models.MatchNotRegexp, Value: "node_.*"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			matcher, err := models.ParseMatcher(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, matcher)
+		})
+	}
+
+	t.Run("should reject invalid matchers", func(t *testing.T) {
+		for _, expr := range []string{"namespace", "=team-a", "namespace=~(", "namespace~team-a"} {
+			_, err := models.ParseMatcher(expr)
+			assert.Error(t, err, expr)
+		}
+	})
+}
+
+func TestNewRouter(t *testing.T) {
+	receivers, _ := newRecordingReceivers("default")
+
+	t.Run("should require a root receiver", func(t *testing.T) {
+		_, err := notifier.NewRouter(config.RoutingConfig{}, receivers)
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject unknown receivers", func(t *testing.T) {
+		_, err := notifier.NewRouter(config.RoutingConfig{Route: config.RouteConfig{
+			Receiver: "default",
+			Routes:   []config.RouteConfig{{Receiver: "missing", Matchers: []string{"namespace=team-a"}}},
+		}}, receivers)
+		assert.ErrorContains(t, err, `unknown receiver "missing"`)
+	})
+}
+
+func TestRouter_Routing(t *testing.T) {
+	cfg := config.RoutingConfig{Route: config.RouteConfig{
+		Receiver: "default",
+		Routes: []config.RouteConfig{
+			{Receiver: "pager", Matchers: []string{"severity=critical"}, Continue: true},
+			{Receiver: "team-a", Matchers: []string{"namespace=~team-a(-.*)?"}},
+			{Receiver: "team-b", Matchers: []string{"namespace=team-b"}},
+		},
+	}}
+
+	t.Run("should send team namespaces to their own receiver only", func(t *testing.T) {
+		receivers, recorders := newRecordingReceivers("default", "pager", "team-a", "team-b")
+		router := startRouter(t, cfg, receivers)
+
+		alert := newRoutedAlert("high", map[string]string{"alert_type": "pod_crash_loop", "namespace": "team-a-api", "pod": "api-0"})
+		router.fire(t, alert)
+
+		assert.Eventually(t, func() bool { return recorders["team-a"].count() == 1 }, time.Second, 5*time.Millisecond)
+		assert.Zero(t, recorders["default"].count())
+		assert.Zero(t, recorders["team-b"].count())
+		assert.Zero(t, recorders["pager"].count())
+	})
+
+	t.Run("should continue matching after a route with continue", func(t *testing.T) {
+		receivers, recorders := newRecordingReceivers("default", "pager", "team-a", "team-b")
+		router := startRouter(t, cfg, receivers)
+
+		alert := newRoutedAlert("critical", map[string]string{"alert_type": "pod_oom_killed", "namespace": "team-b", "pod": "db-0"})
+		router.fire(t, alert)
+
+		assert.Eventually(t, func() bool {
+			return recorders["pager"].count() == 1 && recorders["team-b"].count() == 1
+		}, time.Second, 5*time.Millisecond)
+		assert.Zero(t, recorders["default"].count())
+
+		// Each receiver has its own notification
+		queued := router.notificationsOf(t, alert)
+		assert.Len(t, queued, 2)
+		assert.Len(t, queued["pager"], 1)
+		assert.Len(t, queued["team-b"], 1)
+	})
+
+	t.Run("should retry a failing receiver without resending to the others", func(t *testing.T) {
+		receivers, recorders := newRecordingReceivers("default", "pager", "team-a", "team-b")
+		recorders["pager"].failures = 1
+		router := startRouter(t, cfg, receivers)
+
+		alert := newRoutedAlert("critical", map[string]string{"alert_type": "pod_oom_killed", "namespace": "team-b", "pod": "db-0"})
+		router.fire(t, alert)
+
+		assert.Eventually(t, func() bool {
+			queued := router.notificationsOf(t, alert)
+			return len(queued["pager"]) == 1 && queued["pager"][0].State == models.NotificationStateSuccess
+		}, time.Second, 5*time.Millisecond)
+
+		queued := router.notificationsOf(t, alert)
+		assert.Equal(t, 2, queued["pager"][0].Attempts)
+		assert.Equal(t, models.NotificationStateSuccess, queued["team-b"][0].State)
+		assert.Equal(t, 1, queued["team-b"][0].Attempts)
+		assert.Equal(t, 1, recorders["pager"].count())
+		assert.Equal(t, 1, recorders["team-b"].count())
+	})
+
+	t.Run("should fall back to the default route", func(t *testing.T) {
+		receivers, recorders := newRecordingReceivers("default", "pager", "team-a", "team-b")
+		router := startRouter(t, cfg, receivers)
+
+		alert := newRoutedAlert("high", map[string]string{"alert_type": "node_disk_pressure", "node": "worker-1"})
+		router.fire(t, alert)
+
+		assert.Eventually(t, func() bool { return recorders["default"].count() == 1 }, time.Second, 5*time.Millisecond)
+		assert.Zero(t, recorders["team-a"].count())
+	})
+}
+
+func TestRouter_Timing(t *testing.T) {
+	t.Run("should wait group_wait and drop alerts resolved before it", func(t *testing.T) {
+		receivers, recorders := newRecordingReceivers("default")
+		router := startRouter(t, config.RoutingConfig{Route: config.RouteConfig{Receiver: "default", GroupWait: 100 * time.Millisecond}}, receivers)
+
+		flapping := newRoutedAlert("high", map[string]string{"alert_type": "pod_crash_loop", "namespace": "default", "pod": "flap-0"})
+		steady := newRoutedAlert("high", map[string]string{"alert_type": "pod_crash_loop", "namespace": "default", "pod": "steady-0"})
+		router.fire(t, flapping, steady)
+		router.resolve(t, flapping)
+
+		time.Sleep(30 * time.Millisecond)
+		assert.Zero(t, recorders["default"].count())
+
+		assert.Eventually(t, func() bool { return recorders["default"].count() == 1 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, steady.ID, recorders["default"].received()[0].Alert.ID)
+
+		// The flapping alert's firing and resolved notifications were skipped together
+		assert.Eventually(t, func() bool {
+			queued := router.notificationsOf(t, flapping)["default"]
+			return len(queued) == 2 && queued[0].State == models.NotificationStateSkipped && queued[1].State == models.NotificationStateSkipped
+		}, time.Second, 5*time.Millisecond)
+
+		router.resolve(t, steady)
+		assert.Eventually(t, func() bool { return recorders["default"].count() == 2 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, processor.AlertEventResolved, recorders["default"].received()[1].Type)
+	})
+
+	t.Run("should repeat firing alerts until they are acknowledged", func(t *testing.T) {
+		receivers, recorders := newRecordingReceivers("default")
+		router := startRouter(t, config.RoutingConfig{Route: config.RouteConfig{
+			Receiver: "default",
+			Routes: []config.RouteConfig{
+				{Matchers: []string{"namespace=default"}, RepeatInterval: 50 * time.Millisecond},
+			},
+		}}, receivers)
+
+		alert := newRoutedAlert("high", map[string]string{"alert_type": "pod_crash_loop", "namespace": "default", "pod": "api-0"})
+		router.fire(t, alert)
+
+		assert.Eventually(t, func() bool { return recorders["default"].count() >= 3 }, time.Second, 5*time.Millisecond)
+		for _, event := range recorders["default"].received() {
+			assert.Equal(t, processor.AlertEventFiring, event.Type, "repeats are delivered as firing events")
+		}
+
+		router.acknowledge(t, alert)
+		assert.Eventually(t, func() bool {
+			events := recorders["default"].received()
+			return events[len(events)-1].Type == processor.AlertEventAcknowledged
+		}, time.Second, 5*time.Millisecond)
+
+		delivered := recorders["default"].count()
+		time.Sleep(150 * time.Millisecond)
+		assert.Equal(t, delivered, recorders["default"].count())
+	})
+}
+
+func TestBuildReceivers(t *testing.T) {
+	t.Run("should build the notifiers of each receiver", func(t *testing.T) {
+		cfg := &config.Config{
+			Email: config.EmailConfig{SMTPHost: "smtp.example.com", Username: "user"},
+			Slack: config.SlackConfig{BotToken: "xoxb-test"},
+			Routing: config.RoutingConfig{Receivers: []config.ReceiverConfig{
+				{Name: "team-a", Email: &config.EmailReceiverConfig{To: []string{"team-a@example.com"}}, Slack: &config.SlackReceiverConfig{Channel: "#team-a"}},
+				{Name: "ops", Webhooks: []config.WebhookEndpoint{{URL: "http://ops.example.com"}, {Name: "statuspage", URL: "http://status.example.com"}}},
+				{Name: "blackhole"},
+			}},
+		}
+
+		receivers, err := notifier.BuildReceivers(cfg)
+		require.NoError(t, err)
+		assert.Len(t, receivers["team-a"].Notifiers, 2)
+		require.Len(t, receivers["ops"].Notifiers, 2, "one notifier per webhook endpoint")
+		assert.Equal(t, "statuspage", receivers["ops"].Notifiers[1].Name)
+		assert.Empty(t, receivers["blackhole"].Notifiers)
+	})
+
+	t.Run("should reject email receivers without SMTP settings", func(t *testing.T) {
+		cfg := &config.Config{Routing: config.RoutingConfig{Receivers: []config.ReceiverConfig{
+			{Name: "team-a", Email: &config.EmailReceiverConfig{To: []string{"team-a@example.com"}}},
+		}}}
+
+		_, err := notifier.BuildReceivers(cfg)
+		assert.ErrorContains(t, err, "SMTP")
+	})
+
+	t.Run("should reject duplicate receivers", func(t *testing.T) {
+		cfg := &config.Config{Routing: config.RoutingConfig{Receivers: []config.ReceiverConfig{{Name: "ops"}, {Name: "ops"}}}}
+
+		_, err := notifier.BuildReceivers(cfg)
+		assert.ErrorContains(t, err, "defined twice")
+	})
+}
+
+// groupRecorder records the group notifications delivered to a receiver
+type groupRecorder struct {
+	recordingNotifier
+	groups []*notifier.AlertGroup
+}
+
+func (n *groupRecorder) NotifyGroup(ctx context.Context, group *notifier.AlertGroup) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.groups = append(n.groups, group)
+	return nil
+}
+
+func (n *groupRecorder) receivedGroups() []*notifier.AlertGroup {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]*notifier.AlertGroup(nil), n.groups...)
+}
+
+func TestRouter_Grouping(t *testing.T) {
+	podAlert := func(node, pod string) *models.Alert {
+		return newRoutedAlert("critical", map[string]string{"alert_type": "pod_unknown", "namespace": "default", "pod": pod, "node": node})
+	}
+
+	t.Run("should send one notification per group after group_wait", func(t *testing.T) {
+		recorder := &groupRecorder{}
+		receivers := map[string]*notifier.Receiver{"default": newReceiver("default", recorder)}
+		router := startRouter(t, config.RoutingConfig{Route: config.RouteConfig{
+			Receiver: "default", GroupBy: []string{"node"}, GroupWait: 50 * time.Millisecond,
+		}}, receivers)
+
+		router.fire(t, podAlert("worker-1", "a"), podAlert("worker-1", "b"), podAlert("worker-1", "c"), podAlert("worker-2", "d"))
+
+		assert.Eventually(t, func() bool { return len(recorder.receivedGroups()) == 2 }, time.Second, 5*time.Millisecond)
+		sizes := map[string]int{}
+		for _, group := range recorder.receivedGroups() {
+			sizes[group.Labels["node"]] = len(group.Firing)
+			assert.Equal(t, len(group.Firing), len(group.New))
+		}
+		assert.Equal(t, map[string]int{"worker-1": 3, "worker-2": 1}, sizes)
+		assert.Zero(t, recorder.count())
+	})
+
+	t.Run("should follow up only when membership changes", func(t *testing.T) {
+		recorder := &groupRecorder{}
+		receivers := map[string]*notifier.Receiver{"default": newReceiver("default", recorder)}
+		router := startRouter(t, config.RoutingConfig{Route: config.RouteConfig{
+			Receiver: "default", GroupBy: []string{"node"}, GroupInterval: 50 * time.Millisecond,
+		}}, receivers)
+
+		first, second := podAlert("worker-1", "a"), podAlert("worker-1", "b")
+		router.fire(t, first)
+		assert.Eventually(t, func() bool { return len(recorder.receivedGroups()) == 1 }, time.Second, 5*time.Millisecond)
+
+		// Repeated firing events of members are not changes
+		router.fire(t, first)
+		time.Sleep(100 * time.Millisecond)
+		require.Len(t, recorder.receivedGroups(), 1)
+
+		router.fire(t, second)
+		assert.Eventually(t, func() bool { return len(recorder.receivedGroups()) == 2 }, time.Second, 5*time.Millisecond)
+		update := recorder.receivedGroups()[1]
+		assert.Len(t, update.Firing, 2)
+		require.Len(t, update.New, 1)
+		assert.Equal(t, second.ID, update.New[0].ID)
+
+		router.resolve(t, first, second)
+		assert.Eventually(t, func() bool { return len(recorder.receivedGroups()) == 3 }, time.Second, 5*time.Millisecond)
+		final := recorder.receivedGroups()[2]
+		assert.True(t, final.IsResolved())
+		assert.Len(t, final.Resolved, 2)
+	})
+
+	t.Run("should repeat unchanged groups after repeat_interval", func(t *testing.T) {
+		recorder := &groupRecorder{}
+		receivers := map[string]*notifier.Receiver{"default": newReceiver("default", recorder)}
+		router := startRouter(t, config.RoutingConfig{Route: config.RouteConfig{
+			Receiver: "default", GroupBy: []string{"node"}, RepeatInterval: 50 * time.Millisecond,
+		}}, receivers)
+
+		router.fire(t, podAlert("worker-1", "a"))
+
+		assert.Eventually(t, func() bool { return len(recorder.receivedGroups()) >= 2 }, time.Second, 5*time.Millisecond)
+		assert.True(t, recorder.receivedGroups()[1].IsRepeat())
+	})
+
+	t.Run("should send group members as single events to notifiers without group support", func(t *testing.T) {
+		receivers, recorders := newRecordingReceivers("default")
+		router := startRouter(t, config.RoutingConfig{Route: config.RouteConfig{
+			Receiver: "default", GroupBy: []string{"node"}, GroupWait: 20 * time.Millisecond,
+		}}, receivers)
+
+		router.fire(t, podAlert("worker-1", "a"), podAlert("worker-1", "b"))
+
+		assert.Eventually(t, func() bool { return recorders["default"].count() == 2 }, time.Second, 5*time.Millisecond)
+		for _, event := range recorders["default"].received() {
+			assert.Equal(t, processor.AlertEventFiring, event.Type)
+		}
+	})
+}
@@ -0,0 +1,436 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+const defaultSlackAPIURL = "https://slack.com/api"
+
+// severityColors are the attachment bar colours of firing alerts
+var severityColors = map[string]string{
+	"critical": "#d00000",
+	"high":     "#f08c00",
+	"medium":   "#f2c744",
+	"low":      "#439fe0",
+}
+
+const resolvedColor = "#2eb67d"
+
+// slackLabelFields are the alert labels shown as message fields, in order
+var slackLabelFields = []struct{ label, title string }{
+	{"namespace", "Namespace"},
+	{"pod", "Pod"},
+	{"container", "Container"},
+	{"node", "Node"},
+}
+
+// SlackDispatcher posts alerts to Slack as Block Kit messages. Posted messages are remembered in
+// memory only, so alerts firing before a restart are resolved with a new message instead of an
+// update and thread reply.
+type SlackDispatcher struct {
+	config       config.SlackConfig
+	httpClient   *http.Client
+	threads      map[uuid.UUID]slackThread // firing messages posted with the bot token, by alert
+	groupThreads map[string]slackThread    // first group messages posted with the bot token, by group key
+	mu           sync.Mutex
+}
+
+// slackThread identifies a posted message so it can be updated and replied to
+type slackThread struct {
+	Channel string
+	TS      string
+}
+
+// NewSlackDispatcher creates a new Slack dispatcher
+func NewSlackDispatcher(cfg config.SlackConfig) *SlackDispatcher {
+	if cfg.APIURL == "" {
+		cfg.APIURL = defaultSlackAPIURL
+	}
+	return &SlackDispatcher{
+		config:       cfg,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		threads:      make(map[uuid.UUID]slackThread),
+		groupThreads: make(map[string]slackThread),
+	}
+}
+
+// Accepts reports whether the event is posted. Acknowledgements are for dashboards only,
+// the on-call engineer already knows.
+func (sd *SlackDispatcher) Accepts(event *processor.AlertEvent) bool {
+	return !event.IsAcknowledgement()
+}
+
+// OnAlert implements AlertObserver interface
+func (sd *SlackDispatcher) OnAlert(ctx context.Context, event *processor.AlertEvent) error {
+	if !sd.Accepts(event) {
+		return nil
+	}
+
+	if sd.config.BotToken != "" {
+		return sd.postWithBot(ctx, event)
+	}
+
+	webhookURL := sd.config.WebhookURL
+	if url, ok := sd.config.SeverityWebhooks[event.Alert.Severity]; ok {
+		webhookURL = url
+	}
+	if webhookURL == "" {
+		logger.Warn().Msg("Slack configuration incomplete, skipping Slack dispatch")
+		return nil
+	}
+
+	if err := sd.postWebhook(ctx, webhookURL, sd.buildMessage(event)); err != nil {
+		return fmt.Errorf("slack webhook dispatch failed: %w", err)
+	}
+	sd.logSent(event)
+	return nil
+}
+
+// postWithBot posts firing alerts with chat.postMessage and remembers the message, so the
+// resolution can update it and reply in its thread
+func (sd *SlackDispatcher) postWithBot(ctx context.Context, event *processor.AlertEvent) error {
+	alert := event.Alert
+	message := sd.buildMessage(event)
+
+	if event.IsResolved() {
+		sd.mu.Lock()
+		thread, ok := sd.threads[alert.ID]
+		sd.mu.Unlock()
+
+		if ok {
+			message.Channel = thread.Channel
+			message.TS = thread.TS
+			if _, err := sd.callAPI(ctx, "chat.update", message); err != nil {
+				return fmt.Errorf("slack update failed: %w", err)
+			}
+
+			reply := slackMessage{
+				Channel:  thread.Channel,
+				ThreadTS: thread.TS,
+				Text:     fmt.Sprintf(":white_check_mark: Resolved after %s", resolvedDuration(alert)),
+			}
+			if _, err := sd.callAPI(ctx, "chat.postMessage", reply); err != nil {
+				return fmt.Errorf("slack thread reply failed: %w", err)
+			}
+			// Forgotten only once resolved in Slack, so a failed update or reply is retried on the thread
+			sd.mu.Lock()
+			delete(sd.threads, alert.ID)
+			sd.mu.Unlock()
+			sd.logSent(event)
+			return nil
+		}
+	}
+
+	message.Channel = sd.config.Channel
+	if channel, ok := sd.config.SeverityChannels[alert.Severity]; ok {
+		message.Channel = channel
+	}
+	if message.Channel == "" {
+		logger.Warn().Str("severity", alert.Severity).Msg("No Slack channel configured, skipping Slack dispatch")
+		return nil
+	}
+
+	posted, err := sd.callAPI(ctx, "chat.postMessage", message)
+	if err != nil {
+		return fmt.Errorf("slack post failed: %w", err)
+	}
+
+	if !event.IsResolved() {
+		sd.mu.Lock()
+		sd.threads[alert.ID] = slackThread{Channel: posted.Channel, TS: posted.TS}
+		sd.mu.Unlock()
+	}
+	sd.logSent(event)
+	return nil
+}
+
+// NotifyGroup implements GroupNotifier interface, posting one message for the whole group.
+// With the bot token, follow-ups are posted in the thread of the group's first message, which
+// is updated once every alert of the group has resolved.
+func (sd *SlackDispatcher) NotifyGroup(ctx context.Context, group *AlertGroup) error {
+	message := sd.buildGroupMessage(group)
+	severity := group.highestSeverity()
+
+	if sd.config.BotToken == "" {
+		webhookURL := sd.config.WebhookURL
+		if url, ok := sd.config.SeverityWebhooks[severity]; ok {
+			webhookURL = url
+		}
+		if webhookURL == "" {
+			logger.Warn().Msg("Slack configuration incomplete, skipping Slack dispatch")
+			return nil
+		}
+		if err := sd.postWebhook(ctx, webhookURL, message); err != nil {
+			return fmt.Errorf("slack webhook dispatch failed: %w", err)
+		}
+		return nil
+	}
+
+	sd.mu.Lock()
+	thread, ok := sd.groupThreads[group.Key]
+	sd.mu.Unlock()
+
+	if ok {
+		if group.IsResolved() {
+			update := message
+			update.Channel = thread.Channel
+			update.TS = thread.TS
+			if _, err := sd.callAPI(ctx, "chat.update", update); err != nil {
+				return fmt.Errorf("slack update failed: %w", err)
+			}
+		}
+		message.Channel = thread.Channel
+		message.ThreadTS = thread.TS
+		if _, err := sd.callAPI(ctx, "chat.postMessage", message); err != nil {
+			return fmt.Errorf("slack thread reply failed: %w", err)
+		}
+		if group.IsResolved() {
+			sd.mu.Lock()
+			delete(sd.groupThreads, group.Key)
+			sd.mu.Unlock()
+		}
+		return nil
+	}
+
+	message.Channel = sd.config.Channel
+	if channel, ok := sd.config.SeverityChannels[severity]; ok {
+		message.Channel = channel
+	}
+	if message.Channel == "" {
+		logger.Warn().Str("severity", severity).Msg("No Slack channel configured, skipping Slack dispatch")
+		return nil
+	}
+
+	posted, err := sd.callAPI(ctx, "chat.postMessage", message)
+	if err != nil {
+		return fmt.Errorf("slack post failed: %w", err)
+	}
+	if !group.IsResolved() {
+		sd.mu.Lock()
+		sd.groupThreads[group.Key] = slackThread{Channel: posted.Channel, TS: posted.TS}
+		sd.mu.Unlock()
+	}
+	return nil
+}
+
+func (sd *SlackDispatcher) logSent(event *processor.AlertEvent) {
+	logger.Info().
+		Str("severity", event.Alert.Severity).
+		Bool("resolved", event.IsResolved()).
+		Msg("Alert sent to Slack")
+}
+
+// slackMessage is the body of incoming webhook, chat.postMessage and chat.update requests
+type slackMessage struct {
+	Channel     string            `json:"channel,omitempty"`
+	TS          string            `json:"ts,omitempty"`
+	ThreadTS    string            `json:"thread_ts,omitempty"`
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func mrkdwn(text string) slackText {
+	return slackText{Type: "mrkdwn", Text: text}
+}
+
+// buildMessage renders an alert event as a Block Kit message with a severity coloured bar.
+// Resolved events are rendered as a recovery notice.
+func (sd *SlackDispatcher) buildMessage(event *processor.AlertEvent) slackMessage {
+	alert := event.Alert
+	labels := alert.GetLabelsMap()
+	severity := strings.ToUpper(alert.Severity)
+
+	title := fmt.Sprintf(":rotating_light: *[%s] %s*", severity, labels["alert_type"])
+	color := severityColors[alert.Severity]
+	if event.IsResolved() {
+		title = fmt.Sprintf(":white_check_mark: *[RESOLVED] %s*", labels["alert_type"])
+		color = resolvedColor
+	}
+
+	var fields []slackText
+	for _, field := range slackLabelFields {
+		if value := labels[field.label]; value != "" {
+			fields = append(fields, mrkdwn(fmt.Sprintf("*%s*\n%s", field.title, value)))
+		}
+	}
+	fields = append(fields,
+		mrkdwn(fmt.Sprintf("*Severity*\n%s", alert.Severity)),
+		mrkdwn(fmt.Sprintf("*Source*\n%s", alert.Source)),
+	)
+	if event.IsResolved() {
+		fields = append(fields, mrkdwn(fmt.Sprintf("*Duration*\n%s", resolvedDuration(alert))))
+	}
+
+	context := []slackText{mrkdwn(fmt.Sprintf("Triggered %s", alert.TriggeredAt.Format(time.RFC3339)))}
+	if sd.config.DashboardURL != "" {
+		context = append(context, mrkdwn(fmt.Sprintf("<%s|View in dashboard>", sd.config.DashboardURL)))
+	}
+
+	return slackMessage{
+		Text: fmt.Sprintf("[%s] %s", severity, alert.Message),
+		Attachments: []slackAttachment{{
+			Color: color,
+			Blocks: []slackBlock{
+				{Type: "section", Text: &slackText{Type: "mrkdwn", Text: title + "\n" + alert.Message}},
+				{Type: "section", Fields: fields},
+				{Type: "context", Elements: context},
+			},
+		}},
+	}
+}
+
+// maxGroupLines limits the alerts listed per section of a group message
+const maxGroupLines = 10
+
+// buildGroupMessage renders an alert group as one Block Kit message listing its firing and
+// resolved alerts
+func (sd *SlackDispatcher) buildGroupMessage(group *AlertGroup) slackMessage {
+	title := group.title()
+	color := severityColors[group.highestSeverity()]
+	icon := ":rotating_light:"
+	if group.IsResolved() {
+		color = resolvedColor
+		icon = ":white_check_mark:"
+	}
+
+	blocks := []slackBlock{{Type: "section", Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("%s *%s*", icon, title)}}}
+	if len(group.Firing) > 0 {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: alertLines("Firing", group.Firing, group.New)}})
+	}
+	if len(group.Resolved) > 0 {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: alertLines("Resolved", group.Resolved, nil)}})
+	}
+	if sd.config.DashboardURL != "" {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{mrkdwn(fmt.Sprintf("<%s|View in dashboard>", sd.config.DashboardURL))}})
+	}
+
+	return slackMessage{
+		Text:        title,
+		Attachments: []slackAttachment{{Color: color, Blocks: blocks}},
+	}
+}
+
+// alertLines lists alerts under a heading, marking new ones and eliding beyond maxGroupLines
+func alertLines(heading string, alerts, newAlerts []*models.Alert) string {
+	isNew := make(map[*models.Alert]bool, len(newAlerts))
+	for _, alert := range newAlerts {
+		isNew[alert] = true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s (%d)*", heading, len(alerts))
+	for i, alert := range alerts {
+		if i == maxGroupLines {
+			fmt.Fprintf(&b, "\n_…and %d more_", len(alerts)-maxGroupLines)
+			break
+		}
+		marker := ""
+		if isNew[alert] {
+			marker = " :new:"
+		}
+		fmt.Fprintf(&b, "\n• *%s*%s %s", strings.ToUpper(alert.Severity), marker, alert.Message)
+	}
+	return b.String()
+}
+
+// resolvedDuration returns how long the alert fired for
+func resolvedDuration(alert *models.Alert) time.Duration {
+	resolvedAt := time.Now()
+	if alert.ResolvedAt != nil {
+		resolvedAt = *alert.ResolvedAt
+	}
+	return resolvedAt.Sub(alert.TriggeredAt).Round(time.Second)
+}
+
+// postWebhook posts a message to an incoming webhook
+func (sd *SlackDispatcher) postWebhook(ctx context.Context, url string, message slackMessage) error {
+	resp, err := sd.post(ctx, url, message)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// slackAPIResponse is the common part of Slack Web API responses
+type slackAPIResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+// callAPI calls a Slack Web API method with the bot token
+func (sd *SlackDispatcher) callAPI(ctx context.Context, method string, message slackMessage) (*slackAPIResponse, error) {
+	resp, err := sd.post(ctx, strings.TrimSuffix(sd.config.APIURL, "/")+"/"+method, message)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status %d", method, resp.StatusCode)
+	}
+
+	var result slackAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if !result.OK {
+		return nil, fmt.Errorf("%s: %s", method, result.Error)
+	}
+	return &result, nil
+}
+
+func (sd *SlackDispatcher) post(ctx context.Context, url string, message slackMessage) (*http.Response, error) {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if sd.config.BotToken != "" {
+		req.Header.Set("Authorization", "Bearer "+sd.config.BotToken)
+	}
+	return sd.httpClient.Do(req)
+}
@@ -0,0 +1,276 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/notifier"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// slackRequest is a request received by the fake Slack server
+type slackRequest struct {
+	Path          string
+	Authorization string
+	Body          map[string]interface{}
+}
+
+// fakeSlack records requests and answers like the Slack Web API, failing the first
+// failures[path] calls of a method
+type fakeSlack struct {
+	mu       sync.Mutex
+	requests []slackRequest
+	failures map[string]int
+}
+
+func (f *fakeSlack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, slackRequest{Path: r.URL.Path, Authorization: r.Header.Get("Authorization"), Body: body})
+	fail := f.failures[r.URL.Path] > 0
+	if fail {
+		f.failures[r.URL.Path]--
+	}
+	f.mu.Unlock()
+
+	if fail {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error": "ratelimited"})
+		return
+	}
+
+	if r.URL.Path == "/fail" {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("no_service"))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "channel": "C123", "ts": "1700000000.000100"})
+}
+
+func (f *fakeSlack) received() []slackRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]slackRequest(nil), f.requests...)
+}
+
+func newNotifiedAlert(severity string) *models.Alert {
+	return &models.Alert{
+		ID:          uuid.New(),
+		Severity:    severity,
+		Status:      "firing",
+		Message:     "Pod default/api-0 container 'app' is in CRASH LOOP BACKOFF",
+		Labels:      datatypes.JSON([]byte(`{"alert_type":"pod_crash_loop","namespace":"default","pod":"api-0","container":"app","node":"worker-1"}`)),
+		Source:      "k8s_pod",
+		TriggeredAt: time.Now().Add(-10 * time.Minute),
+	}
+}
+
+func TestSlackDispatcher_Webhook(t *testing.T) {
+	t.Run("should post a Block Kit message with severity colour and label fields", func(t *testing.T) {
+		slack := &fakeSlack{}
+		server := httptest.NewServer(slack)
+		defer server.Close()
+
+		dispatcher := notifier.NewSlackDispatcher(config.SlackConfig{
+			WebhookURL:   server.URL + "/default",
+			DashboardURL: "http://monitoring.example.com",
+		})
+		event := &processor.AlertEvent{Alert: newNotifiedAlert("critical"), Type: processor.AlertEventFiring}
+
+		require.NoError(t, dispatcher.OnAlert(context.Background(), event))
+
+		requests := slack.received()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "/default", requests[0].Path)
+		assert.Empty(t, requests[0].Authorization)
+
+		attachment := requests[0].Body["attachments"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "#d00000", attachment["color"])
+
+		payload, _ := json.Marshal(requests[0].Body)
+		assert.Contains(t, string(payload), "*Namespace*\\ndefault")
+		assert.Contains(t, string(payload), "*Pod*\\napi-0")
+		assert.Contains(t, string(payload), "*Node*\\nworker-1")
+		assert.Contains(t, string(payload), "http://monitoring.example.com|View in dashboard")
+	})
+
+	t.Run("should route by severity to its webhook", func(t *testing.T) {
+		slack := &fakeSlack{}
+		server := httptest.NewServer(slack)
+		defer server.Close()
+
+		dispatcher := notifier.NewSlackDispatcher(config.SlackConfig{
+			WebhookURL:       server.URL + "/default",
+			SeverityWebhooks: map[string]string{"critical": server.URL + "/pager"},
+		})
+
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("critical"), Type: processor.AlertEventFiring}))
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("low"), Type: processor.AlertEventFiring}))
+
+		requests := slack.received()
+		require.Len(t, requests, 2)
+		assert.Equal(t, "/pager", requests[0].Path)
+		assert.Equal(t, "/default", requests[1].Path)
+	})
+
+	t.Run("should return an error when the webhook rejects the message", func(t *testing.T) {
+		server := httptest.NewServer(&fakeSlack{})
+		defer server.Close()
+
+		dispatcher := notifier.NewSlackDispatcher(config.SlackConfig{WebhookURL: server.URL + "/fail"})
+		err := dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("high"), Type: processor.AlertEventFiring})
+
+		assert.ErrorContains(t, err, "no_service")
+	})
+
+	t.Run("should skip acknowledgements and unconfigured dispatchers", func(t *testing.T) {
+		slack := &fakeSlack{}
+		server := httptest.NewServer(slack)
+		defer server.Close()
+
+		dispatcher := notifier.NewSlackDispatcher(config.SlackConfig{WebhookURL: server.URL})
+		assert.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("high"), Type: processor.AlertEventAcknowledged}))
+		assert.Empty(t, slack.received())
+
+		unconfigured := notifier.NewSlackDispatcher(config.SlackConfig{})
+		assert.NoError(t, unconfigured.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("high"), Type: processor.AlertEventFiring}))
+	})
+}
+
+func TestSlackDispatcher_BotThreads(t *testing.T) {
+	t.Run("should update the original message and reply in its thread on resolve", func(t *testing.T) {
+		slack := &fakeSlack{}
+		server := httptest.NewServer(slack)
+		defer server.Close()
+
+		dispatcher := notifier.NewSlackDispatcher(config.SlackConfig{
+			BotToken:         "xoxb-test",
+			Channel:          "#alerts",
+			SeverityChannels: map[string]string{"critical": "#oncall"},
+			APIURL:           server.URL,
+		})
+
+		alert := newNotifiedAlert("critical")
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: alert, Type: processor.AlertEventFiring}))
+
+		resolvedAt := time.Now()
+		alert.Status = "resolved"
+		alert.ResolvedAt = &resolvedAt
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: alert, Type: processor.AlertEventResolved}))
+
+		requests := slack.received()
+		require.Len(t, requests, 3)
+
+		assert.Equal(t, "/chat.postMessage", requests[0].Path)
+		assert.Equal(t, "Bearer xoxb-test", requests[0].Authorization)
+		assert.Equal(t, "#oncall", requests[0].Body["channel"])
+
+		assert.Equal(t, "/chat.update", requests[1].Path)
+		assert.Equal(t, "C123", requests[1].Body["channel"])
+		assert.Equal(t, "1700000000.000100", requests[1].Body["ts"])
+		attachment := requests[1].Body["attachments"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "#2eb67d", attachment["color"])
+
+		assert.Equal(t, "/chat.postMessage", requests[2].Path)
+		assert.Equal(t, "1700000000.000100", requests[2].Body["thread_ts"])
+		assert.Contains(t, requests[2].Body["text"], "Resolved after 10m0s")
+	})
+
+	t.Run("should keep the thread until the update and reply succeed", func(t *testing.T) {
+		slack := &fakeSlack{failures: map[string]int{"/chat.update": 1}}
+		server := httptest.NewServer(slack)
+		defer server.Close()
+
+		dispatcher := notifier.NewSlackDispatcher(config.SlackConfig{BotToken: "xoxb-test", Channel: "#alerts", APIURL: server.URL})
+
+		alert := newNotifiedAlert("high")
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: alert, Type: processor.AlertEventFiring}))
+
+		alert.Status = "resolved"
+		resolved := &processor.AlertEvent{Alert: alert, Type: processor.AlertEventResolved}
+		assert.Error(t, dispatcher.OnAlert(context.Background(), resolved))
+		require.NoError(t, dispatcher.OnAlert(context.Background(), resolved))
+
+		requests := slack.received()
+		require.Len(t, requests, 4)
+		assert.Equal(t, "/chat.update", requests[2].Path, "the retry still updates the original message")
+		assert.Equal(t, "1700000000.000100", requests[2].Body["ts"])
+		assert.Equal(t, "1700000000.000100", requests[3].Body["thread_ts"])
+	})
+
+	t.Run("should post a new message when resolving an alert it never posted", func(t *testing.T) {
+		slack := &fakeSlack{}
+		server := httptest.NewServer(slack)
+		defer server.Close()
+
+		dispatcher := notifier.NewSlackDispatcher(config.SlackConfig{BotToken: "xoxb-test", Channel: "#alerts", APIURL: server.URL})
+
+		alert := newNotifiedAlert("medium")
+		alert.Status = "resolved"
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: alert, Type: processor.AlertEventResolved}))
+
+		requests := slack.received()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "/chat.postMessage", requests[0].Path)
+		assert.Equal(t, "#alerts", requests[0].Body["channel"])
+		assert.Nil(t, requests[0].Body["thread_ts"])
+	})
+}
+
+func TestSlackDispatcher_NotifyGroup(t *testing.T) {
+	t.Run("should post one message listing the group", func(t *testing.T) {
+		slack := &fakeSlack{}
+		server := httptest.NewServer(slack)
+		defer server.Close()
+
+		dispatcher := notifier.NewSlackDispatcher(config.SlackConfig{WebhookURL: server.URL + "/default"})
+		firing := []*models.Alert{newNotifiedAlert("critical"), newNotifiedAlert("high")}
+		group := &notifier.AlertGroup{Key: "root|node=worker-1", Labels: map[string]string{"node": "worker-1"}, Firing: firing, New: firing}
+
+		require.NoError(t, dispatcher.NotifyGroup(context.Background(), group))
+
+		requests := slack.received()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "[FIRING:2] node=worker-1", requests[0].Body["text"])
+		attachment := requests[0].Body["attachments"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "#d00000", attachment["color"])
+		payload, _ := json.Marshal(requests[0].Body)
+		assert.Contains(t, string(payload), "*Firing (2)*")
+	})
+
+	t.Run("should reply in the group thread and update it once resolved", func(t *testing.T) {
+		slack := &fakeSlack{}
+		server := httptest.NewServer(slack)
+		defer server.Close()
+
+		dispatcher := notifier.NewSlackDispatcher(config.SlackConfig{BotToken: "xoxb-test", Channel: "#alerts", APIURL: server.URL})
+		alerts := []*models.Alert{newNotifiedAlert("high"), newNotifiedAlert("high")}
+		labels := map[string]string{"node": "worker-1"}
+
+		require.NoError(t, dispatcher.NotifyGroup(context.Background(), &notifier.AlertGroup{Key: "g", Labels: labels, Firing: alerts[:1], New: alerts[:1]}))
+		require.NoError(t, dispatcher.NotifyGroup(context.Background(), &notifier.AlertGroup{Key: "g", Labels: labels, Firing: alerts, New: alerts[1:]}))
+		require.NoError(t, dispatcher.NotifyGroup(context.Background(), &notifier.AlertGroup{Key: "g", Labels: labels, Resolved: alerts}))
+
+		requests := slack.received()
+		require.Len(t, requests, 4)
+		assert.Equal(t, "/chat.postMessage", requests[0].Path)
+		assert.Nil(t, requests[0].Body["thread_ts"])
+		assert.Equal(t, "/chat.postMessage", requests[1].Path)
+		assert.Equal(t, "1700000000.000100", requests[1].Body["thread_ts"])
+		assert.Equal(t, "/chat.update", requests[2].Path)
+		assert.Equal(t, "[RESOLVED] node=worker-1", requests[2].Body["text"])
+		assert.Equal(t, "1700000000.000100", requests[3].Body["thread_ts"])
+	})
+}
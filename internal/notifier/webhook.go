@@ -0,0 +1,284 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, prefixed with "sha256="
+const SignatureHeader = "X-Monitoring-Signature"
+
+// TimestampHeader carries the Unix time the request was signed at
+const TimestampHeader = "X-Monitoring-Timestamp"
+
+// defaultWebhookTemplate sends the event type and the alert as JSON
+const defaultWebhookTemplate = `{"event":{{json .Event}},"alert":{{json .Alert}}}`
+
+// webhookFuncs are available to body templates
+var webhookFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// WebhookTemplateData is the data body templates are rendered with
+type WebhookTemplateData struct {
+	Event     processor.AlertEventType // firing or resolved
+	Alert     *models.Alert
+	Labels    map[string]string
+	Timestamp time.Time
+}
+
+// WebhookDispatcher posts alerts as templated JSON to configured HTTP endpoints
+type WebhookDispatcher struct {
+	endpoints  []*webhookEndpoint
+	httpClient *http.Client
+}
+
+// webhookEndpoint is a configured endpoint with its parsed template
+type webhookEndpoint struct {
+	config     config.WebhookEndpoint
+	template   *template.Template
+	severities map[string]bool
+	sources    map[string]bool
+}
+
+// NewWebhookDispatcher creates a webhook dispatcher, failing if an endpoint has no URL or an invalid template
+func NewWebhookDispatcher(cfg config.WebhooksConfig) (*WebhookDispatcher, error) {
+	dispatcher := &WebhookDispatcher{httpClient: &http.Client{}}
+
+	for i, endpointCfg := range cfg.Endpoints {
+		if endpointCfg.Name == "" {
+			endpointCfg.Name = fmt.Sprintf("webhook-%d", i+1)
+		}
+		endpointCfg.URL = os.ExpandEnv(endpointCfg.URL)
+		endpointCfg.Secret = os.ExpandEnv(endpointCfg.Secret)
+		if endpointCfg.URL == "" {
+			return nil, fmt.Errorf("webhook %q: url is required", endpointCfg.Name)
+		}
+		if endpointCfg.Method == "" {
+			endpointCfg.Method = http.MethodPost
+		}
+		if endpointCfg.Timeout <= 0 {
+			endpointCfg.Timeout = 10 * time.Second
+		}
+		if endpointCfg.RetryBackoff <= 0 {
+			endpointCfg.RetryBackoff = time.Second
+		}
+
+		headers := make(map[string]string, len(endpointCfg.Headers))
+		for name, value := range endpointCfg.Headers {
+			headers[name] = os.ExpandEnv(value)
+		}
+		endpointCfg.Headers = headers
+
+		body := endpointCfg.Template
+		if body == "" {
+			body = defaultWebhookTemplate
+		}
+		tmpl, err := template.New(endpointCfg.Name).Funcs(webhookFuncs).Option("missingkey=zero").Parse(body)
+		if err != nil {
+			return nil, fmt.Errorf("webhook %q: invalid template: %w", endpointCfg.Name, err)
+		}
+
+		dispatcher.endpoints = append(dispatcher.endpoints, &webhookEndpoint{
+			config:     endpointCfg,
+			template:   tmpl,
+			severities: toSet(endpointCfg.Severities),
+			sources:    toSet(endpointCfg.Sources),
+		})
+	}
+	return dispatcher, nil
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// Accepts reports whether any endpoint receives the event. Acknowledgements are for
+// dashboards only, the on-call engineer already knows.
+func (wd *WebhookDispatcher) Accepts(event *processor.AlertEvent) bool {
+	for _, endpoint := range wd.Endpoints() {
+		if endpoint.Accepts(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// OnAlert implements AlertObserver interface. Every matching endpoint is tried;
+// failures are joined into the returned error.
+func (wd *WebhookDispatcher) OnAlert(ctx context.Context, event *processor.AlertEvent) error {
+	var errs []error
+	for _, endpoint := range wd.Endpoints() {
+		if err := endpoint.OnAlert(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Endpoints returns a notifier per endpoint, so the outbox can deliver, retry and
+// dead-letter the notifications of each endpoint on their own
+func (wd *WebhookDispatcher) Endpoints() []*WebhookEndpointNotifier {
+	notifiers := make([]*WebhookEndpointNotifier, 0, len(wd.endpoints))
+	for _, endpoint := range wd.endpoints {
+		notifiers = append(notifiers, &WebhookEndpointNotifier{dispatcher: wd, endpoint: endpoint})
+	}
+	return notifiers
+}
+
+// WebhookEndpointNotifier posts alerts to one endpoint of a webhook dispatcher
+type WebhookEndpointNotifier struct {
+	dispatcher *WebhookDispatcher
+	endpoint   *webhookEndpoint
+}
+
+// Name returns the endpoint's configured name
+func (n *WebhookEndpointNotifier) Name() string {
+	return n.endpoint.config.Name
+}
+
+// Accepts reports whether the endpoint's filters match the event's alert. Acknowledgements
+// are never sent.
+func (n *WebhookEndpointNotifier) Accepts(event *processor.AlertEvent) bool {
+	return !event.IsAcknowledgement() && n.endpoint.accepts(event.Alert)
+}
+
+// OnAlert implements AlertObserver interface
+func (n *WebhookEndpointNotifier) OnAlert(ctx context.Context, event *processor.AlertEvent) error {
+	if !n.Accepts(event) {
+		return nil
+	}
+
+	data := WebhookTemplateData{
+		Event:     event.Type,
+		Alert:     event.Alert,
+		Labels:    event.Alert.GetLabelsMap(),
+		Timestamp: event.Timestamp,
+	}
+	if data.Event == "" {
+		data.Event = processor.AlertEventFiring
+	}
+	if data.Timestamp.IsZero() {
+		data.Timestamp = time.Now()
+	}
+
+	if err := n.dispatcher.deliver(ctx, n.endpoint, data); err != nil {
+		return fmt.Errorf("webhook %q: %w", n.endpoint.config.Name, err)
+	}
+	logger.Info().
+		Str("webhook", n.endpoint.config.Name).
+		Str("severity", event.Alert.Severity).
+		Bool("resolved", event.IsResolved()).
+		Msg("Alert sent to webhook")
+	return nil
+}
+
+// accepts reports whether the endpoint's severity and source filters match the alert
+func (e *webhookEndpoint) accepts(alert *models.Alert) bool {
+	if len(e.severities) > 0 && !e.severities[alert.Severity] {
+		return false
+	}
+	if len(e.sources) > 0 && !e.sources[alert.Source] {
+		return false
+	}
+	return true
+}
+
+// deliver renders the body and sends it, retrying network errors, 429 and 5xx responses with
+// exponential backoff
+func (wd *WebhookDispatcher) deliver(ctx context.Context, endpoint *webhookEndpoint, data WebhookTemplateData) error {
+	var body bytes.Buffer
+	if err := endpoint.template.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+
+	backoff := endpoint.config.RetryBackoff
+	var err error
+	for attempt := 0; attempt <= endpoint.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		var retry bool
+		retry, err = wd.send(ctx, endpoint, body.Bytes())
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return fmt.Errorf("failed after %d attempts: %w", endpoint.config.MaxRetries+1, err)
+}
+
+// send makes one request, reporting whether a failure is worth retrying
+func (wd *WebhookDispatcher) send(ctx context.Context, endpoint *webhookEndpoint, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, endpoint.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, endpoint.config.Method, endpoint.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range endpoint.config.Headers {
+		req.Header.Set(name, value)
+	}
+	if endpoint.config.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(endpoint.config.Secret, timestamp, body))
+	}
+
+	resp, err := wd.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return false, nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>", as sent in SignatureHeader.
+// Receivers recompute it with the shared secret and the TimestampHeader value.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
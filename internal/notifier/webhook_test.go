@@ -0,0 +1,222 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/notifier"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver records requests to a test endpoint
+type webhookReceiver struct {
+	mu       sync.Mutex
+	bodies   []string
+	headers  []http.Header
+	statuses []int // responses to return in order, then 200
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	r.bodies = append(r.bodies, string(body))
+	r.headers = append(r.headers, req.Header.Clone())
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	r.mu.Unlock()
+
+	w.WriteHeader(status)
+}
+
+func (r *webhookReceiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+func TestNewWebhookDispatcher(t *testing.T) {
+	t.Run("should reject an endpoint without url", func(t *testing.T) {
+		_, err := notifier.NewWebhookDispatcher(config.WebhooksConfig{Endpoints: []config.WebhookEndpoint{{Name: "ops"}}})
+		assert.ErrorContains(t, err, "url is required")
+	})
+
+	t.Run("should reject an invalid template", func(t *testing.T) {
+		_, err := notifier.NewWebhookDispatcher(config.WebhooksConfig{Endpoints: []config.WebhookEndpoint{
+			{Name: "ops", URL: "http://localhost", Template: "{{.Alert.Message"},
+		}})
+		assert.ErrorContains(t, err, "invalid template")
+	})
+}
+
+func TestWebhookDispatcher_OnAlert(t *testing.T) {
+	t.Run("should send the default JSON body", func(t *testing.T) {
+		receiver := &webhookReceiver{}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		dispatcher, err := notifier.NewWebhookDispatcher(config.WebhooksConfig{Endpoints: []config.WebhookEndpoint{{URL: server.URL}}})
+		require.NoError(t, err)
+
+		alert := newNotifiedAlert("high")
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: alert, Type: processor.AlertEventFiring}))
+
+		require.Equal(t, 1, receiver.count())
+		var body struct {
+			Event string `json:"event"`
+			Alert struct {
+				ID     string            `json:"id"`
+				Labels map[string]string `json:"labels"`
+			} `json:"alert"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(receiver.bodies[0]), &body))
+		assert.Equal(t, "firing", body.Event)
+		assert.Equal(t, alert.ID.String(), body.Alert.ID)
+		assert.Equal(t, "api-0", body.Alert.Labels["pod"])
+		assert.Equal(t, "application/json", receiver.headers[0].Get("Content-Type"))
+	})
+
+	t.Run("should render the template with labels and send headers", func(t *testing.T) {
+		receiver := &webhookReceiver{}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		t.Setenv("TEST_WEBHOOK_TOKEN", "s3cret")
+		dispatcher, err := notifier.NewWebhookDispatcher(config.WebhooksConfig{Endpoints: []config.WebhookEndpoint{{
+			URL:      server.URL,
+			Headers:  map[string]string{"Authorization": "Bearer ${TEST_WEBHOOK_TOKEN}"},
+			Template: `{"summary":{{json .Alert.Message}},"pod":"{{.Labels.pod}}","state":"{{upper (print .Event)}}"}`,
+		}}})
+		require.NoError(t, err)
+
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("high"), Type: processor.AlertEventResolved}))
+
+		require.Equal(t, 1, receiver.count())
+		assert.JSONEq(t, `{"summary":"Pod default/api-0 container 'app' is in CRASH LOOP BACKOFF","pod":"api-0","state":"RESOLVED"}`, receiver.bodies[0])
+		assert.Equal(t, "Bearer s3cret", receiver.headers[0].Get("Authorization"))
+	})
+
+	t.Run("should sign the body with the secret", func(t *testing.T) {
+		receiver := &webhookReceiver{}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		dispatcher, err := notifier.NewWebhookDispatcher(config.WebhooksConfig{Endpoints: []config.WebhookEndpoint{{URL: server.URL, Secret: "shared"}}})
+		require.NoError(t, err)
+
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("high"), Type: processor.AlertEventFiring}))
+
+		timestamp := receiver.headers[0].Get(notifier.TimestampHeader)
+		require.NotEmpty(t, timestamp)
+		expected := "sha256=" + notifier.Sign("shared", timestamp, []byte(receiver.bodies[0]))
+		assert.Equal(t, expected, receiver.headers[0].Get(notifier.SignatureHeader))
+	})
+
+	t.Run("should only send alerts matching the endpoint filters", func(t *testing.T) {
+		critical := &webhookReceiver{}
+		criticalServer := httptest.NewServer(critical)
+		defer criticalServer.Close()
+		nodes := &webhookReceiver{}
+		nodesServer := httptest.NewServer(nodes)
+		defer nodesServer.Close()
+
+		dispatcher, err := notifier.NewWebhookDispatcher(config.WebhooksConfig{Endpoints: []config.WebhookEndpoint{
+			{Name: "critical", URL: criticalServer.URL, Severities: []string{"critical"}},
+			{Name: "nodes", URL: nodesServer.URL, Sources: []string{"k8s_node"}},
+		}})
+		require.NoError(t, err)
+
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("critical"), Type: processor.AlertEventFiring}))
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("high"), Type: processor.AlertEventFiring}))
+
+		assert.Equal(t, 1, critical.count())
+		assert.Equal(t, 0, nodes.count())
+	})
+
+	t.Run("should expose each endpoint as its own notifier", func(t *testing.T) {
+		dispatcher, err := notifier.NewWebhookDispatcher(config.WebhooksConfig{Endpoints: []config.WebhookEndpoint{
+			{Name: "critical", URL: "http://critical.example.com", Severities: []string{"critical"}},
+			{URL: "http://all.example.com"},
+		}})
+		require.NoError(t, err)
+
+		endpoints := dispatcher.Endpoints()
+		require.Len(t, endpoints, 2)
+		assert.Equal(t, "critical", endpoints[0].Name())
+		assert.Equal(t, "webhook-2", endpoints[1].Name())
+
+		high := &processor.AlertEvent{Alert: newNotifiedAlert("high"), Type: processor.AlertEventFiring}
+		assert.False(t, endpoints[0].Accepts(high))
+		assert.True(t, endpoints[1].Accepts(high))
+	})
+
+	t.Run("should retry server errors and give up on client errors", func(t *testing.T) {
+		flaky := &webhookReceiver{statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway}}
+		flakyServer := httptest.NewServer(flaky)
+		defer flakyServer.Close()
+		rejecting := &webhookReceiver{statuses: []int{http.StatusBadRequest}}
+		rejectingServer := httptest.NewServer(rejecting)
+		defer rejectingServer.Close()
+
+		dispatcher, err := notifier.NewWebhookDispatcher(config.WebhooksConfig{Endpoints: []config.WebhookEndpoint{
+			{Name: "flaky", URL: flakyServer.URL, MaxRetries: 3, RetryBackoff: time.Millisecond},
+			{Name: "rejecting", URL: rejectingServer.URL, MaxRetries: 3, RetryBackoff: time.Millisecond},
+		}})
+		require.NoError(t, err)
+
+		err = dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("high"), Type: processor.AlertEventFiring})
+
+		assert.Equal(t, 3, flaky.count())
+		assert.Equal(t, 1, rejecting.count())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `webhook "rejecting"`)
+		assert.NotContains(t, err.Error(), `webhook "flaky"`)
+	})
+
+	t.Run("should time out slow endpoints", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}))
+		defer server.Close()
+
+		dispatcher, err := notifier.NewWebhookDispatcher(config.WebhooksConfig{Endpoints: []config.WebhookEndpoint{
+			{URL: server.URL, Timeout: 20 * time.Millisecond, MaxRetries: 1, RetryBackoff: time.Millisecond},
+		}})
+		require.NoError(t, err)
+
+		err = dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("high"), Type: processor.AlertEventFiring})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed after 2 attempts")
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("should skip acknowledgements", func(t *testing.T) {
+		receiver := &webhookReceiver{}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		dispatcher, err := notifier.NewWebhookDispatcher(config.WebhooksConfig{Endpoints: []config.WebhookEndpoint{{URL: server.URL}}})
+		require.NoError(t, err)
+
+		require.NoError(t, dispatcher.OnAlert(context.Background(), &processor.AlertEvent{Alert: newNotifiedAlert("high"), Type: processor.AlertEventAcknowledged}))
+		assert.Equal(t, 0, receiver.count())
+	})
+}
@@ -13,14 +13,16 @@ type EvaluatorEngine struct {
 	alertRepo    repository.AlertRepo
 	eventBus     *EventBus
 	stateManager *AlertStateManager
+	ruleEngine   *RuleEngine
 	workerPool   *pool.WorkerPool
 }
 
-func NewEvaluatorEngine(alertRepo repository.AlertRepo, eventBus *EventBus) *EvaluatorEngine {
+func NewEvaluatorEngine(alertRepo repository.AlertRepo, eventBus *EventBus, ruleEngine *RuleEngine) *EvaluatorEngine {
 	return &EvaluatorEngine{
 		alertRepo:    alertRepo,
 		eventBus:     eventBus,
 		stateManager: NewAlertStateManager(alertRepo, eventBus),
+		ruleEngine:   ruleEngine,
 		workerPool:   pool.NewWorkerPool(5, 300), // 5 workers, 300 task queue
 	}
 }
@@ -41,6 +43,11 @@ func (ee *EvaluatorEngine) GetStateManager() *AlertStateManager {
 	return ee.stateManager
 }
 
+// GetRuleEngine returns the rule engine watchers evaluate observations with
+func (ee *EvaluatorEngine) GetRuleEngine() *RuleEngine {
+	return ee.ruleEngine
+}
+
 // GetWorkerPool returns the worker pool for use by watchers
 func (ee *EvaluatorEngine) GetWorkerPool() *pool.WorkerPool {
 	return ee.workerPool
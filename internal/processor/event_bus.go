@@ -9,12 +9,35 @@ import (
 	"github.com/monitoring-engine/monitoring-tool/internal/logger"
 )
 
+// AlertEventType describes the state transition that produced an event
+type AlertEventType string
+
+const (
+	AlertEventFiring         AlertEventType = "firing"
+	AlertEventResolved       AlertEventType = "resolved"
+	AlertEventAcknowledged   AlertEventType = "acknowledged"
+	AlertEventUnacknowledged AlertEventType = "unacknowledged"
+	AlertEventRepeated       AlertEventType = "repeated" // a firing alert notified again after its route's repeat interval
+)
+
 // AlertEvent represents an alert event
 type AlertEvent struct {
+	Type      AlertEventType
 	Alert     *models.Alert
 	Timestamp time.Time
 }
 
+// IsResolved returns true if the event reports a recovered alert
+func (e *AlertEvent) IsResolved() bool {
+	return e.Type == AlertEventResolved
+}
+
+// IsAcknowledgement returns true if the event only reports an operator (un)acknowledging an alert.
+// Notification channels skip these; dashboards still render them.
+func (e *AlertEvent) IsAcknowledgement() bool {
+	return e.Type == AlertEventAcknowledged || e.Type == AlertEventUnacknowledged
+}
+
 // AlertObserver interface (Observer Pattern)
 type AlertObserver interface {
 	OnAlert(ctx context.Context, event *AlertEvent) error
@@ -0,0 +1,142 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+)
+
+// inhibitRule mutes alerts matching target while an active alert matching source has the
+// same values for the equal labels
+type inhibitRule struct {
+	source []models.Matcher
+	target []models.Matcher
+	equal  []string
+}
+
+// Inhibitor suppresses symptom alerts while the alert for their cause is active, e.g. pod
+// alerts on a node that is not ready
+type Inhibitor struct {
+	alertRepo repository.AlertRepo
+	rules     []inhibitRule
+}
+
+// NewInhibitor creates an inhibitor for the configured rules, looking up source alerts in the alert repository
+func NewInhibitor(alertRepo repository.AlertRepo, rulesCfg []config.InhibitRuleConfig) (*Inhibitor, error) {
+	inhibitor := &Inhibitor{alertRepo: alertRepo}
+
+	for i, ruleCfg := range rulesCfg {
+		if len(ruleCfg.SourceMatchers) == 0 || len(ruleCfg.TargetMatchers) == 0 {
+			return nil, fmt.Errorf("inhibit rule %d: source and target matchers are required", i)
+		}
+		rule := inhibitRule{equal: ruleCfg.Equal}
+		for _, expr := range ruleCfg.SourceMatchers {
+			matcher, err := models.ParseMatcher(expr)
+			if err != nil {
+				return nil, fmt.Errorf("inhibit rule %d: %w", i, err)
+			}
+			rule.source = append(rule.source, matcher)
+		}
+		for _, expr := range ruleCfg.TargetMatchers {
+			matcher, err := models.ParseMatcher(expr)
+			if err != nil {
+				return nil, fmt.Errorf("inhibit rule %d: %w", i, err)
+			}
+			rule.target = append(rule.target, matcher)
+		}
+		inhibitor.rules = append(inhibitor.rules, rule)
+	}
+	return inhibitor, nil
+}
+
+// Suppress marks the alert as inhibited by the first active source alert of a rule whose
+// target matchers match it. Severity and source can be matched as labels.
+func (in *Inhibitor) Suppress(ctx context.Context, alert *models.Alert) (bool, error) {
+	labels := alert.MatchLabels()
+
+	var rules []inhibitRule
+	for _, rule := range in.rules {
+		if matchAll(rule.target, labels) {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return false, nil
+	}
+
+	for _, rule := range rules {
+		sourceName, sourceLabels := rule.sourceQuery(labels)
+		candidates, err := in.alertRepo.GetActiveByLabels(ctx, sourceName, sourceLabels)
+		if err != nil {
+			return false, err
+		}
+
+		for _, source := range candidates {
+			// An alert never inhibits itself, and two alerts never inhibit each other
+			if source.ID == alert.ID || source.Fingerprint == alert.Fingerprint {
+				continue
+			}
+			if source.InhibitedBy != nil && *source.InhibitedBy == alert.ID {
+				continue
+			}
+
+			sourceLabels := source.MatchLabels()
+			if matchAll(rule.source, sourceLabels) && equalLabels(rule.equal, labels, sourceLabels) {
+				alert.Inhibit(source.ID)
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// sourceQuery returns the source and labels every source alert of the rule for the target has, so
+// only those active alerts are loaded: the values of its equality matchers and the target's values
+// of the equal labels. Other matchers, severity and labels the target lacks are checked on the
+// loaded alerts.
+func (r inhibitRule) sourceQuery(target map[string]string) (string, map[string]string) {
+	var source string
+	labels := make(map[string]string)
+	add := func(name, value string) {
+		switch {
+		case value == "" || name == "severity":
+		case name == "source":
+			source = value
+		default:
+			labels[name] = value
+		}
+	}
+
+	for _, matcher := range r.source {
+		if matcher.Operator == models.MatchEqual {
+			add(matcher.Name, matcher.Value)
+		}
+	}
+	for _, name := range r.equal {
+		add(name, target[name])
+	}
+	return source, labels
+}
+
+func matchAll(matchers []models.Matcher, labels map[string]string) bool {
+	for _, matcher := range matchers {
+		if !matcher.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalLabels reports whether both label sets have the same value for each name.
+// A label missing from both counts as equal.
+func equalLabels(names []string, a, b map[string]string) bool {
+	for _, name := range names {
+		if a[name] != b[name] {
+			return false
+		}
+	}
+	return true
+}
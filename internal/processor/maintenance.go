@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+)
+
+// MaintenanceSuppressor suppresses alerts raised during a maintenance window or for a cordoned node.
+// Cordoned nodes are reported by the node watcher and treated as implicit maintenance, which also
+// covers pod alerts carrying the node label.
+type MaintenanceSuppressor struct {
+	maintenanceRepo repository.MaintenanceRepo
+	cordoned        map[string]bool
+	mu              sync.RWMutex
+}
+
+// NewMaintenanceSuppressor creates a new maintenance suppressor backed by the maintenance repository
+func NewMaintenanceSuppressor(maintenanceRepo repository.MaintenanceRepo) *MaintenanceSuppressor {
+	return &MaintenanceSuppressor{
+		maintenanceRepo: maintenanceRepo,
+		cordoned:        make(map[string]bool),
+	}
+}
+
+// SetNodeCordoned records whether a node is cordoned (spec.unschedulable)
+func (ms *MaintenanceSuppressor) SetNodeCordoned(node string, cordoned bool) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if cordoned {
+		ms.cordoned[node] = true
+	} else {
+		delete(ms.cordoned, node)
+	}
+}
+
+// IsNodeCordoned returns true if the node is currently cordoned
+func (ms *MaintenanceSuppressor) IsNodeCordoned(node string) bool {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.cordoned[node]
+}
+
+// Suppress marks the alert as in maintenance if its node is cordoned or an active window covers it
+func (ms *MaintenanceSuppressor) Suppress(ctx context.Context, alert *models.Alert) (bool, error) {
+	labels := alert.GetLabelsMap()
+	if node := labels["node"]; node != "" && ms.IsNodeCordoned(node) {
+		alert.EnterMaintenance(nil)
+		return true, nil
+	}
+
+	now := time.Now()
+	windows, err := ms.maintenanceRepo.GetInEffect(ctx, now)
+	if err != nil {
+		return false, err
+	}
+
+	for _, window := range windows {
+		if window.IsActive(now) && window.Matches(labels) {
+			alert.EnterMaintenance(&window.ID)
+			return true, nil
+		}
+	}
+	return false, nil
+}
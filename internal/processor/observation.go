@@ -0,0 +1,144 @@
+package processor
+
+import "time"
+
+// Signal names a value watchers observe on a Kubernetes object
+type Signal string
+
+const (
+	SignalPodPhase                  Signal = "pod_phase"                        // Text: Pending, Running, Failed, Unknown...
+	SignalContainerWaitingReason    Signal = "container_waiting_reason"         // Text: CrashLoopBackOff, ImagePullBackOff...; Value: restart count
+	SignalContainerTerminatedReason Signal = "container_last_terminated_reason" // Text: OOMKilled, Error...; Value: restart count
+	SignalRestartCount              Signal = "restart_count"                    // Value: total restarts across containers
+	SignalCPUPercent                Signal = "cpu_percent"                      // Value: usage as a percentage of requests (pods) or capacity (nodes)
+	SignalMemoryPercent             Signal = "memory_percent"                   // Value: usage as a percentage of requests (pods) or capacity (nodes)
+	SignalNodeCondition             Signal = "node_condition"                   // Key: condition type; Text: True, False, Unknown
+	SignalContainerMemoryLimit      Signal = "container_memory_limit_percent"   // Key: container name; Value: memory working set as a percentage of the limit
+	SignalEphemeralStorage          Signal = "ephemeral_storage_percent"        // Key: container name; Value: writable layer and logs as a percentage of the ephemeral-storage limit
+	SignalRolloutProgress           Signal = "rollout_progress"                 // Key: workload kind; Text: reason of the Progressing condition; Value: progress deadline in seconds
+	SignalAvailableReplicasPercent  Signal = "available_replicas_percent"       // Key: workload kind; Value: available replicas as a percentage of desired
+	SignalUnavailableReplicas       Signal = "unavailable_replicas"             // Key: workload kind; Value: StatefulSet replicas not ready, or DaemonSet pods unavailable
+	SignalMisscheduledPods          Signal = "misscheduled_pods"                // Key: workload kind; Value: DaemonSet pods running on nodes they should not
+	SignalGenerationLag             Signal = "generation_lag"                   // Key: workload kind; Value: spec generations the controller has not observed
+	SignalVolumeUsedPercent         Signal = "volume_used_percent"              // Value: space in use as a percentage of the claim's volume capacity
+	SignalVolumeInodesPercent       Signal = "volume_inodes_used_percent"       // Value: inodes in use as a percentage of the claim's volume inodes
+)
+
+// knownSignals lists the signals rules may refer to
+var knownSignals = map[Signal]bool{
+	SignalPodPhase:                  true,
+	SignalContainerWaitingReason:    true,
+	SignalContainerTerminatedReason: true,
+	SignalRestartCount:              true,
+	SignalCPUPercent:                true,
+	SignalMemoryPercent:             true,
+	SignalNodeCondition:             true,
+	SignalContainerMemoryLimit:      true,
+	SignalEphemeralStorage:          true,
+	SignalRolloutProgress:           true,
+	SignalAvailableReplicasPercent:  true,
+	SignalUnavailableReplicas:       true,
+	SignalMisscheduledPods:          true,
+	SignalGenerationLag:             true,
+	SignalVolumeUsedPercent:         true,
+	SignalVolumeInodesPercent:       true,
+}
+
+// Observation is the current state of one pod, node, workload or claim's volume as seen by a watcher
+type Observation struct {
+	Source       string            // alert source for alerts raised from this observation
+	Namespace    string            // empty for nodes
+	Pod          string            // empty for nodes and workloads; the pod mounting a volume
+	Node         string            // node name, or the node a pod is scheduled on
+	WorkloadKind string            // Deployment, StatefulSet or DaemonSet, for workloads
+	Workload     string            // empty for pods and nodes
+	PVC          string            // PersistentVolumeClaim, for volumes
+	Labels       map[string]string // Kubernetes object labels, matched by rule selectors
+	Samples      []Sample
+
+	// Annotations of the object and of its namespace, which can override rule
+	// thresholds and disable alert types (see AnnotationPrefix)
+	Annotations          map[string]string
+	NamespaceAnnotations map[string]string
+}
+
+// Sample is one observed signal value
+type Sample struct {
+	Signal  Signal
+	Key     string            // container name, node condition type or workload kind the sample describes
+	Text    string            // string value compared by ==, !=, in and not_in
+	Value   float64           // numeric value compared by >, >=, <, <=; also the alert value
+	Since   time.Time         // when the condition began, if Kubernetes reports it; otherwise tracked by the engine
+	Reason  string            // short cause, available to message templates
+	Message string            // detail text, available to message templates
+	Labels  map[string]string // extra labels added to alerts raised from this sample
+}
+
+// object returns the kind of object observed, which rules are evaluated for
+func (o *Observation) object() string {
+	switch {
+	case o.PVC != "":
+		return ObjectVolume
+	case o.Workload != "":
+		return ObjectWorkload
+	case o.Pod != "":
+		return ObjectPod
+	}
+	return ObjectNode
+}
+
+// subjectLabels returns the labels identifying the observed object
+func (o *Observation) subjectLabels() map[string]string {
+	labels := make(map[string]string)
+	if o.PVC != "" {
+		labels["namespace"] = o.Namespace
+		labels["pvc"] = o.PVC
+		// Describes the volume alert; the claim alone identifies it
+		if o.Pod != "" {
+			labels["pod"] = o.Pod
+		}
+		return labels
+	}
+	if o.Workload != "" {
+		labels["namespace"] = o.Namespace
+		labels["workload_kind"] = o.WorkloadKind
+		labels["workload"] = o.Workload
+		return labels
+	}
+	if o.Pod != "" {
+		labels["namespace"] = o.Namespace
+		labels["pod"] = o.Pod
+	}
+	if o.Node != "" {
+		labels["node"] = o.Node
+	}
+	return labels
+}
+
+// subjectName returns a readable name for the observed object
+func (o *Observation) subjectName() string {
+	if o.PVC != "" {
+		return o.Namespace + "/" + o.PVC
+	}
+	if o.Workload != "" {
+		return o.WorkloadKind + " " + o.Namespace + "/" + o.Workload
+	}
+	if o.Pod != "" {
+		return o.Namespace + "/" + o.Pod
+	}
+	return o.Node
+}
+
+// subjectKey identifies the observed object and the watcher that observed it
+func (o *Observation) subjectKey() string {
+	if o.PVC != "" {
+		return o.Source + "|" + o.Namespace + "/" + o.PVC
+	}
+	if o.Workload != "" {
+		return o.Source + "|" + o.WorkloadKind + "/" + o.Namespace + "/" + o.Workload
+	}
+	if o.Pod != "" {
+		return o.Source + "|" + o.Namespace + "/" + o.Pod
+	}
+	return o.Source + "|" + o.Node
+}
@@ -0,0 +1,477 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/logger"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+)
+
+// EventFilter is implemented by notifiers that only deliver some events, e.g. not acknowledgements.
+// Events a notifier does not accept are neither queued nor recorded for it.
+type EventFilter interface {
+	Accepts(event *AlertEvent) bool
+}
+
+// Batcher is implemented by notifiers that send the notifications of a period together, e.g. email
+// digests. Their notifications are queued under the batch's key, due when the batch is, and all
+// notifications of a batch are delivered in one NotifyBatch call, so a batch is not lost on restart
+// and is retried as a whole.
+type Batcher interface {
+	// Batch returns the key and due time of the batch the event joins at now; an empty key sends the event on its own
+	Batch(event *AlertEvent, now time.Time) (key string, dueAt time.Time)
+	// NotifyBatch sends the events of a batch, oldest first
+	NotifyBatch(ctx context.Context, events []*AlertEvent) error
+}
+
+// Errors of notifications that can never be delivered, so they are dead-lettered without retries
+var (
+	ErrNoTarget        = errors.New("no notifier registered for channel and receiver")
+	errInvalidSnapshot = errors.New("invalid alert snapshot")
+)
+
+// ErrNotificationSkipped is returned by a router that deliberately sends nothing for a batch,
+// e.g. an alert that resolved before its group was notified. The notifications are recorded as skipped.
+var ErrNotificationSkipped = errors.New("notification skipped")
+
+// Route is where a routed alert event is delivered: one notifier channel of a receiver.
+// Events of the same group key that are due together are delivered as one batch.
+type Route struct {
+	Channel  string
+	Receiver string
+	GroupKey string    // empty for events delivered on their own
+	DueAt    time.Time // zero for events due immediately
+}
+
+// RoutedEvent is an event the router raises on its own, e.g. a repeated notification
+type RoutedEvent struct {
+	Route Route
+	Event *AlertEvent
+}
+
+// NotificationRouter decides which receivers an alert event is queued for and delivers the
+// queued notifications. The outbox stores a notification per route, so each receiver's
+// channel is retried and dead-lettered on its own and survives restarts.
+type NotificationRouter interface {
+	// Route returns the routes the event is queued on
+	Route(event *AlertEvent) []Route
+	// Repeats returns the notifications that are due again at now
+	Repeats(now time.Time) []RoutedEvent
+	// Deliver sends the events queued on a route that are due together, oldest first
+	Deliver(ctx context.Context, route Route, events []*AlertEvent) error
+}
+
+// outboxTarget is a notifier the outbox delivers to
+type outboxTarget struct {
+	channel  string
+	receiver string
+	notifier AlertObserver
+}
+
+// Outbox delivers alert notifications durably. The state manager queues a notification per
+// target, or per route of the router, in the same transaction that stores the alert change; a
+// background worker delivers them, retrying failures with exponential backoff and jitter until
+// they are dead-lettered. Queued notifications survive restarts and carry the alert as it was
+// at their event.
+type Outbox struct {
+	transactor    repository.Transactor
+	notifications repository.NotificationRepo
+	alertRepo     repository.AlertRepo
+	config        config.DeliveryConfig
+	targets       []outboxTarget
+	router        NotificationRouter
+	wakeCh        chan struct{}
+	stopCh        chan struct{}
+	wg            sync.WaitGroup
+	mu            sync.RWMutex
+}
+
+// NewOutbox creates an outbox storing notifications through the transactor and repositories.
+// Unset delivery settings take their defaults.
+func NewOutbox(transactor repository.Transactor, notifications repository.NotificationRepo, alertRepo repository.AlertRepo, cfg config.DeliveryConfig) *Outbox {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = 10 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+
+	return &Outbox{
+		transactor:    transactor,
+		notifications: notifications,
+		alertRepo:     alertRepo,
+		config:        cfg,
+		wakeCh:        make(chan struct{}, 1),
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// Register adds a notifier that receives every alert event it accepts. Notifications are
+// recorded under the channel and receiver. Registered notifiers are not used with a router.
+func (o *Outbox) Register(channel, receiver string, notifier AlertObserver) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.targets = append(o.targets, outboxTarget{channel: channel, receiver: receiver, notifier: notifier})
+}
+
+// UseRouter queues every alert event on the routes the router returns instead of the registered targets
+func (o *Outbox) UseRouter(router NotificationRouter) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.router = router
+}
+
+// enqueue stores a queued notification of the event for each route, or without a router for
+// each target that accepts it
+func (o *Outbox) enqueue(ctx context.Context, notifications repository.NotificationRepo, event *AlertEvent) error {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	if o.router != nil {
+		for _, route := range o.router.Route(event) {
+			if err := o.queue(ctx, notifications, route, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	now := time.Now()
+	for _, target := range o.targets {
+		if filter, ok := target.notifier.(EventFilter); ok && !filter.Accepts(event) {
+			continue
+		}
+		route := Route{Channel: target.channel, Receiver: target.receiver}
+		if batcher, ok := target.notifier.(Batcher); ok {
+			route.GroupKey, route.DueAt = batcher.Batch(event, now)
+		}
+		if err := o.queue(ctx, notifications, route, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queue stores a notification of the event on the route, due when the route is
+func (o *Outbox) queue(ctx context.Context, notifications repository.NotificationRepo, route Route, event *AlertEvent) error {
+	notification := models.NewNotification(event.Alert.ID, route.Channel, route.Receiver, string(event.Type))
+	notification.GroupKey = route.GroupKey
+	if err := notification.Snapshot(event.Alert); err != nil {
+		return fmt.Errorf("failed to snapshot alert for %s notification: %w", route.Channel, err)
+	}
+	dueAt := route.DueAt
+	if dueAt.IsZero() {
+		dueAt = time.Now()
+	}
+	notification.ScheduleAttempt(dueAt)
+	if err := notifications.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to queue %s notification: %w", route.Channel, err)
+	}
+	return nil
+}
+
+// queueRepeats queues the notifications the router repeats
+func (o *Outbox) queueRepeats(ctx context.Context) {
+	o.mu.RLock()
+	router := o.router
+	o.mu.RUnlock()
+	if router == nil {
+		return
+	}
+
+	for _, repeat := range router.Repeats(time.Now()) {
+		if err := o.queue(ctx, o.notifications, repeat.Route, repeat.Event); err != nil {
+			logger.Error().Err(err).Str("alert_id", repeat.Event.Alert.ID.String()).Msg("Failed to queue repeated notification")
+		}
+	}
+}
+
+// wake makes the worker look for due notifications without waiting for the next poll
+func (o *Outbox) wake() {
+	select {
+	case o.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// Start begins delivering queued notifications, including those left over from before a restart
+func (o *Outbox) Start(ctx context.Context) {
+	logger.Info().
+		Dur("poll_interval", o.config.PollInterval).
+		Int("max_attempts", o.config.MaxAttempts).
+		Msg("Starting notification outbox")
+
+	o.wg.Add(1)
+	go o.run(ctx)
+}
+
+// Stop stops delivering notifications and waits for the current deliveries to finish
+func (o *Outbox) Stop() {
+	close(o.stopCh)
+	o.wg.Wait()
+}
+
+func (o *Outbox) run(ctx context.Context) {
+	defer o.wg.Done()
+
+	ticker := time.NewTicker(o.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		o.queueRepeats(ctx)
+		o.deliverDue(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-o.wakeCh:
+		case <-o.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverDue claims the due notifications and delivers them concurrently, each batch of
+// notifications on the same route and group key together
+func (o *Outbox) deliverDue(ctx context.Context) {
+	now := time.Now()
+	// A claimed notification is retried after the lease if this process dies while delivering it
+	leaseUntil := now.Add(2 * o.config.Timeout)
+
+	due, err := o.notifications.ClaimDue(ctx, now, leaseUntil, o.config.BatchSize)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to claim due notifications")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, batch := range batches(due) {
+		wg.Add(1)
+		go func(batch []*models.Notification) {
+			defer wg.Done()
+			o.deliver(ctx, batch)
+		}(batch)
+	}
+	wg.Wait()
+
+	// A full batch means more notifications may be due already
+	if len(due) == o.config.BatchSize {
+		o.wake()
+	}
+}
+
+// batches splits claimed notifications into the batches delivered together: those on the same
+// channel, receiver and group key, oldest first. Notifications without a group key are sent alone.
+func batches(due []*models.Notification) [][]*models.Notification {
+	var result [][]*models.Notification
+	index := make(map[string]int)
+	for _, notification := range due {
+		if notification.GroupKey == "" {
+			result = append(result, []*models.Notification{notification})
+			continue
+		}
+		key := notification.Channel + "|" + notification.Receiver + "|" + notification.GroupKey
+		if i, ok := index[key]; ok {
+			result[i] = append(result[i], notification)
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, []*models.Notification{notification})
+	}
+	for _, batch := range result {
+		sort.SliceStable(batch, func(i, j int) bool { return batch[i].CreatedAt.Before(batch[j].CreatedAt) })
+	}
+	return result
+}
+
+// deliver sends a batch of notifications and records the outcome of each, scheduling a retry of
+// the whole batch or dead-lettering notifications on failure
+func (o *Outbox) deliver(ctx context.Context, batch []*models.Notification) {
+	err := o.send(ctx, batch)
+
+	if errors.Is(err, ErrNotificationSkipped) {
+		for _, notification := range batch {
+			notification.Skip()
+		}
+		o.record(ctx, batch)
+		return
+	}
+
+	attempts := 0
+	for _, notification := range batch {
+		notification.RecordAttempt(err)
+		attempts = max(attempts, notification.Attempts)
+	}
+	if err == nil {
+		o.record(ctx, batch)
+		return
+	}
+
+	// The batch is retried together so a group is not split across notifications
+	retryAt := time.Now().Add(o.backoff(attempts))
+	for _, notification := range batch {
+		if notification.Attempts >= o.config.MaxAttempts || errors.Is(err, ErrNoTarget) || errors.Is(err, errInvalidSnapshot) || errors.Is(err, repository.ErrAlertNotFound) {
+			notification.GiveUp()
+			logger.Error().
+				Err(err).
+				Str("notification_id", notification.ID.String()).
+				Str("channel", notification.Channel).
+				Str("receiver", notification.Receiver).
+				Int("attempts", notification.Attempts).
+				Msg("Notification dead-lettered")
+			continue
+		}
+		notification.ScheduleAttempt(retryAt)
+		logger.Warn().
+			Err(err).
+			Str("notification_id", notification.ID.String()).
+			Str("channel", notification.Channel).
+			Str("receiver", notification.Receiver).
+			Int("attempts", notification.Attempts).
+			Time("retry_at", retryAt).
+			Msg("Notification failed, will retry")
+	}
+	o.record(ctx, batch)
+}
+
+// record stores the outcome of delivered notifications, even if the delivery timed out
+func (o *Outbox) record(ctx context.Context, batch []*models.Notification) {
+	for _, notification := range batch {
+		if err := o.notifications.Update(context.WithoutCancel(ctx), notification); err != nil {
+			logger.Error().Err(err).Str("notification_id", notification.ID.String()).Msg("Failed to record notification outcome")
+		}
+	}
+}
+
+// send delivers the events of a batch to their route, or of a single notification to its target
+func (o *Outbox) send(ctx context.Context, batch []*models.Notification) error {
+	events := make([]*AlertEvent, 0, len(batch))
+	for _, notification := range batch {
+		event, err := o.event(ctx, notification)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+
+	o.mu.RLock()
+	router := o.router
+	o.mu.RUnlock()
+
+	first := batch[0]
+	if router != nil && first.Channel == models.ChannelTypeRouter {
+		return o.reroute(ctx, router, events)
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, o.config.Timeout)
+	defer cancel()
+	if router != nil {
+		return router.Deliver(sendCtx, Route{Channel: first.Channel, Receiver: first.Receiver, GroupKey: first.GroupKey}, events)
+	}
+
+	targets := o.targetsOf(first.Channel, first.Receiver)
+	if len(targets) == 0 {
+		return ErrNoTarget
+	}
+	if batcher, ok := targets[0].(Batcher); ok && first.GroupKey != "" {
+		return batcher.NotifyBatch(sendCtx, events)
+	}
+	var errs []error
+	for _, target := range targets {
+		if err := target.OnAlert(sendCtx, events[0]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// event rebuilds the alert event a notification was queued for
+func (o *Outbox) event(ctx context.Context, notification *models.Notification) (*AlertEvent, error) {
+	alert, err := notification.SnapshotAlert()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidSnapshot, err)
+	}
+	if alert == nil {
+		// Queued before notifications stored the alert with the event
+		if alert, err = o.alertRepo.GetByID(ctx, notification.AlertID); err != nil {
+			return nil, err
+		}
+	}
+	return &AlertEvent{
+		Type:      AlertEventType(notification.Event),
+		Alert:     alert,
+		Timestamp: notification.CreatedAt,
+	}, nil
+}
+
+// reroute queues notifications that were handed to the router as a whole before it queued
+// one per receiver channel, on the routes it returns for them now
+func (o *Outbox) reroute(ctx context.Context, router NotificationRouter, events []*AlertEvent) error {
+	for _, event := range events {
+		for _, route := range router.Route(event) {
+			if err := o.queue(ctx, o.notifications, route, event); err != nil {
+				return err
+			}
+		}
+	}
+	o.wake()
+	return nil
+}
+
+// targetsOf returns the notifier registered for the channel and receiver. Notifications queued
+// without a receiver before the channel had a target per receiver, e.g. per webhook endpoint,
+// go to every target of the channel.
+func (o *Outbox) targetsOf(channel, receiver string) []AlertObserver {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	var all []AlertObserver
+	for _, target := range o.targets {
+		if target.channel != channel {
+			continue
+		}
+		if target.receiver == receiver {
+			return []AlertObserver{target.notifier}
+		}
+		all = append(all, target.notifier)
+	}
+	if receiver == "" {
+		return all
+	}
+	return nil
+}
+
+// backoff returns the delay before the retry following the given number of attempts: the
+// initial backoff doubled per attempt up to the maximum, of which a random half is jitter so
+// retries of notifications that failed together spread out
+func (o *Outbox) backoff(attempts int) time.Duration {
+	delay := o.config.InitialBackoff
+	for i := 1; i < attempts && delay < o.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > o.config.MaxBackoff {
+		delay = o.config.MaxBackoff
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
@@ -0,0 +1,73 @@
+package processor
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+)
+
+// Annotations read from pods, namespaces and nodes
+const (
+	// AnnotationPrefix prefixes a rule's ThresholdAnnotation, e.g. monitoring-tool/cpu-threshold
+	AnnotationPrefix = "monitoring-tool/"
+	// AnnotationDisabledAlerts lists comma-separated alert types not to raise for the object
+	AnnotationDisabledAlerts = AnnotationPrefix + "disabled-alerts"
+)
+
+// SetNamespaceOverrides sets the per-namespace thresholds and disabled alert types from configuration
+func (e *RuleEngine) SetNamespaceOverrides(namespaces map[string]config.NamespaceAlertRules) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.namespaces = namespaces
+}
+
+// disabledFor reports whether the rule is disabled for the observed object by its annotations,
+// its namespace's annotations or the namespace configuration
+func (e *RuleEngine) disabledFor(rule *compiledRule, obs *Observation) bool {
+	if listsAlertType(obs.Annotations[AnnotationDisabledAlerts], rule.Name) ||
+		listsAlertType(obs.NamespaceAnnotations[AnnotationDisabledAlerts], rule.Name) {
+		return true
+	}
+	if obs.Namespace == "" {
+		return false
+	}
+	for _, alertType := range e.namespaces[obs.Namespace].Disabled {
+		if alertType == rule.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// thresholdFor resolves the rule threshold for the observed object: its annotation, then its
+// namespace's annotation, then the namespace configuration, then the rule's own value.
+// Annotations that are not numbers are ignored.
+func (e *RuleEngine) thresholdFor(rule *compiledRule, obs *Observation) float64 {
+	if rule.ThresholdAnnotation != "" {
+		annotation := AnnotationPrefix + rule.ThresholdAnnotation
+		for _, annotations := range []map[string]string{obs.Annotations, obs.NamespaceAnnotations} {
+			if value, ok := annotations[annotation]; ok {
+				if threshold, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					return threshold
+				}
+			}
+		}
+	}
+	if obs.Namespace != "" {
+		if threshold, ok := e.namespaces[obs.Namespace].Thresholds[rule.Name]; ok {
+			return threshold
+		}
+	}
+	return rule.threshold
+}
+
+// listsAlertType reports whether a comma-separated annotation value names the alert type
+func listsAlertType(value, alertType string) bool {
+	for _, item := range strings.Split(value, ",") {
+		if strings.TrimSpace(item) == alertType {
+			return true
+		}
+	}
+	return false
+}
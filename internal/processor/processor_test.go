@@ -2,16 +2,21 @@ package processor_test
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
 	"github.com/monitoring-engine/monitoring-tool/internal/models"
 	"github.com/monitoring-engine/monitoring-tool/internal/processor"
 	"github.com/monitoring-engine/monitoring-tool/internal/repository"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/datatypes"
 )
 
@@ -303,7 +308,7 @@ func TestAlertStateManager_ProcessAlert(t *testing.T) {
 
 		isNew, err := manager.ProcessAlert(ctx, alert)
 		assert.NoError(t, err)
-		assert.True(t, isNew) // Every alert is new without deduplication
+		assert.True(t, isNew) // First observation of a fingerprint is new
 
 		// Wait for event bus notification
 		time.Sleep(100 * time.Millisecond)
@@ -331,7 +336,7 @@ func TestAlertStateManager_ProcessAlert(t *testing.T) {
 
 		manager := processor.NewAlertStateManager(repo, eventBus)
 
-		// Create and process 5 alerts
+		// Create and process 5 alerts for distinct pods
 		for i := 0; i < 5; i++ {
 			alert := &models.Alert{
 				ID:          uuid.New(),
@@ -339,7 +344,7 @@ func TestAlertStateManager_ProcessAlert(t *testing.T) {
 				Severity:    "medium",
 				Message:     "Batch test",
 				Source:      "test",
-				Labels:      datatypes.JSON([]byte(`{}`)),
+				Labels:      datatypes.JSON([]byte(fmt.Sprintf(`{"pod":"pod-%d"}`, i))),
 				Value:       float64(i),
 				TriggeredAt: time.Now(),
 			}
@@ -381,13 +386,19 @@ func TestAlertStateManager_ProcessAlert(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("should create separate alerts without deduplication", func(t *testing.T) {
+	t.Run("should deduplicate alerts with the same fingerprint", func(t *testing.T) {
 		ctx := context.Background()
 		repo := repository.NewInMemoryAlertRepo()
 		eventBus := processor.NewEventBus()
+		observer := &MockObserver{}
+
+		eventBus.Subscribe(observer)
+		eventBus.Start(ctx)
+		defer eventBus.Stop()
+
 		manager := processor.NewAlertStateManager(repo, eventBus)
 
-		// Create identical alerts
+		// Process identical alerts
 		for i := 0; i < 3; i++ {
 			alert := &models.Alert{
 				ID:          uuid.New(),
@@ -396,23 +407,1179 @@ func TestAlertStateManager_ProcessAlert(t *testing.T) {
 				Message:     "Same message",
 				Source:      "same_source",
 				Labels:      datatypes.JSON([]byte(`{"key":"value"}`)),
-				Value:       100.0,
+				Value:       float64(100 + i),
 				TriggeredAt: time.Now(),
 			}
 
 			isNew, err := manager.ProcessAlert(ctx, alert)
 			assert.NoError(t, err)
-			assert.True(t, isNew) // All should be new
+			assert.Equal(t, i == 0, isNew) // Only the first is new
 		}
 
-		// All 3 should exist as separate alerts
+		time.Sleep(100 * time.Millisecond)
+
+		// Only one alert should exist, carrying the occurrence count and latest value
+		alerts, err := repo.GetRecent(ctx, 10)
+		assert.NoError(t, err)
+		assert.Len(t, alerts, 1)
+		assert.Equal(t, 3, alerts[0].OccurrenceCount)
+		assert.Equal(t, 102.0, alerts[0].Value)
+		assert.False(t, alerts[0].LastSeenAt.IsZero())
+
+		// Only the first observation should have been published
+		assert.Len(t, observer.GetReceivedEvents(), 1)
+	})
+
+	t.Run("should create a new alert once the previous one is resolved", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		eventBus := processor.NewEventBus()
+		manager := processor.NewAlertStateManager(repo, eventBus)
+
+		labels := map[string]string{"alert_type": "pod_failed", "namespace": "default", "pod": "web"}
+		first := models.NewAlert("critical", "Pod failed", "k8s_pod", 1, labels)
+		isNew, err := manager.ProcessAlert(ctx, first)
+		require.NoError(t, err)
+		assert.True(t, isNew)
+
+		first.Resolve()
+		require.NoError(t, repo.Upsert(ctx, first))
+
+		second := models.NewAlert("critical", "Pod failed", "k8s_pod", 1, labels)
+		isNew, err = manager.ProcessAlert(ctx, second)
+		require.NoError(t, err)
+		assert.True(t, isNew)
+
 		alerts, err := repo.GetRecent(ctx, 10)
 		assert.NoError(t, err)
-		assert.Len(t, alerts, 3)
+		assert.Len(t, alerts, 2)
+	})
+}
+
+// TestAlertStateManager_ResolveCleared tests automatic resolution
+func TestAlertStateManager_ResolveCleared(t *testing.T) {
+	subject := map[string]string{"namespace": "default", "pod": "web"}
+	crashLabels := map[string]string{"alert_type": "pod_crash_loop", "namespace": "default", "pod": "web", "container": "app"}
+	failedLabels := map[string]string{"alert_type": "pod_failed", "namespace": "default", "pod": "web"}
+
+	t.Run("should resolve alerts whose condition cleared", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		eventBus := processor.NewEventBus()
+		observer := &MockObserver{}
+
+		eventBus.Subscribe(observer)
+		eventBus.Start(ctx)
+		defer eventBus.Stop()
+
+		manager := processor.NewAlertStateManager(repo, eventBus)
+
+		crash := models.NewAlert("high", "Crash loop", "k8s_pod", 3, crashLabels)
+		failed := models.NewAlert("critical", "Failed", "k8s_pod", 1, failedLabels)
+		_, err := manager.ProcessAlert(ctx, crash)
+		require.NoError(t, err)
+		_, err = manager.ProcessAlert(ctx, failed)
+		require.NoError(t, err)
+
+		// Only the crash loop is still observed
+		stillActive := models.NewAlert("high", "Crash loop", "k8s_pod", 4, crashLabels)
+		resolved, err := manager.ResolveCleared(ctx, "k8s_pod", subject, []*models.Alert{stillActive})
+		require.NoError(t, err)
+		assert.Equal(t, 1, resolved)
+
+		firing, err := repo.CountByStatus(ctx, models.AlertStatusFiring)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), firing)
+
+		time.Sleep(100 * time.Millisecond)
+
+		events := observer.GetReceivedEvents()
+		require.Len(t, events, 3)
+		var resolvedEvents []*processor.AlertEvent
+		for _, event := range events {
+			if event.IsResolved() {
+				resolvedEvents = append(resolvedEvents, event)
+			}
+		}
+		require.Len(t, resolvedEvents, 1)
+		assert.Equal(t, failed.ID, resolvedEvents[0].Alert.ID)
+		assert.Equal(t, models.AlertStatusResolved, resolvedEvents[0].Alert.Status)
+		assert.NotNil(t, resolvedEvents[0].Alert.ResolvedAt)
+	})
+
+	t.Run("should resolve everything for subject when nothing is active", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		eventBus := processor.NewEventBus()
+		manager := processor.NewAlertStateManager(repo, eventBus)
+
+		_, err := manager.ProcessAlert(ctx, models.NewAlert("high", "Crash loop", "k8s_pod", 3, crashLabels))
+		require.NoError(t, err)
+		_, err = manager.ProcessAlert(ctx, models.NewAlert("critical", "Failed", "k8s_pod", 1, failedLabels))
+		require.NoError(t, err)
+
+		resolved, err := manager.ResolveCleared(ctx, "k8s_pod", subject, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, resolved)
+
+		firing, err := repo.CountByStatus(ctx, models.AlertStatusFiring)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), firing)
+	})
+
+	t.Run("should not touch other sources or subjects", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		eventBus := processor.NewEventBus()
+		manager := processor.NewAlertStateManager(repo, eventBus)
+
+		otherPod := map[string]string{"alert_type": "pod_failed", "namespace": "default", "pod": "api"}
+		_, err := manager.ProcessAlert(ctx, models.NewAlert("critical", "Failed", "k8s_pod", 1, otherPod))
+		require.NoError(t, err)
+		_, err = manager.ProcessAlert(ctx, models.NewAlert("high", "CPU", "k8s_pod_metrics", 95, map[string]string{"namespace": "default", "pod": "web", "alert_type": "pod_cpu_high"}))
+		require.NoError(t, err)
+
+		resolved, err := manager.ResolveCleared(ctx, "k8s_pod", subject, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 0, resolved)
+	})
+
+	t.Run("should fire again after resolution", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		eventBus := processor.NewEventBus()
+		manager := processor.NewAlertStateManager(repo, eventBus)
+
+		_, err := manager.ProcessAlert(ctx, models.NewAlert("critical", "Failed", "k8s_pod", 1, failedLabels))
+		require.NoError(t, err)
+		_, err = manager.ResolveCleared(ctx, "k8s_pod", subject, nil)
+		require.NoError(t, err)
+
+		isNew, err := manager.ProcessAlert(ctx, models.NewAlert("critical", "Failed", "k8s_pod", 1, failedLabels))
+		require.NoError(t, err)
+		assert.True(t, isNew)
+	})
+}
+
+// TestAlertStateManager_OperatorActions tests acknowledge, unacknowledge and manual resolve
+func TestAlertStateManager_OperatorActions(t *testing.T) {
+	labels := map[string]string{"alert_type": "node_not_ready", "node": "node-1"}
+
+	t.Run("should acknowledge, unacknowledge and resolve with audit trail", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		eventBus := processor.NewEventBus()
+		observer := &MockObserver{}
+
+		eventBus.Subscribe(observer)
+		eventBus.Start(ctx)
+		defer eventBus.Stop()
+
+		manager := processor.NewAlertStateManager(repo, eventBus)
+
+		alert := models.NewAlert("critical", "Node not ready", "k8s_node", 1, labels)
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+
+		acked, err := manager.Acknowledge(ctx, alert.ID, "alice", "investigating")
+		require.NoError(t, err)
+		assert.Equal(t, models.AlertStatusAcknowledged, acked.Status)
+		assert.Equal(t, "alice", acked.AcknowledgedBy)
+		assert.NotNil(t, acked.AcknowledgedAt)
+
+		unacked, err := manager.Unacknowledge(ctx, alert.ID, "bob", "handing back")
+		require.NoError(t, err)
+		assert.Equal(t, models.AlertStatusFiring, unacked.Status)
+		assert.Empty(t, unacked.AcknowledgedBy)
+
+		resolved, err := manager.Resolve(ctx, alert.ID, "carol", "fixed")
+		require.NoError(t, err)
+		assert.Equal(t, models.AlertStatusResolved, resolved.Status)
+		assert.Equal(t, "carol", resolved.ResolvedBy)
+
+		actions, err := repo.GetActions(ctx, alert.ID)
+		require.NoError(t, err)
+		require.Len(t, actions, 3)
+		assert.Equal(t, models.AlertActionAcknowledge, actions[0].Action)
+		assert.Equal(t, "bob", actions[1].Actor)
+		assert.Equal(t, "fixed", actions[2].Comment)
+
+		time.Sleep(100 * time.Millisecond)
+
+		// firing + acknowledged + unacknowledged + resolved
+		assert.Len(t, observer.GetReceivedEvents(), 4)
+	})
+
+	t.Run("should keep acknowledged alert deduplicated and quiet", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		eventBus := processor.NewEventBus()
+		manager := processor.NewAlertStateManager(repo, eventBus)
+
+		alert := models.NewAlert("critical", "Node not ready", "k8s_node", 1, labels)
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+		_, err = manager.Acknowledge(ctx, alert.ID, "alice", "")
+		require.NoError(t, err)
+
+		isNew, err := manager.ProcessAlert(ctx, models.NewAlert("critical", "Node not ready", "k8s_node", 1, labels))
+		require.NoError(t, err)
+		assert.False(t, isNew)
+
+		stored, err := repo.GetByID(ctx, alert.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.AlertStatusAcknowledged, stored.Status)
+		assert.Equal(t, 2, stored.OccurrenceCount)
+	})
+
+	t.Run("should reject invalid transitions", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		eventBus := processor.NewEventBus()
+		manager := processor.NewAlertStateManager(repo, eventBus)
+
+		alert := models.NewAlert("critical", "Node not ready", "k8s_node", 1, labels)
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+
+		_, err = manager.Unacknowledge(ctx, alert.ID, "alice", "")
+		assert.ErrorIs(t, err, models.ErrInvalidTransition)
+
+		_, err = manager.Resolve(ctx, alert.ID, "alice", "")
+		require.NoError(t, err)
+		_, err = manager.Acknowledge(ctx, alert.ID, "alice", "")
+		assert.ErrorIs(t, err, models.ErrInvalidTransition)
+
+		_, err = manager.Acknowledge(ctx, uuid.New(), "alice", "")
+		assert.ErrorIs(t, err, repository.ErrAlertNotFound)
+	})
+}
+
+func TestAlertStateManager_Silences(t *testing.T) {
+	labels := map[string]string{"alert_type": "pod_crash_loop", "namespace": "staging", "pod": "api-0"}
+
+	newSilence := func(startsAt, endsAt time.Time) *models.Silence {
+		return models.NewSilence(
+			[]models.Matcher{{Name: "namespace", Operator: models.MatchEqual, Value: "staging"}},
+			startsAt, endsAt, "alice", "deploy",
+		)
+	}
+
+	t.Run("should store silenced alert as suppressed without publishing", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		silenceRepo := repository.NewInMemorySilenceRepo()
+		eventBus := processor.NewEventBus()
+		observer := &MockObserver{}
+
+		eventBus.Subscribe(observer)
+		eventBus.Start(ctx)
+		defer eventBus.Stop()
+
+		silence := newSilence(time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+		require.NoError(t, silenceRepo.Create(ctx, silence))
+
+		manager := processor.NewAlertStateManager(repo, eventBus)
+		manager.AddSuppressor(processor.NewSilencer(silenceRepo))
+
+		alert := models.NewAlert("critical", "CrashLoopBackOff", "k8s_pod", 1, labels)
+		isNew, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+		assert.True(t, isNew)
+
+		stored, err := repo.GetByID(ctx, alert.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.Suppressed)
+		require.NotNil(t, stored.SilenceID)
+		assert.Equal(t, silence.ID, *stored.SilenceID)
+
+		resolved, err := manager.ResolveCleared(ctx, "k8s_pod", map[string]string{"namespace": "staging", "pod": "api-0"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resolved)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Empty(t, observer.GetReceivedEvents())
+	})
+
+	t.Run("should publish suppressed alert once the silence no longer matches", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		silenceRepo := repository.NewInMemorySilenceRepo()
+		eventBus := processor.NewEventBus()
+		observer := &MockObserver{}
+
+		eventBus.Subscribe(observer)
+		eventBus.Start(ctx)
+		defer eventBus.Stop()
+
+		silence := newSilence(time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+		require.NoError(t, silenceRepo.Create(ctx, silence))
+
+		manager := processor.NewAlertStateManager(repo, eventBus)
+		manager.AddSuppressor(processor.NewSilencer(silenceRepo))
+
+		alert := models.NewAlert("critical", "CrashLoopBackOff", "k8s_pod", 1, labels)
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+
+		require.NoError(t, silenceRepo.Delete(ctx, silence.ID))
+
+		isNew, err := manager.ProcessAlert(ctx, models.NewAlert("critical", "CrashLoopBackOff", "k8s_pod", 1, labels))
+		require.NoError(t, err)
+		assert.False(t, isNew)
+
+		stored, err := repo.GetByID(ctx, alert.ID)
+		require.NoError(t, err)
+		assert.False(t, stored.Suppressed)
+		assert.Nil(t, stored.SilenceID)
+
+		time.Sleep(100 * time.Millisecond)
+		events := observer.GetReceivedEvents()
+		require.Len(t, events, 1)
+		assert.Equal(t, processor.AlertEventFiring, events[0].Type)
+	})
+
+	t.Run("should ignore silences that are not active or do not match", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		silenceRepo := repository.NewInMemorySilenceRepo()
+		eventBus := processor.NewEventBus()
+
+		require.NoError(t, silenceRepo.Create(ctx, newSilence(time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))))
+		require.NoError(t, silenceRepo.Create(ctx, models.NewSilence(
+			[]models.Matcher{{Name: "namespace", Operator: models.MatchEqual, Value: "production"}},
+			time.Now().Add(-time.Minute), time.Now().Add(time.Hour), "alice", "",
+		)))
+
+		manager := processor.NewAlertStateManager(repo, eventBus)
+		manager.AddSuppressor(processor.NewSilencer(silenceRepo))
+
+		alert := models.NewAlert("critical", "CrashLoopBackOff", "k8s_pod", 1, labels)
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+		assert.False(t, alert.Suppressed)
+	})
+
+	t.Run("should match severity and source like routes and inhibition rules", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		silenceRepo := repository.NewInMemorySilenceRepo()
+		eventBus := processor.NewEventBus()
+
+		require.NoError(t, silenceRepo.Create(ctx, models.NewSilence(
+			[]models.Matcher{
+				{Name: "severity", Operator: models.MatchEqual, Value: "low"},
+				{Name: "source", Operator: models.MatchEqual, Value: "k8s_pod"},
+			},
+			time.Now().Add(-time.Minute), time.Now().Add(time.Hour), "alice", "",
+		)))
+
+		manager := processor.NewAlertStateManager(repo, eventBus)
+		manager.AddSuppressor(processor.NewSilencer(silenceRepo))
+
+		low := models.NewAlert("low", "Restarted", "k8s_pod", 1, map[string]string{"namespace": "staging", "pod": "api-1"})
+		_, err := manager.ProcessAlert(ctx, low)
+		require.NoError(t, err)
+		assert.True(t, low.Suppressed)
+
+		critical := models.NewAlert("critical", "CrashLoopBackOff", "k8s_pod", 1, labels)
+		_, err = manager.ProcessAlert(ctx, critical)
+		require.NoError(t, err)
+		assert.False(t, critical.Suppressed)
+	})
+}
+
+func TestAlertStateManager_Maintenance(t *testing.T) {
+	podLabels := map[string]string{"alert_type": "pod_failed", "namespace": "default", "pod": "web-0", "node": "node-1"}
+
+	t.Run("should flag alerts in an active maintenance window", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		maintenanceRepo := repository.NewInMemoryMaintenanceRepo()
+		eventBus := processor.NewEventBus()
+
+		endsAt := time.Now().Add(time.Hour)
+		window := &models.MaintenanceWindow{
+			ID:        uuid.New(),
+			Name:      "node-1 upgrade",
+			Nodes:     datatypes.NewJSONSlice([]string{"node-1"}),
+			StartsAt:  time.Now().Add(-time.Minute),
+			EndsAt:    &endsAt,
+			CreatedBy: "alice",
+		}
+		require.NoError(t, maintenanceRepo.Create(ctx, window))
+
+		manager := processor.NewAlertStateManager(repo, eventBus)
+		manager.AddSuppressor(processor.NewMaintenanceSuppressor(maintenanceRepo))
+
+		alert := models.NewAlert("critical", "Pod failed", "k8s_pod", 1, podLabels)
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+
+		stored, err := repo.GetByID(ctx, alert.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.Suppressed)
+		assert.True(t, stored.Maintenance)
+		require.NotNil(t, stored.MaintenanceID)
+		assert.Equal(t, window.ID, *stored.MaintenanceID)
+
+		other := models.NewAlert("critical", "Pod failed", "k8s_pod", 1, map[string]string{"alert_type": "pod_failed", "namespace": "default", "pod": "web-1", "node": "node-2"})
+		_, err = manager.ProcessAlert(ctx, other)
+		require.NoError(t, err)
+		assert.False(t, other.Maintenance)
+	})
+
+	t.Run("should treat cordoned nodes as implicit maintenance", func(t *testing.T) {
+		ctx := context.Background()
+		repo := repository.NewInMemoryAlertRepo()
+		eventBus := processor.NewEventBus()
+		maintenance := processor.NewMaintenanceSuppressor(repository.NewInMemoryMaintenanceRepo())
+
+		manager := processor.NewAlertStateManager(repo, eventBus)
+		manager.AddSuppressor(maintenance)
+
+		maintenance.SetNodeCordoned("node-1", true)
+		assert.True(t, maintenance.IsNodeCordoned("node-1"))
+
+		alert := models.NewAlert("critical", "Pod failed", "k8s_pod", 1, podLabels)
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+		assert.True(t, alert.Maintenance)
+		assert.Nil(t, alert.MaintenanceID)
+
+		maintenance.SetNodeCordoned("node-1", false)
+		_, err = manager.ProcessAlert(ctx, models.NewAlert("critical", "Pod failed", "k8s_pod", 1, podLabels))
+		require.NoError(t, err)
+
+		stored, err := repo.GetByID(ctx, alert.ID)
+		require.NoError(t, err)
+		assert.False(t, stored.Maintenance)
+		assert.False(t, stored.Suppressed)
+	})
+}
+
+// activeQueryRepo records the queries for active alerts by labels and fails loading all of them
+type activeQueryRepo struct {
+	repository.AlertRepo
+	t       *testing.T
+	sources []string
+	queries []map[string]string
+}
+
+func (r *activeQueryRepo) GetActive(ctx context.Context) ([]*models.Alert, error) {
+	r.t.Error("loaded every active alert")
+	return r.AlertRepo.GetActive(ctx)
+}
+
+func (r *activeQueryRepo) GetActiveByLabels(ctx context.Context, source string, labels map[string]string) ([]*models.Alert, error) {
+	r.sources = append(r.sources, source)
+	r.queries = append(r.queries, labels)
+	return r.AlertRepo.GetActiveByLabels(ctx, source, labels)
+}
+
+func TestInhibitor_LoadsOnlyMatchingSources(t *testing.T) {
+	ctx := context.Background()
+	repo := &activeQueryRepo{AlertRepo: repository.NewInMemoryAlertRepo(), t: t}
+
+	inhibitor, err := processor.NewInhibitor(repo, []config.InhibitRuleConfig{{
+		SourceMatchers: []string{"alert_type=node_not_ready", "source=k8s_node", "severity=critical"},
+		TargetMatchers: []string{"alert_type=pod_unknown"},
+		Equal:          []string{"node"},
+	}})
+	require.NoError(t, err)
+
+	source := models.NewAlert("critical", "Node not ready", "k8s_node", 1, map[string]string{"alert_type": "node_not_ready", "node": "node-1"})
+	require.NoError(t, repo.Create(ctx, source))
+
+	target := models.NewAlert("high", "Pod unknown", "k8s_pod", 1, map[string]string{"alert_type": "pod_unknown", "pod": "web-0", "node": "node-1"})
+	inhibited, err := inhibitor.Suppress(ctx, target)
+	require.NoError(t, err)
+	assert.True(t, inhibited)
+
+	assert.Equal(t, []string{"k8s_node"}, repo.sources)
+	assert.Equal(t, []map[string]string{{"alert_type": "node_not_ready", "node": "node-1"}}, repo.queries)
+}
+
+func TestAlertStateManager_Inhibition(t *testing.T) {
+	rules := []config.InhibitRuleConfig{{
+		SourceMatchers: []string{"alert_type=node_not_ready"},
+		TargetMatchers: []string{"alert_type=~pod_unknown|pod_failed"},
+		Equal:          []string{"node"},
+	}}
+	nodeLabels := map[string]string{"alert_type": "node_not_ready", "node": "node-1"}
+	podLabels := map[string]string{"alert_type": "pod_unknown", "namespace": "default", "pod": "web-0", "node": "node-1"}
+
+	newManager := func(t *testing.T, ctx context.Context) (*processor.AlertStateManager, repository.AlertRepo, *MockObserver) {
+		repo := repository.NewInMemoryAlertRepo()
+		eventBus := processor.NewEventBus()
+		observer := &MockObserver{}
+		eventBus.Subscribe(observer)
+		eventBus.Start(ctx)
+		t.Cleanup(eventBus.Stop)
+
+		inhibitor, err := processor.NewInhibitor(repo, rules)
+		require.NoError(t, err)
+		manager := processor.NewAlertStateManager(repo, eventBus)
+		manager.AddSuppressor(inhibitor)
+		return manager, repo, observer
+	}
+
+	t.Run("should store pod alerts on a not ready node as inhibited without publishing", func(t *testing.T) {
+		ctx := context.Background()
+		manager, repo, observer := newManager(t, ctx)
+
+		source := models.NewAlert("critical", "Node not ready", "k8s_node", 1, nodeLabels)
+		_, err := manager.ProcessAlert(ctx, source)
+		require.NoError(t, err)
+		assert.False(t, source.Suppressed)
+
+		target := models.NewAlert("high", "Pod unknown", "k8s_pod", 1, podLabels)
+		_, err = manager.ProcessAlert(ctx, target)
+		require.NoError(t, err)
+
+		stored, err := repo.GetByID(ctx, target.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.Suppressed)
+		assert.True(t, stored.Inhibited)
+		require.NotNil(t, stored.InhibitedBy)
+		assert.Equal(t, source.ID, *stored.InhibitedBy)
+
+		otherNode := models.NewAlert("high", "Pod unknown", "k8s_pod", 1, map[string]string{"alert_type": "pod_unknown", "namespace": "default", "pod": "web-1", "node": "node-2"})
+		_, err = manager.ProcessAlert(ctx, otherNode)
+		require.NoError(t, err)
+		assert.False(t, otherNode.Inhibited)
+
+		crashLoop := models.NewAlert("high", "CrashLoopBackOff", "k8s_pod", 1, map[string]string{"alert_type": "pod_crash_loop", "namespace": "default", "pod": "web-2", "node": "node-1"})
+		_, err = manager.ProcessAlert(ctx, crashLoop)
+		require.NoError(t, err)
+		assert.False(t, crashLoop.Inhibited)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Len(t, observer.GetReceivedEvents(), 3)
+	})
+
+	t.Run("should publish inhibited alerts once the source alert resolves", func(t *testing.T) {
+		ctx := context.Background()
+		manager, repo, observer := newManager(t, ctx)
+
+		source := models.NewAlert("critical", "Node not ready", "k8s_node", 1, nodeLabels)
+		_, err := manager.ProcessAlert(ctx, source)
+		require.NoError(t, err)
+		target := models.NewAlert("high", "Pod unknown", "k8s_pod", 1, podLabels)
+		_, err = manager.ProcessAlert(ctx, target)
+		require.NoError(t, err)
+
+		resolved, err := manager.ResolveCleared(ctx, "k8s_node", map[string]string{"node": "node-1"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resolved)
+
+		stored, err := repo.GetByID(ctx, target.ID)
+		require.NoError(t, err)
+		assert.False(t, stored.Suppressed)
+		assert.False(t, stored.Inhibited)
+		assert.Nil(t, stored.InhibitedBy)
+
+		time.Sleep(100 * time.Millisecond)
+		var targetEvents []*processor.AlertEvent
+		for _, event := range observer.GetReceivedEvents() {
+			if event.Alert.ID == target.ID {
+				targetEvents = append(targetEvents, event)
+			}
+		}
+		require.Len(t, targetEvents, 1)
+		assert.Equal(t, processor.AlertEventFiring, targetEvents[0].Type)
+	})
+
+	t.Run("should reject rules without matchers or with invalid matchers", func(t *testing.T) {
+		repo := repository.NewInMemoryAlertRepo()
+
+		_, err := processor.NewInhibitor(repo, []config.InhibitRuleConfig{{SourceMatchers: []string{"alert_type=node_not_ready"}}})
+		assert.Error(t, err)
+
+		_, err = processor.NewInhibitor(repo, []config.InhibitRuleConfig{{SourceMatchers: []string{"alert_type=~("}, TargetMatchers: []string{"alert_type=pod_unknown"}}})
+		assert.Error(t, err)
+	})
+}
+
+// flakyObserver fails its first failures deliveries and accepts only the events in accepts, if set
+type flakyObserver struct {
+	MockObserver
+	failures int32
+	accepts  []processor.AlertEventType
+}
+
+func (f *flakyObserver) OnAlert(ctx context.Context, event *processor.AlertEvent) error {
+	f.MockObserver.OnAlert(ctx, event)
+	if f.GetCallCount() <= atomic.LoadInt32(&f.failures) {
+		return assert.AnError
+	}
+	return nil
+}
+
+func (f *flakyObserver) Accepts(event *processor.AlertEvent) bool {
+	if len(f.accepts) == 0 {
+		return true
+	}
+	for _, eventType := range f.accepts {
+		if event.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// batchingObserver batches every event into one batch due at dueAt, failing the first failures batches
+type batchingObserver struct {
+	mu       sync.Mutex
+	dueAt    time.Time
+	failures int
+	batches  [][]*processor.AlertEvent
+}
+
+func (b *batchingObserver) OnAlert(ctx context.Context, event *processor.AlertEvent) error {
+	return b.NotifyBatch(ctx, []*processor.AlertEvent{event})
+}
+
+func (b *batchingObserver) Batch(event *processor.AlertEvent, now time.Time) (string, time.Time) {
+	return "batch", b.dueAt
+}
+
+func (b *batchingObserver) NotifyBatch(ctx context.Context, events []*processor.AlertEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures > 0 {
+		b.failures--
+		return assert.AnError
+	}
+	b.batches = append(b.batches, events)
+	return nil
+}
+
+func (b *batchingObserver) sent() [][]*processor.AlertEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([][]*processor.AlertEvent(nil), b.batches...)
+}
+
+func TestOutbox(t *testing.T) {
+	deliveryCfg := config.DeliveryConfig{
+		PollInterval:   10 * time.Millisecond,
+		Timeout:        time.Second,
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+	}
+
+	newManager := func(t *testing.T) (*processor.AlertStateManager, *processor.Outbox, repository.AlertRepo, repository.NotificationRepo) {
+		repo := repository.NewInMemoryAlertRepo()
+		notifications := repository.NewInMemoryNotificationRepo()
+		eventBus := processor.NewEventBus()
+		eventBus.Start(context.Background())
+		t.Cleanup(eventBus.Stop)
+
+		outbox := processor.NewOutbox(repository.NewInMemoryTransactor(repo, notifications), notifications, repo, deliveryCfg)
+		manager := processor.NewAlertStateManager(repo, eventBus)
+		manager.UseOutbox(outbox)
+		return manager, outbox, repo, notifications
+	}
+
+	t.Run("should queue a notification per target with the new alert", func(t *testing.T) {
+		ctx := context.Background()
+		manager, outbox, _, notifications := newManager(t)
+		outbox.Register(models.ChannelTypeEmail, "", &flakyObserver{})
+		outbox.Register(models.ChannelTypePagerDuty, "", &flakyObserver{accepts: []processor.AlertEventType{processor.AlertEventResolved}})
+
+		alert := models.NewAlert("critical", "Pod crashed", "k8s_pod", 1, map[string]string{"pod": "web-0"})
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+
+		queued, err := notifications.GetByAlert(ctx, alert.ID)
+		require.NoError(t, err)
+		require.Len(t, queued, 1, "events a target does not accept are not queued")
+		assert.Equal(t, models.ChannelTypeEmail, queued[0].Channel)
+		assert.Equal(t, string(processor.AlertEventFiring), queued[0].Event)
+		assert.True(t, queued[0].IsQueued())
+	})
+
+	t.Run("should retry failed deliveries until they succeed", func(t *testing.T) {
+		ctx := context.Background()
+		manager, outbox, _, notifications := newManager(t)
+		observer := &flakyObserver{failures: 2}
+		outbox.Register(models.ChannelTypeSlack, "", observer)
+		outbox.Start(ctx)
+		defer outbox.Stop()
+
+		alert := models.NewAlert("high", "Node pressure", "k8s_node", 1, map[string]string{"node": "node-1"})
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			delivered, err := notifications.GetByAlert(ctx, alert.ID)
+			return err == nil && len(delivered) == 1 && delivered[0].State == models.NotificationStateSuccess
+		}, 2*time.Second, 10*time.Millisecond)
+
+		delivered, err := notifications.GetByAlert(ctx, alert.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, delivered[0].Attempts)
+		assert.Nil(t, delivered[0].NextAttemptAt)
+		assert.Equal(t, alert.ID, observer.GetReceivedEvents()[0].Alert.ID)
+	})
+
+	t.Run("should retry a failing receiver of a channel on its own", func(t *testing.T) {
+		ctx := context.Background()
+		manager, outbox, _, notifications := newManager(t)
+		healthy, failing := &flakyObserver{}, &flakyObserver{failures: 1}
+		outbox.Register(models.ChannelTypeWebhook, "statuspage", healthy)
+		outbox.Register(models.ChannelTypeWebhook, "ticketing", failing)
+		outbox.Start(ctx)
+		defer outbox.Stop()
+
+		alert := models.NewAlert("high", "Node pressure", "k8s_node", 1, map[string]string{"node": "node-3"})
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			delivered, err := notifications.GetByAlert(ctx, alert.ID)
+			if err != nil || len(delivered) != 2 {
+				return false
+			}
+			return delivered[0].State == models.NotificationStateSuccess && delivered[1].State == models.NotificationStateSuccess
+		}, 2*time.Second, 10*time.Millisecond)
+
+		assert.Equal(t, int32(1), healthy.GetCallCount())
+		assert.Equal(t, int32(2), failing.GetCallCount())
+	})
+
+	t.Run("should keep batched notifications queued until their batch is sent", func(t *testing.T) {
+		ctx := context.Background()
+		manager, outbox, _, notifications := newManager(t)
+		batcher := &batchingObserver{dueAt: time.Now().Add(100 * time.Millisecond), failures: 1}
+		outbox.Register(models.ChannelTypeEmail, "", batcher)
+		outbox.Start(ctx)
+		defer outbox.Stop()
+
+		first := models.NewAlert("high", "Pod crashed", "k8s_pod", 1, map[string]string{"pod": "api-0"})
+		second := models.NewAlert("medium", "Pod pending", "k8s_pod", 1, map[string]string{"pod": "api-1"})
+		_, err := manager.ProcessAlert(ctx, first)
+		require.NoError(t, err)
+		_, err = manager.ProcessAlert(ctx, second)
+		require.NoError(t, err)
+		_, err = manager.Resolve(ctx, first.ID, "alice", "")
+		require.NoError(t, err)
+
+		queued, err := notifications.GetByAlert(ctx, first.ID)
+		require.NoError(t, err)
+		require.Len(t, queued, 2)
+		assert.Equal(t, "batch", queued[0].GroupKey)
+		assert.True(t, queued[0].IsQueued())
+
+		require.Eventually(t, func() bool { return len(batcher.sent()) == 1 }, 2*time.Second, 10*time.Millisecond)
+		batch := batcher.sent()[0]
+		require.Len(t, batch, 3, "the failed batch is retried as a whole")
+		assert.Equal(t, processor.AlertEventResolved, batch[2].Type)
+
+		require.Eventually(t, func() bool {
+			delivered, err := notifications.GetByAlert(ctx, first.ID)
+			return err == nil && delivered[0].State == models.NotificationStateSuccess && delivered[1].State == models.NotificationStateSuccess
+		}, time.Second, 10*time.Millisecond)
+		delivered, err := notifications.GetByAlert(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, delivered[0].Attempts)
+	})
+
+	t.Run("should dead-letter notifications after the last attempt", func(t *testing.T) {
+		ctx := context.Background()
+		manager, outbox, _, notifications := newManager(t)
+		observer := &flakyObserver{failures: 100}
+		outbox.Register(models.ChannelTypeWebhook, "", observer)
+		outbox.Start(ctx)
+		defer outbox.Stop()
+
+		alert := models.NewAlert("medium", "Disk filling", "k8s_node", 1, map[string]string{"node": "node-2"})
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			dead, err := notifications.List(ctx, models.NotificationStateDead, 10)
+			return err == nil && len(dead) == 1
+		}, 2*time.Second, 10*time.Millisecond)
+
+		dead, err := notifications.List(ctx, models.NotificationStateDead, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 3, dead[0].Attempts)
+		assert.Equal(t, assert.AnError.Error(), dead[0].Error)
+		assert.Equal(t, int32(3), observer.GetCallCount())
+	})
+
+	t.Run("should deliver notifications queued before a restart in order", func(t *testing.T) {
+		ctx := context.Background()
+		manager, outbox, repo, notifications := newManager(t)
+		outbox.Register(models.ChannelTypeEmail, "", &flakyObserver{})
+
+		// The outbox is never started, as if the process stopped before delivering
+		alert := models.NewAlert("high", "Pod crashed", "k8s_pod", 1, map[string]string{"pod": "api-0"})
+		_, err := manager.ProcessAlert(ctx, alert)
+		require.NoError(t, err)
+		_, err = manager.Resolve(ctx, alert.ID, "alice", "fixed")
+		require.NoError(t, err)
+
+		// A new outbox, as after a restart
+		restarted := processor.NewOutbox(repository.NewInMemoryTransactor(repo, notifications), notifications, repo, deliveryCfg)
+		observer := &flakyObserver{failures: 1}
+		restarted.Register(models.ChannelTypeEmail, "", observer)
+		restarted.Start(ctx)
+		defer restarted.Stop()
+
+		require.Eventually(t, func() bool {
+			return len(observer.GetReceivedEvents()) == 3
+		}, 2*time.Second, 10*time.Millisecond)
+
+		events := observer.GetReceivedEvents()
+		assert.Equal(t, processor.AlertEventFiring, events[0].Type)
+		assert.Equal(t, processor.AlertEventFiring, events[1].Type, "the failed firing notification is retried before the resolve is sent")
+		assert.Equal(t, processor.AlertEventResolved, events[2].Type)
+
+		// Each notification reports the alert as it was at its event
+		assert.Equal(t, models.AlertStatusFiring, events[1].Alert.Status)
+		assert.Nil(t, events[1].Alert.ResolvedAt)
+		assert.Equal(t, models.AlertStatusResolved, events[2].Alert.Status)
+		assert.Equal(t, "alice", events[2].Alert.ResolvedBy)
 	})
 }
 
 // TestEventBus_ConcurrentPublish tests concurrent publishing
+func TestRuleEngine(t *testing.T) {
+	podObservation := func(samples ...processor.Sample) *processor.Observation {
+		return &processor.Observation{
+			Source:    "k8s_pod",
+			Namespace: "production",
+			Pod:       "api-0",
+			Node:      "worker-1",
+			Labels:    map[string]string{"app": "api"},
+			Samples:   samples,
+		}
+	}
+
+	t.Run("should reject invalid rules", func(t *testing.T) {
+		invalid := []processor.Rule{
+			{Name: "no_signal", Operator: processor.OpEqual, Severity: "high", Message: "m"},
+			{Name: "bad_op", Signal: processor.SignalRestartCount, Operator: "~", Severity: "high", Message: "m"},
+			{Name: "bad_value", Signal: processor.SignalRestartCount, Operator: processor.OpGreater, Value: "many", Severity: "high", Message: "m"},
+			{Name: "bad_severity", Signal: processor.SignalRestartCount, Operator: processor.OpGreater, Value: "1", Severity: "urgent", Message: "m"},
+			{Name: "no_object", Signal: processor.SignalCPUPercent, Operator: processor.OpGreater, Value: "1", Severity: "high", Message: "m"},
+			{Name: "bad_template", Signal: processor.SignalRestartCount, Operator: processor.OpGreater, Value: "1", Severity: "high", Message: "{{.Pod"},
+		}
+		for _, rule := range invalid {
+			_, err := processor.NewRuleEngine([]processor.Rule{rule})
+			assert.Error(t, err, rule.Name)
+		}
+	})
+
+	t.Run("should render message and labels from the matching sample", func(t *testing.T) {
+		engine, err := processor.NewRuleEngine([]processor.Rule{{
+			Name:     "container_error",
+			Signal:   processor.SignalContainerTerminatedReason,
+			Operator: processor.OpNotIn,
+			Values:   []string{"Completed", "OOMKilled"},
+			Severity: "medium",
+			Message:  "{{.Namespace}}/{{.Pod}} {{.Container}} exited: {{.Text}} ({{.Message}})",
+		}})
+		require.NoError(t, err)
+
+		alerts := engine.Evaluate(podObservation(
+			processor.Sample{Signal: processor.SignalContainerTerminatedReason, Text: "Completed", Labels: map[string]string{"container": "init"}},
+			processor.Sample{Signal: processor.SignalContainerTerminatedReason, Text: "Error", Value: 2, Message: "exit 1", Labels: map[string]string{"container": "app"}},
+		))
+		require.Len(t, alerts, 1)
+
+		labels := alerts[0].GetLabelsMap()
+		assert.Equal(t, "production/api-0 app exited: Error (exit 1)", alerts[0].Message)
+		assert.Equal(t, "medium", alerts[0].Severity)
+		assert.Equal(t, "k8s_pod", alerts[0].Source)
+		assert.Equal(t, 2.0, alerts[0].Value)
+		assert.Equal(t, "container_error", labels["alert_type"])
+		assert.Equal(t, "app", labels["container"])
+		assert.Equal(t, "worker-1", labels["node"])
+	})
+
+	t.Run("should scope rules by object, namespace and selector", func(t *testing.T) {
+		engine, err := processor.NewRuleEngine([]processor.Rule{
+			{
+				Name: "prod_restarts", Signal: processor.SignalRestartCount, Operator: processor.OpGreaterEqual, Value: "1",
+				Severity: "high", Namespaces: []string{"production"}, Message: "restarts",
+			},
+			{
+				Name: "worker_restarts", Signal: processor.SignalRestartCount, Operator: processor.OpGreaterEqual, Value: "1",
+				Severity: "high", Selector: map[string]string{"app": "worker"}, Message: "restarts",
+			},
+			{
+				Name: "node_cpu", Object: processor.ObjectNode, Signal: processor.SignalCPUPercent, Operator: processor.OpGreater, Value: "1",
+				Severity: "high", Message: "cpu",
+			},
+		})
+		require.NoError(t, err)
+
+		alerts := engine.Evaluate(podObservation(
+			processor.Sample{Signal: processor.SignalRestartCount, Value: 4},
+			processor.Sample{Signal: processor.SignalCPUPercent, Value: 99},
+		))
+		require.Len(t, alerts, 1)
+		assert.Equal(t, "prod_restarts", alerts[0].GetLabelsMap()["alert_type"])
+	})
+
+	t.Run("should wait for the condition to hold for the rule duration", func(t *testing.T) {
+		engine, err := processor.NewRuleEngine([]processor.Rule{{
+			Name: "not_ready", Signal: processor.SignalNodeCondition, Key: "Ready", Operator: processor.OpNotEqual, Value: "True",
+			For: 2 * time.Minute, Severity: "critical", Message: "{{.Node}} not ready",
+		}})
+		require.NoError(t, err)
+
+		node := func(since time.Time) *processor.Observation {
+			return &processor.Observation{
+				Source: "k8s_node",
+				Node:   "worker-1",
+				Samples: []processor.Sample{
+					{Signal: processor.SignalNodeCondition, Key: "Ready", Text: "False", Since: since},
+				},
+			}
+		}
+
+		assert.Empty(t, engine.Evaluate(node(time.Now().Add(-time.Minute))))
+		assert.Empty(t, engine.Evaluate(node(time.Time{})))
+		alerts := engine.Evaluate(node(time.Now().Add(-3 * time.Minute)))
+		require.Len(t, alerts, 1)
+		assert.Equal(t, "worker-1 not ready", alerts[0].Message)
+	})
+}
+
+func TestRuleEngine_Pending(t *testing.T) {
+	engine, err := processor.NewRuleEngine([]processor.Rule{{
+		Name: "pod_cpu_high", Object: processor.ObjectPod, Signal: processor.SignalCPUPercent, Operator: processor.OpGreater, Value: "80",
+		For: 50 * time.Millisecond, Severity: "high", Message: "{{.Pod}} CPU {{.Value}}",
+	}})
+	require.NoError(t, err)
+
+	observe := func(cpu float64) []*models.Alert {
+		return engine.Evaluate(&processor.Observation{
+			Source:    "k8s_pod_metrics",
+			Namespace: "production",
+			Pod:       "api-0",
+			Samples:   []processor.Sample{{Signal: processor.SignalCPUPercent, Value: cpu}},
+		})
+	}
+
+	t.Run("should hold the alert as pending until the condition has held long enough", func(t *testing.T) {
+		assert.Empty(t, observe(95))
+
+		pending := engine.Pending()
+		require.Len(t, pending, 1)
+		assert.Equal(t, "pod_cpu_high", pending[0].Rule)
+		assert.Equal(t, "api-0", pending[0].Labels["pod"])
+		assert.Equal(t, 50*time.Millisecond, pending[0].FiresAt.Sub(pending[0].ActiveSince))
+
+		time.Sleep(60 * time.Millisecond)
+		alerts := observe(97)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, pending[0].Fingerprint, alerts[0].Fingerprint)
+		assert.Empty(t, engine.Pending(), "a firing alert is no longer pending")
+
+		// Still firing on the next observation without waiting again
+		assert.Len(t, observe(96), 1)
+	})
+
+	t.Run("should start over when one observation is below threshold", func(t *testing.T) {
+		assert.Empty(t, observe(50))
+		assert.Empty(t, engine.Pending())
+
+		assert.Empty(t, observe(95))
+		assert.Len(t, engine.Pending(), 1)
+	})
+
+	t.Run("should forget pending alerts of deleted objects", func(t *testing.T) {
+		engine.Forget("k8s_pod_metrics", map[string]string{"namespace": "production", "pod": "api-0"})
+		assert.Empty(t, engine.Pending())
+	})
+
+	t.Run("should apply configured durations to built-in rules", func(t *testing.T) {
+		rules := processor.DefaultRules(config.AlertRulesConfig{
+			PodRestartThreshold: 3,
+			For:                 map[string]time.Duration{"pod_cpu_high": 3 * time.Minute, "pod_pending": time.Minute},
+		})
+		for _, rule := range rules {
+			switch rule.Name {
+			case "pod_cpu_high":
+				assert.Equal(t, 3*time.Minute, rule.For)
+			case "pod_pending":
+				assert.Equal(t, time.Minute, rule.For)
+			case "pod_memory_high":
+				assert.Zero(t, rule.For)
+			}
+		}
+	})
+}
+
+func TestRuleEngine_Overrides(t *testing.T) {
+	engine, err := processor.NewRuleEngine(processor.DefaultRules(config.AlertRulesConfig{
+		PodRestartThreshold: 3,
+		PodCPUPercent:       80,
+		PodMemoryPercent:    85,
+	}))
+	require.NoError(t, err)
+	engine.SetNamespaceOverrides(map[string]config.NamespaceAlertRules{
+		"batch":   {Thresholds: map[string]float64{"pod_cpu_high": 100}},
+		"staging": {Disabled: []string{"pod_restart_threshold"}},
+	})
+
+	observe := func(namespace string, annotations, namespaceAnnotations map[string]string) []*models.Alert {
+		return engine.Evaluate(&processor.Observation{
+			Source:               "k8s_pod_metrics",
+			Namespace:            namespace,
+			Pod:                  "worker-0",
+			Annotations:          annotations,
+			NamespaceAnnotations: namespaceAnnotations,
+			Samples: []processor.Sample{
+				{Signal: processor.SignalCPUPercent, Value: 90, Labels: map[string]string{"metric": "cpu"}},
+				{Signal: processor.SignalRestartCount, Value: 5},
+			},
+		})
+	}
+	alertTypes := func(alerts []*models.Alert) []string {
+		var types []string
+		for _, alert := range alerts {
+			types = append(types, alert.GetLabelsMap()["alert_type"])
+		}
+		return types
+	}
+
+	t.Run("should use the global threshold without overrides", func(t *testing.T) {
+		alerts := observe("default", nil, nil)
+		assert.ElementsMatch(t, []string{"pod_cpu_high", "pod_restart_threshold"}, alertTypes(alerts))
+	})
+
+	t.Run("should use the namespace configuration over the global threshold", func(t *testing.T) {
+		alerts := observe("batch", nil, nil)
+		assert.Equal(t, []string{"pod_restart_threshold"}, alertTypes(alerts))
+	})
+
+	t.Run("should use the namespace annotation over the namespace configuration", func(t *testing.T) {
+		alerts := observe("batch", nil, map[string]string{"monitoring-tool/cpu-threshold": "70"})
+		require.Contains(t, alertTypes(alerts), "pod_cpu_high")
+		for _, alert := range alerts {
+			if alert.GetLabelsMap()["alert_type"] == "pod_cpu_high" {
+				assert.Contains(t, alert.Message, "(threshold: 70.0%)")
+			}
+		}
+	})
+
+	t.Run("should use the pod annotation over the namespace annotation", func(t *testing.T) {
+		alerts := observe("default",
+			map[string]string{"monitoring-tool/cpu-threshold": "95", "monitoring-tool/restart-threshold": "10"},
+			map[string]string{"monitoring-tool/cpu-threshold": "70"})
+		assert.Empty(t, alerts)
+	})
+
+	t.Run("should ignore annotations that are not numbers", func(t *testing.T) {
+		alerts := observe("default", map[string]string{"monitoring-tool/cpu-threshold": "high"}, nil)
+		assert.Contains(t, alertTypes(alerts), "pod_cpu_high")
+	})
+
+	t.Run("should disable alert types by annotation or namespace configuration", func(t *testing.T) {
+		alerts := observe("default", map[string]string{"monitoring-tool/disabled-alerts": "pod_cpu_high, pod_oom_killed"}, nil)
+		assert.Equal(t, []string{"pod_restart_threshold"}, alertTypes(alerts))
+
+		alerts = observe("default", nil, map[string]string{"monitoring-tool/disabled-alerts": "pod_restart_threshold"})
+		assert.Equal(t, []string{"pod_cpu_high"}, alertTypes(alerts))
+
+		alerts = observe("staging", nil, nil)
+		assert.Equal(t, []string{"pod_cpu_high"}, alertTypes(alerts))
+	})
+}
+
+func TestRules_MergeAndLoad(t *testing.T) {
+	defaults := []processor.Rule{
+		{Name: "a", Severity: "high"},
+		{Name: "b", Severity: "high"},
+		{Name: "c", Severity: "high"},
+	}
+
+	t.Run("should override, disable and append rules by name", func(t *testing.T) {
+		merged := processor.MergeRules(defaults, []processor.Rule{
+			{Name: "b", Severity: "low"},
+			{Name: "c", Disabled: true},
+			{Name: "d", Severity: "medium"},
+		})
+
+		require.Len(t, merged, 3)
+		assert.Equal(t, "a", merged[0].Name)
+		assert.Equal(t, "b", merged[1].Name)
+		assert.Equal(t, "low", merged[1].Severity)
+		assert.Equal(t, "d", merged[2].Name)
+		assert.Len(t, defaults, 3, "defaults must not be modified")
+		assert.Equal(t, "high", defaults[1].Severity)
+	})
+
+	t.Run("should only override the fields a rule sets", func(t *testing.T) {
+		merged := processor.MergeRules(processor.DefaultRules(config.AlertRulesConfig{}), []processor.Rule{
+			{Name: "pod_cpu_high", Severity: "critical"},
+			{Name: "pod_pending", For: 15 * time.Minute, Namespaces: []string{"batch"}},
+		})
+
+		byName := make(map[string]processor.Rule, len(merged))
+		for _, rule := range merged {
+			byName[rule.Name] = rule
+		}
+		assert.Equal(t, "critical", byName["pod_cpu_high"].Severity)
+		assert.Equal(t, processor.SignalCPUPercent, byName["pod_cpu_high"].Signal)
+		assert.Equal(t, "cpu-threshold", byName["pod_cpu_high"].ThresholdAnnotation)
+		assert.Equal(t, 15*time.Minute, byName["pod_pending"].For)
+		assert.Equal(t, []string{"batch"}, byName["pod_pending"].Namespaces)
+		assert.NotEmpty(t, byName["pod_pending"].Message)
+
+		_, err := processor.NewRuleEngine(merged)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should load rules from YAML", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "rules.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+rules:
+  - name: pod_pending
+    signal: pod_phase
+    op: "=="
+    value: Pending
+    for: 10m
+    severity: low
+    namespaces: [batch]
+    message: "{{.Pod}} pending"
+  - name: pod_unknown
+    disabled: true
+`), 0o600))
+
+		rules, err := processor.LoadRulesFile(path)
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		assert.Equal(t, 10*time.Minute, rules[0].For)
+		assert.Equal(t, []string{"batch"}, rules[0].Namespaces)
+		assert.True(t, rules[1].Disabled)
+
+		_, err = processor.NewRuleEngine(rules[:1])
+		assert.NoError(t, err)
+	})
+
+	t.Run("should accept the example rules file", func(t *testing.T) {
+		rules, err := processor.LoadRulesFile("../../configs/alert_rules.example.yaml")
+		require.NoError(t, err)
+
+		_, err = processor.NewRuleEngine(processor.MergeRules(processor.DefaultRules(config.AlertRulesConfig{}), rules))
+		assert.NoError(t, err)
+	})
+
+	t.Run("should fail for a missing file", func(t *testing.T) {
+		_, err := processor.LoadRulesFile(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Error(t, err)
+	})
+}
+
 func TestEventBus_ConcurrentPublish(t *testing.T) {
 	t.Run("should handle concurrent publishes", func(t *testing.T) {
 		ctx := context.Background()
@@ -0,0 +1,329 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+)
+
+// RuleEngine evaluates declarative rules against watcher observations.
+// Matches of rules with a "for" duration are held as pending, in memory, until the
+// condition has held for that long across consecutive observations.
+type RuleEngine struct {
+	rules   []*compiledRule
+	now     func() time.Time
+	pending map[string]map[string]*pendingEntry // subject key -> fingerprint -> entry
+	// namespaces holds per-namespace overrides from configuration
+	namespaces map[string]config.NamespaceAlertRules
+	mu         sync.Mutex
+}
+
+// PendingAlert is an alert whose condition holds but not yet for its rule's duration
+type PendingAlert struct {
+	Rule        string            `json:"rule"`
+	Fingerprint string            `json:"fingerprint"`
+	Severity    string            `json:"severity"`
+	Message     string            `json:"message"`
+	Source      string            `json:"source"`
+	Value       float64           `json:"value"`
+	Labels      map[string]string `json:"labels"`
+	ActiveSince time.Time         `json:"active_since"`
+	FiresAt     time.Time         `json:"fires_at"`
+}
+
+type pendingEntry struct {
+	alert       *models.Alert
+	rule        string
+	activeSince time.Time
+	firesAt     time.Time
+	firing      bool
+}
+
+type compiledRule struct {
+	Rule
+	threshold float64
+	message   *template.Template
+}
+
+// ruleTemplateData is the data available to rule message templates
+type ruleTemplateData struct {
+	Rule         string
+	Namespace    string
+	Pod          string
+	Node         string
+	Container    string
+	WorkloadKind string
+	Workload     string
+	PVC          string
+	Key          string
+	Text         string
+	Value        float64
+	Threshold    float64
+	Reason       string
+	Message      string
+}
+
+// NewRuleEngine validates and compiles the rules
+func NewRuleEngine(rules []Rule) (*RuleEngine, error) {
+	engine := &RuleEngine{
+		now:     time.Now,
+		pending: make(map[string]map[string]*pendingEntry),
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		message, err := template.New(rule.Name).Option("missingkey=zero").Parse(rule.Message)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid message template: %w", rule.Name, err)
+		}
+		engine.rules = append(engine.rules, &compiledRule{
+			Rule:      rule,
+			threshold: rule.Threshold(),
+			message:   message,
+		})
+	}
+	return engine, nil
+}
+
+// Rules returns the rules the engine evaluates
+func (e *RuleEngine) Rules() []Rule {
+	rules := make([]Rule, len(e.rules))
+	for i, rule := range e.rules {
+		rules[i] = rule.Rule
+	}
+	return rules
+}
+
+// Evaluate returns an alert for every rule and sample of the observation that matches.
+// A match of a rule with a "for" duration is only returned once the condition has held
+// that long; until then it is pending. The condition's start is taken from the sample
+// when Kubernetes reports it, otherwise from the first observation it was seen in.
+// Each observation replaces the pending state of its object, so a condition missing
+// from one observation starts over.
+func (e *RuleEngine) Evaluate(obs *Observation) []*models.Alert {
+	object := obs.object()
+	now := e.now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := obs.subjectKey()
+	previous := e.pending[key]
+	current := make(map[string]*pendingEntry)
+
+	var alerts []*models.Alert
+	for _, rule := range e.rules {
+		if rule.Object != object || !rule.inScope(obs) || e.disabledFor(rule, obs) {
+			continue
+		}
+		threshold := e.thresholdFor(rule, obs)
+		for _, sample := range obs.Samples {
+			if !rule.matches(sample, threshold) {
+				continue
+			}
+
+			alert := rule.buildAlert(obs, sample, threshold)
+			if rule.For == 0 {
+				alerts = append(alerts, alert)
+				continue
+			}
+
+			since := sample.Since
+			if since.IsZero() {
+				since = now
+				if entry, ok := previous[alert.Fingerprint]; ok {
+					since = entry.activeSince
+				}
+			}
+
+			entry := &pendingEntry{
+				alert:       alert,
+				rule:        rule.Name,
+				activeSince: since,
+				firesAt:     since.Add(rule.For),
+				firing:      !now.Before(since.Add(rule.For)),
+			}
+			current[alert.Fingerprint] = entry
+			if entry.firing {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+
+	if len(current) > 0 {
+		e.pending[key] = current
+	} else {
+		delete(e.pending, key)
+	}
+	return alerts
+}
+
+// Forget drops the pending state of an object that no longer exists.
+// subject holds the namespace and pod, node, namespace and workload, or namespace and pvc
+// labels identifying it.
+func (e *RuleEngine) Forget(source string, subject map[string]string) {
+	obs := &Observation{
+		Source:       source,
+		Namespace:    subject["namespace"],
+		Pod:          subject["pod"],
+		Node:         subject["node"],
+		WorkloadKind: subject["workload_kind"],
+		Workload:     subject["workload"],
+		PVC:          subject["pvc"],
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.pending, obs.subjectKey())
+}
+
+// PendingFor returns the alerts of the observed object that wait for their rule's duration, and
+// when the soonest of them fires; the zero time if there are none. Watchers that do not observe
+// the object again on their own re-evaluate it then.
+func (e *RuleEngine) PendingFor(obs *Observation) ([]*models.Alert, time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var alerts []*models.Alert
+	var firesAt time.Time
+	for _, entry := range e.pending[obs.subjectKey()] {
+		if entry.firing {
+			continue
+		}
+		alerts = append(alerts, entry.alert)
+		if firesAt.IsZero() || entry.firesAt.Before(firesAt) {
+			firesAt = entry.firesAt
+		}
+	}
+	return alerts, firesAt
+}
+
+// Pending returns the alerts waiting for their rule's duration, soonest to fire first
+func (e *RuleEngine) Pending() []*PendingAlert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pending := make([]*PendingAlert, 0)
+	for _, entries := range e.pending {
+		for _, entry := range entries {
+			if entry.firing {
+				continue
+			}
+			pending = append(pending, &PendingAlert{
+				Rule:        entry.rule,
+				Fingerprint: entry.alert.Fingerprint,
+				Severity:    entry.alert.Severity,
+				Message:     entry.alert.Message,
+				Source:      entry.alert.Source,
+				Value:       entry.alert.Value,
+				Labels:      entry.alert.GetLabelsMap(),
+				ActiveSince: entry.activeSince,
+				FiresAt:     entry.firesAt,
+			})
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].FiresAt.Equal(pending[j].FiresAt) {
+			return pending[i].Fingerprint < pending[j].Fingerprint
+		}
+		return pending[i].FiresAt.Before(pending[j].FiresAt)
+	})
+	return pending
+}
+
+// inScope reports whether the observed object is within the rule's namespaces and selector
+func (r *compiledRule) inScope(obs *Observation) bool {
+	if len(r.Namespaces) > 0 {
+		found := false
+		for _, namespace := range r.Namespaces {
+			if namespace == obs.Namespace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for key, value := range r.Selector {
+		if obs.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether the sample satisfies the rule's comparison against the threshold
+func (r *compiledRule) matches(sample Sample, threshold float64) bool {
+	if sample.Signal != r.Signal || (r.Key != "" && sample.Key != r.Key) {
+		return false
+	}
+
+	switch r.Operator {
+	case OpEqual:
+		return sample.Text == r.Value
+	case OpNotEqual:
+		return sample.Text != r.Value
+	case OpIn, OpNotIn:
+		found := false
+		for _, value := range r.Values {
+			if sample.Text == value {
+				found = true
+				break
+			}
+		}
+		return found == (r.Operator == OpIn)
+	case OpGreater:
+		return sample.Value > threshold
+	case OpGreaterEqual:
+		return sample.Value >= threshold
+	case OpLess:
+		return sample.Value < threshold
+	case OpLessEqual:
+		return sample.Value <= threshold
+	default:
+		return false
+	}
+}
+
+// buildAlert creates the alert raised by the rule for a matching sample
+func (r *compiledRule) buildAlert(obs *Observation, sample Sample, threshold float64) *models.Alert {
+	labels := obs.subjectLabels()
+	labels["alert_type"] = r.Name
+	for key, value := range sample.Labels {
+		labels[key] = value
+	}
+
+	data := ruleTemplateData{
+		Rule:         r.Name,
+		Namespace:    obs.Namespace,
+		Pod:          obs.Pod,
+		Node:         obs.Node,
+		Container:    labels["container"],
+		WorkloadKind: obs.WorkloadKind,
+		Workload:     obs.Workload,
+		PVC:          obs.PVC,
+		Key:          sample.Key,
+		Text:         sample.Text,
+		Value:        sample.Value,
+		Threshold:    threshold,
+		Reason:       sample.Reason,
+		Message:      sample.Message,
+	}
+
+	var message bytes.Buffer
+	if err := r.message.Execute(&message, data); err != nil {
+		message.Reset()
+		fmt.Fprintf(&message, "%s: %s", r.Name, obs.subjectName())
+	}
+
+	return models.NewAlert(r.Severity, message.String(), obs.Source, sample.Value, labels)
+}
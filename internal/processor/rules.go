@@ -0,0 +1,390 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule comparison operators
+const (
+	OpEqual        = "=="
+	OpNotEqual     = "!="
+	OpGreater      = ">"
+	OpGreaterEqual = ">="
+	OpLess         = "<"
+	OpLessEqual    = "<="
+	OpIn           = "in"
+	OpNotIn        = "not_in"
+)
+
+// Rule objects
+const (
+	ObjectPod      = "pod"
+	ObjectNode     = "node"
+	ObjectWorkload = "workload" // Deployment, StatefulSet or DaemonSet
+	ObjectVolume   = "volume"   // the volume of a PersistentVolumeClaim
+)
+
+var validSeverities = map[string]bool{"critical": true, "high": true, "medium": true, "low": true}
+
+// Rule is a declarative alert rule evaluated against watcher observations
+type Rule struct {
+	Name       string            `yaml:"name" json:"name"`                         // alert_type label of raised alerts
+	Object     string            `yaml:"object,omitempty" json:"object,omitempty"` // pod, node, workload or volume; implied by all signals except cpu/memory percent
+	Signal     Signal            `yaml:"signal" json:"signal"`                     // observed value the rule compares
+	Key        string            `yaml:"key,omitempty" json:"key,omitempty"`       // limits the rule to a node condition type, container name or workload kind
+	Operator   string            `yaml:"op" json:"op"`
+	Value      string            `yaml:"value,omitempty" json:"value,omitempty"`
+	Values     []string          `yaml:"values,omitempty" json:"values,omitempty"` // for in / not_in
+	For        time.Duration     `yaml:"for,omitempty" json:"for,omitempty"`       // how long the condition must hold before firing
+	Severity   string            `yaml:"severity" json:"severity"`
+	Namespaces []string          `yaml:"namespaces,omitempty" json:"namespaces,omitempty"` // only objects in these namespaces
+	Selector   map[string]string `yaml:"selector,omitempty" json:"selector,omitempty"`     // object labels that must all match
+	Message    string            `yaml:"message" json:"message"`                           // text/template over ruleTemplateData
+	Disabled   bool              `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+
+	// ThresholdAnnotation names the monitoring-tool/ annotation that overrides Value on a pod,
+	// workload, claim, their namespace or a node, e.g. "cpu-threshold" for monitoring-tool/cpu-threshold
+	ThresholdAnnotation string `yaml:"threshold_annotation,omitempty" json:"threshold_annotation,omitempty"`
+}
+
+// RulesFile is the layout of a YAML rules file
+type RulesFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// numericOperators compare Sample.Value; the others compare Sample.Text
+var numericOperators = map[string]bool{OpGreater: true, OpGreaterEqual: true, OpLess: true, OpLessEqual: true}
+
+// signalObjects maps signals that only exist on one kind of object to that object
+var signalObjects = map[Signal]string{
+	SignalPodPhase:                  ObjectPod,
+	SignalContainerWaitingReason:    ObjectPod,
+	SignalContainerTerminatedReason: ObjectPod,
+	SignalRestartCount:              ObjectPod,
+	SignalNodeCondition:             ObjectNode,
+	SignalContainerMemoryLimit:      ObjectPod,
+	SignalEphemeralStorage:          ObjectPod,
+	SignalRolloutProgress:           ObjectWorkload,
+	SignalAvailableReplicasPercent:  ObjectWorkload,
+	SignalUnavailableReplicas:       ObjectWorkload,
+	SignalMisscheduledPods:          ObjectWorkload,
+	SignalGenerationLag:             ObjectWorkload,
+	SignalVolumeUsedPercent:         ObjectVolume,
+	SignalVolumeInodesPercent:       ObjectVolume,
+}
+
+// Validate checks the rule refers to a known signal and operator and has a parsable value and message.
+// It fills in Object for signals that imply it.
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule name is required")
+	}
+	if !knownSignals[r.Signal] {
+		return fmt.Errorf("rule %q: unknown signal %q", r.Name, r.Signal)
+	}
+	if r.Object == "" {
+		r.Object = signalObjects[r.Signal]
+	}
+	switch r.Object {
+	case ObjectPod, ObjectNode, ObjectWorkload, ObjectVolume:
+	default:
+		return fmt.Errorf("rule %q: object must be %q, %q, %q or %q for signal %q", r.Name, ObjectPod, ObjectNode, ObjectWorkload, ObjectVolume, r.Signal)
+	}
+	if !validSeverities[r.Severity] {
+		return fmt.Errorf("rule %q: invalid severity %q", r.Name, r.Severity)
+	}
+	if r.For < 0 {
+		return fmt.Errorf("rule %q: for must not be negative", r.Name)
+	}
+	if r.ThresholdAnnotation != "" && !numericOperators[r.Operator] {
+		return fmt.Errorf("rule %q: threshold_annotation needs a numeric operator", r.Name)
+	}
+
+	switch {
+	case numericOperators[r.Operator]:
+		if _, err := strconv.ParseFloat(r.Value, 64); err != nil {
+			return fmt.Errorf("rule %q: operator %s needs a numeric value, got %q", r.Name, r.Operator, r.Value)
+		}
+	case r.Operator == OpEqual || r.Operator == OpNotEqual:
+	case r.Operator == OpIn || r.Operator == OpNotIn:
+		if len(r.Values) == 0 {
+			return fmt.Errorf("rule %q: operator %s needs values", r.Name, r.Operator)
+		}
+	default:
+		return fmt.Errorf("rule %q: unknown operator %q", r.Name, r.Operator)
+	}
+
+	if r.Message == "" {
+		return fmt.Errorf("rule %q: message is required", r.Name)
+	}
+	if _, err := template.New(r.Name).Parse(r.Message); err != nil {
+		return fmt.Errorf("rule %q: invalid message template: %w", r.Name, err)
+	}
+	return nil
+}
+
+// Threshold returns the numeric rule value, or 0 if it is not numeric
+func (r *Rule) Threshold() float64 {
+	threshold, _ := strconv.ParseFloat(r.Value, 64)
+	return threshold
+}
+
+// LoadRulesFile reads rules from a YAML file
+func LoadRulesFile(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var file RulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file: %w", err)
+	}
+	return file.Rules, nil
+}
+
+// MergeRules overlays rules on top of the defaults: the fields set on a rule with the same name
+// override the default's, e.g. only its severity, a disabled rule removes it, and new names are
+// appended
+func MergeRules(defaults, overrides []Rule) []Rule {
+	merged := make([]Rule, 0, len(defaults)+len(overrides))
+	index := make(map[string]int, len(defaults))
+	for _, rule := range defaults {
+		index[rule.Name] = len(merged)
+		merged = append(merged, rule)
+	}
+
+	for _, rule := range overrides {
+		if i, ok := index[rule.Name]; ok {
+			merged[i] = merged[i].overlay(rule)
+			continue
+		}
+		index[rule.Name] = len(merged)
+		merged = append(merged, rule)
+	}
+
+	enabled := merged[:0]
+	for _, rule := range merged {
+		if !rule.Disabled {
+			enabled = append(enabled, rule)
+		}
+	}
+	return enabled
+}
+
+// overlay returns the rule with the fields set on override replacing its own. A zero for cannot be
+// told apart from an unset one, so a built-in rule's duration is removed under alert_rules.for.
+func (r Rule) overlay(override Rule) Rule {
+	if override.Object != "" {
+		r.Object = override.Object
+	}
+	if override.Signal != "" {
+		r.Signal = override.Signal
+	}
+	if override.Key != "" {
+		r.Key = override.Key
+	}
+	if override.Operator != "" {
+		r.Operator = override.Operator
+	}
+	if override.Value != "" {
+		r.Value = override.Value
+	}
+	if override.Values != nil {
+		r.Values = override.Values
+	}
+	if override.For != 0 {
+		r.For = override.For
+	}
+	if override.Severity != "" {
+		r.Severity = override.Severity
+	}
+	if override.Namespaces != nil {
+		r.Namespaces = override.Namespaces
+	}
+	if override.Selector != nil {
+		r.Selector = override.Selector
+	}
+	if override.Message != "" {
+		r.Message = override.Message
+	}
+	if override.ThresholdAnnotation != "" {
+		r.ThresholdAnnotation = override.ThresholdAnnotation
+	}
+	r.Disabled = override.Disabled
+	return r
+}
+
+// Thresholds of the kubelet usage rules when not configured
+const (
+	defaultContainerMemoryLimitThreshold = 90
+	defaultEphemeralStorageThreshold     = 85
+	defaultVolumeUsageThreshold          = 85.0
+	defaultVolumeInodesThreshold         = 90.0
+)
+
+// Workload rule settings when not configured
+const (
+	defaultWorkloadAvailableRatio = 0.75
+	defaultWorkloadGracePeriod    = 5 * time.Minute
+)
+
+// DefaultRules returns the built-in rules, reproducing the watchers' original hard-coded checks
+// with thresholds and pending durations from the alert_rules configuration
+func DefaultRules(cfg config.AlertRulesConfig) []Rule {
+	percent := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	orDefault := func(v, fallback int) string {
+		if v <= 0 {
+			v = fallback
+		}
+		return strconv.Itoa(v)
+	}
+	percentOrDefault := func(v, fallback float64) string {
+		if v <= 0 {
+			v = fallback
+		}
+		return percent(v)
+	}
+	// Workloads pass through degraded states during healthy rollouts, so those only alert once
+	// they have lasted the grace period
+	availableRatio := cfg.WorkloadAvailableRatio
+	if availableRatio <= 0 {
+		availableRatio = defaultWorkloadAvailableRatio
+	}
+	gracePeriod := cfg.WorkloadGracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = defaultWorkloadGracePeriod
+	}
+
+	rules := []Rule{
+		{
+			Name: "pod_failed", Signal: SignalPodPhase, Operator: OpEqual, Value: "Failed", Severity: "critical",
+			Message: "Pod {{.Namespace}}/{{.Pod}} has FAILED - Phase: {{.Text}}, Reason: {{.Reason}}{{if .Message}}, Message: {{.Message}}{{end}}",
+		},
+		{
+			Name: "pod_unknown", Signal: SignalPodPhase, Operator: OpEqual, Value: "Unknown", Severity: "critical",
+			Message: "Pod {{.Namespace}}/{{.Pod}} is in UNKNOWN state - Last known phase: {{.Text}}",
+		},
+		{
+			Name: "pod_oom_killed", Signal: SignalContainerTerminatedReason, Operator: OpEqual, Value: "OOMKilled", Severity: "critical",
+			Message: "Pod {{.Namespace}}/{{.Pod}} container '{{.Container}}' was OOM KILLED - Out of memory",
+		},
+		{
+			Name: "pod_crash_loop", Signal: SignalContainerWaitingReason, Operator: OpEqual, Value: "CrashLoopBackOff", Severity: "high",
+			Message: "Pod {{.Namespace}}/{{.Pod}} container '{{.Container}}' is in CRASH LOOP BACKOFF - Reason: {{.Message}}",
+		},
+		{
+			Name: "pod_image_pull", Signal: SignalContainerWaitingReason, Operator: OpIn, Values: []string{"ImagePullBackOff", "ErrImagePull"}, Severity: "high",
+			Message: "Pod {{.Namespace}}/{{.Pod}} container '{{.Container}}' cannot pull image - Error: {{.Message}}",
+		},
+		{
+			Name: "pod_restart_threshold", Signal: SignalRestartCount, Operator: OpGreater, Value: strconv.Itoa(cfg.PodRestartThreshold), Severity: "high",
+			ThresholdAnnotation: "restart-threshold",
+			Message:             "Pod {{.Namespace}}/{{.Pod}} has EXCESSIVE RESTARTS - Total restarts: {{printf \"%.0f\" .Value}}, Container: {{.Container}}",
+		},
+		{
+			Name: "pod_pending", Signal: SignalPodPhase, Operator: OpEqual, Value: "Pending", For: 5 * time.Minute, Severity: "medium",
+			Message: "Pod {{.Namespace}}/{{.Pod}} is PENDING for extended period - Reason: {{.Reason}}{{if .Message}}, Details: {{.Message}}{{end}}",
+		},
+		{
+			Name: "node_not_ready", Signal: SignalNodeCondition, Key: "Ready", Operator: OpNotEqual, Value: "True", Severity: "critical",
+			Message: "Node {{.Node}} is NOT READY - Status: {{.Reason}}",
+		},
+		{
+			Name: "node_memory_pressure", Signal: SignalNodeCondition, Key: "MemoryPressure", Operator: OpEqual, Value: "True", Severity: "high",
+			Message: "Node {{.Node}} has MEMORY PRESSURE - Available memory is low",
+		},
+		{
+			Name: "node_disk_pressure", Signal: SignalNodeCondition, Key: "DiskPressure", Operator: OpEqual, Value: "True", Severity: "high",
+			Message: "Node {{.Node}} has DISK PRESSURE - Disk space is running low",
+		},
+		{
+			Name: "node_pid_pressure", Signal: SignalNodeCondition, Key: "PIDPressure", Operator: OpEqual, Value: "True", Severity: "medium",
+			Message: "Node {{.Node}} has PID PRESSURE - Too many processes running",
+		},
+		{
+			Name: "pod_cpu_high", Object: ObjectPod, Signal: SignalCPUPercent, Operator: OpGreater, Value: percent(cfg.PodCPUPercent), Severity: "high",
+			ThresholdAnnotation: "cpu-threshold",
+			Message:             "Pod {{.Namespace}}/{{.Pod}} CPU usage is HIGH: {{printf \"%.1f\" .Value}}% (threshold: {{printf \"%.1f\" .Threshold}}%)",
+		},
+		{
+			Name: "pod_memory_high", Object: ObjectPod, Signal: SignalMemoryPercent, Operator: OpGreater, Value: percent(cfg.PodMemoryPercent), Severity: "high",
+			ThresholdAnnotation: "memory-threshold",
+			Message:             "Pod {{.Namespace}}/{{.Pod}} Memory usage is HIGH: {{printf \"%.1f\" .Value}}% (threshold: {{printf \"%.1f\" .Threshold}}%)",
+		},
+		{
+			Name: "node_cpu_high", Object: ObjectNode, Signal: SignalCPUPercent, Operator: OpGreater, Value: percent(cfg.NodeCPUPercent), Severity: "critical",
+			ThresholdAnnotation: "cpu-threshold",
+			Message:             "Node {{.Node}} CPU usage is CRITICAL: {{printf \"%.1f\" .Value}}% (threshold: {{printf \"%.1f\" .Threshold}}%)",
+		},
+		{
+			Name: "node_memory_high", Object: ObjectNode, Signal: SignalMemoryPercent, Operator: OpGreater, Value: percent(cfg.NodeMemoryPercent), Severity: "critical",
+			ThresholdAnnotation: "memory-threshold",
+			Message:             "Node {{.Node}} Memory usage is CRITICAL: {{printf \"%.1f\" .Value}}% (threshold: {{printf \"%.1f\" .Threshold}}%)",
+		},
+		{
+			Name: "container_memory_near_limit", Signal: SignalContainerMemoryLimit, Operator: OpGreater,
+			Value: orDefault(cfg.ContainerMemoryLimitThreshold, defaultContainerMemoryLimitThreshold), Severity: "high",
+			ThresholdAnnotation: "memory-limit-threshold",
+			Message:             "Pod {{.Namespace}}/{{.Pod}} container '{{.Container}}' memory is NEAR ITS LIMIT: {{printf \"%.1f\" .Value}}% (threshold: {{printf \"%.1f\" .Threshold}}%)",
+		},
+		{
+			Name: "container_ephemeral_storage_high", Signal: SignalEphemeralStorage, Operator: OpGreater,
+			Value: orDefault(cfg.EphemeralStorageThreshold, defaultEphemeralStorageThreshold), Severity: "high",
+			ThresholdAnnotation: "ephemeral-storage-threshold",
+			Message:             "Pod {{.Namespace}}/{{.Pod}} container '{{.Container}}' ephemeral storage is HIGH: {{printf \"%.1f\" .Value}}% of its limit (threshold: {{printf \"%.1f\" .Threshold}}%) - the pod is evicted at 100%",
+		},
+		{
+			Name: "deployment_rollout_stuck", Signal: SignalRolloutProgress, Key: "Deployment", Operator: OpEqual, Value: "ProgressDeadlineExceeded", Severity: "critical",
+			Message: "Deployment {{.Namespace}}/{{.Workload}} rollout is STUCK - No progress for {{printf \"%.0f\" .Value}}s, Message: {{.Message}}",
+		},
+		{
+			Name: "deployment_replicas_unavailable", Signal: SignalAvailableReplicasPercent, Key: "Deployment", Operator: OpLess,
+			Value: percent(availableRatio * 100), For: gracePeriod, Severity: "high",
+			ThresholdAnnotation: "available-replicas-threshold",
+			Message:             "Deployment {{.Namespace}}/{{.Workload}} has UNAVAILABLE REPLICAS - {{.Message}}",
+		},
+		{
+			Name: "statefulset_replicas_not_ready", Signal: SignalUnavailableReplicas, Key: "StatefulSet", Operator: OpGreater, Value: "0", For: gracePeriod, Severity: "high",
+			Message: "StatefulSet {{.Namespace}}/{{.Workload}} has replicas NOT READY - {{.Message}}",
+		},
+		{
+			Name: "daemonset_misscheduled", Signal: SignalMisscheduledPods, Key: "DaemonSet", Operator: OpGreater, Value: "0", For: gracePeriod, Severity: "medium",
+			Message: "DaemonSet {{.Namespace}}/{{.Workload}} has MISSCHEDULED pods - {{printf \"%.0f\" .Value}} pods run on nodes they should not",
+		},
+		{
+			Name: "daemonset_pods_unavailable", Signal: SignalUnavailableReplicas, Key: "DaemonSet", Operator: OpGreater, Value: "0", For: gracePeriod, Severity: "high",
+			Message: "DaemonSet {{.Namespace}}/{{.Workload}} has UNAVAILABLE pods - {{.Message}}",
+		},
+		{
+			Name: "workload_generation_lag", Signal: SignalGenerationLag, Operator: OpGreater, Value: "0", For: gracePeriod, Severity: "medium",
+			Message: "{{.WorkloadKind}} {{.Namespace}}/{{.Workload}} spec is NOT OBSERVED by its controller - {{.Message}}",
+		},
+		{
+			Name: "volume_usage_high", Signal: SignalVolumeUsedPercent, Operator: OpGreaterEqual,
+			Value: percentOrDefault(cfg.VolumeUsageThreshold, defaultVolumeUsageThreshold), Severity: "high",
+			ThresholdAnnotation: "volume-usage-threshold",
+			Message:             "PVC {{.Namespace}}/{{.PVC}} volume usage is HIGH: {{printf \"%.1f\" .Value}}% (threshold: {{printf \"%.1f\" .Threshold}}%) - Pod: {{.Pod}}",
+		},
+		{
+			Name: "volume_inodes_high", Signal: SignalVolumeInodesPercent, Operator: OpGreaterEqual,
+			Value: percentOrDefault(cfg.VolumeInodesThreshold, defaultVolumeInodesThreshold), Severity: "high",
+			ThresholdAnnotation: "volume-inodes-threshold",
+			Message:             "PVC {{.Namespace}}/{{.PVC}} inode usage is HIGH: {{printf \"%.1f\" .Value}}% (threshold: {{printf \"%.1f\" .Threshold}}%) - Pod: {{.Pod}}",
+		},
+	}
+
+	for i := range rules {
+		if pendingFor, ok := cfg.For[rules[i].Name]; ok {
+			rules[i].For = pendingFor
+		}
+	}
+	return rules
+}
@@ -0,0 +1,43 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+)
+
+// Suppressor decides whether an alert should be stored without being notified
+type Suppressor interface {
+	// Suppress flags the alert and returns true if notifications for it should be held back
+	Suppress(ctx context.Context, alert *models.Alert) (bool, error)
+}
+
+// Silencer suppresses alerts matched by an active silence
+type Silencer struct {
+	silenceRepo repository.SilenceRepo
+}
+
+// NewSilencer creates a new silencer backed by the silence repository
+func NewSilencer(silenceRepo repository.SilenceRepo) *Silencer {
+	return &Silencer{silenceRepo: silenceRepo}
+}
+
+// Suppress marks the alert as silenced by the first active silence whose matchers match its labels.
+// Severity and source can be matched as labels, as in routes and inhibition rules.
+func (s *Silencer) Suppress(ctx context.Context, alert *models.Alert) (bool, error) {
+	silences, err := s.silenceRepo.GetActive(ctx, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	labels := alert.MatchLabels()
+	for _, silence := range silences {
+		if silence.Matches(labels) {
+			alert.Silence(silence.ID)
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -2,17 +2,22 @@ package processor
 
 import (
 	"context"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/monitoring-engine/monitoring-tool/internal/models"
 	"github.com/monitoring-engine/monitoring-tool/internal/repository"
 	"github.com/monitoring-engine/monitoring-tool/internal/logger"
 )
 
-// AlertStateManager manages alert lifecycle (no deduplication - every alert is created as new)
+// AlertStateManager manages alert lifecycle with fingerprint-based deduplication
 type AlertStateManager struct {
-	alertRepo repository.AlertRepo
-	eventBus  *EventBus
+	alertRepo   repository.AlertRepo
+	eventBus    *EventBus
+	suppressors []Suppressor
+	outbox      *Outbox
+	mu          sync.Mutex // serializes lookup+write so concurrent workers cannot duplicate a fingerprint
 }
 
 // NewAlertStateManager creates a new alert state manager
@@ -23,25 +28,304 @@ func NewAlertStateManager(alertRepo repository.AlertRepo, eventBus *EventBus) *A
 	}
 }
 
-// ProcessAlert handles alert without deduplication - creates every alert as new
-// Returns true always (every alert is new)
+// AddSuppressor registers a suppressor consulted before a new alert is published
+func (asm *AlertStateManager) AddSuppressor(suppressor Suppressor) {
+	asm.mu.Lock()
+	defer asm.mu.Unlock()
+	asm.suppressors = append(asm.suppressors, suppressor)
+}
+
+// UseOutbox queues the notifications of every published event in the outbox, in the same
+// transaction that stores the alert change
+func (asm *AlertStateManager) UseOutbox(outbox *Outbox) {
+	asm.mu.Lock()
+	defer asm.mu.Unlock()
+	asm.outbox = outbox
+}
+
+// ProcessAlert deduplicates the alert by fingerprint.
+// A new firing alert is created and published unless a suppressor matches it, in
+// which case it is stored flagged as suppressed. A repeat observation only updates
+// last_seen_at, occurrence count and value on the existing active alert, so an
+// acknowledged alert stays quiet until it resolves; a suppressed alert is published
+// once no suppressor matches it any more.
+// Returns true if a new alert was created.
 func (asm *AlertStateManager) ProcessAlert(ctx context.Context, alert *models.Alert) (bool, error) {
-	// Create every alert as new - no deduplication
-	if err := asm.alertRepo.Create(ctx, alert); err != nil {
+	fingerprint := alert.EnsureFingerprint()
+
+	asm.mu.Lock()
+	defer asm.mu.Unlock()
+
+	existing, err := asm.alertRepo.GetActiveByFingerprint(ctx, fingerprint)
+	if err != nil {
 		return false, err
 	}
 
-	// Publish to event bus for real-time notifications
-	asm.eventBus.Publish(&AlertEvent{
-		Alert:     alert,
-		Timestamp: time.Now(),
-	})
+	if existing != nil {
+		existing.Touch(alert.Value, alert.Message)
+		wasSuppressed := existing.Suppressed
+		if wasSuppressed {
+			asm.suppress(ctx, existing)
+		}
+		var event *AlertEvent
+		if wasSuppressed && !existing.Suppressed {
+			event = &AlertEvent{Type: AlertEventFiring, Alert: existing, Timestamp: time.Now()}
+		}
+		if err := asm.save(ctx, existing, false, event); err != nil {
+			return false, err
+		}
+
+		if event != nil {
+			logger.Info().
+				Str("fingerprint", fingerprint).
+				Str("message", existing.Message).
+				Msg("Alert no longer suppressed, published")
+			return false, nil
+		}
+
+		logger.Debug().
+			Str("fingerprint", fingerprint).
+			Int("occurrences", existing.OccurrenceCount).
+			Str("status", string(existing.Status)).
+			Msg("Alert already active, updated occurrence")
+		return false, nil
+	}
+
+	if alert.OccurrenceCount == 0 {
+		alert.OccurrenceCount = 1
+	}
+	if alert.LastSeenAt.IsZero() {
+		alert.LastSeenAt = time.Now()
+	}
+	suppressed := asm.suppress(ctx, alert)
+	var event *AlertEvent
+	if !suppressed {
+		event = &AlertEvent{Type: AlertEventFiring, Alert: alert, Timestamp: time.Now()}
+	}
+	if err := asm.save(ctx, alert, true, event); err != nil {
+		return false, err
+	}
+
+	if suppressed {
+		logger.Info().
+			Str("severity", alert.Severity).
+			Str("source", alert.Source).
+			Str("fingerprint", fingerprint).
+			Str("message", alert.Message).
+			Msg("Alert created but suppressed")
+		return true, nil
+	}
 
 	logger.Info().
 		Str("severity", alert.Severity).
 		Str("source", alert.Source).
+		Str("fingerprint", fingerprint).
 		Str("message", alert.Message).
 		Msg("Alert created and published")
 
 	return true, nil
 }
+
+// ResolveCleared resolves active alerts from source for the given subject labels
+// (e.g. namespace+pod or node) whose fingerprint is not among the still-active alerts.
+// Passing no active alerts resolves everything active for the subject, e.g. when it is deleted.
+// Returns the number of alerts resolved.
+func (asm *AlertStateManager) ResolveCleared(ctx context.Context, source string, subject map[string]string, active []*models.Alert) (int, error) {
+	stillFiring := make(map[string]bool, len(active))
+	for _, alert := range active {
+		stillFiring[alert.EnsureFingerprint()] = true
+	}
+
+	asm.mu.Lock()
+	defer asm.mu.Unlock()
+
+	firing, err := asm.alertRepo.GetActiveByLabels(ctx, source, subject)
+	if err != nil {
+		return 0, err
+	}
+
+	resolved := 0
+	for _, alert := range firing {
+		if stillFiring[alert.Fingerprint] {
+			continue
+		}
+
+		alert.Resolve()
+		// Nothing was sent while the alert was suppressed, so there is nothing to recover
+		var event *AlertEvent
+		if !alert.Suppressed {
+			event = &AlertEvent{Type: AlertEventResolved, Alert: alert, Timestamp: time.Now()}
+		}
+		if err := asm.save(ctx, alert, false, event); err != nil {
+			return resolved, err
+		}
+		resolved++
+		asm.releaseInhibited(ctx, alert)
+
+		if event == nil {
+			continue
+		}
+
+		logger.Info().
+			Str("severity", alert.Severity).
+			Str("source", alert.Source).
+			Str("fingerprint", alert.Fingerprint).
+			Str("message", alert.Message).
+			Msg("Alert resolved and published")
+	}
+
+	return resolved, nil
+}
+
+// Acknowledge marks a firing alert as acknowledged by an operator and publishes the change
+func (asm *AlertStateManager) Acknowledge(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error) {
+	return asm.transition(ctx, id, models.AlertActionAcknowledge, actor, comment)
+}
+
+// Unacknowledge returns an acknowledged alert to firing and publishes the change
+func (asm *AlertStateManager) Unacknowledge(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error) {
+	return asm.transition(ctx, id, models.AlertActionUnacknowledge, actor, comment)
+}
+
+// Resolve manually resolves an active alert and publishes the change
+func (asm *AlertStateManager) Resolve(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error) {
+	return asm.transition(ctx, id, models.AlertActionResolve, actor, comment)
+}
+
+// transition applies an operator action to an alert, records it and publishes the new state
+func (asm *AlertStateManager) transition(ctx context.Context, id uuid.UUID, action models.AlertActionType, actor, comment string) (*models.Alert, error) {
+	asm.mu.Lock()
+	defer asm.mu.Unlock()
+
+	alert, err := asm.alertRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var eventType AlertEventType
+	switch action {
+	case models.AlertActionAcknowledge:
+		err = alert.Acknowledge(actor, comment)
+		eventType = AlertEventAcknowledged
+	case models.AlertActionUnacknowledge:
+		err = alert.Unacknowledge(comment)
+		eventType = AlertEventUnacknowledged
+	case models.AlertActionResolve:
+		err = alert.ResolveBy(actor, comment)
+		eventType = AlertEventResolved
+	default:
+		err = models.ErrInvalidTransition
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var event *AlertEvent
+	if !alert.Suppressed {
+		event = &AlertEvent{Type: eventType, Alert: alert, Timestamp: time.Now()}
+	}
+	if err := asm.save(ctx, alert, false, event); err != nil {
+		return nil, err
+	}
+	if err := asm.alertRepo.CreateAction(ctx, models.NewAlertAction(alert.ID, action, actor, comment)); err != nil {
+		logger.Error().Err(err).Str("alert_id", alert.ID.String()).Msg("Failed to record alert action")
+	}
+	if !alert.IsActive() {
+		asm.releaseInhibited(ctx, alert)
+	}
+
+	logger.Info().
+		Str("alert_id", alert.ID.String()).
+		Str("action", string(action)).
+		Str("actor", actor).
+		Msg("Alert state changed by operator")
+
+	return alert, nil
+}
+
+// save creates or updates the alert. With an event, the notifications of the event are queued in
+// the outbox within the same transaction, and the event is published once the change is stored.
+// Callers must hold asm.mu.
+func (asm *AlertStateManager) save(ctx context.Context, alert *models.Alert, create bool, event *AlertEvent) error {
+	write := func(alerts repository.AlertRepo, notifications repository.NotificationRepo) error {
+		var err error
+		if create {
+			err = alerts.Create(ctx, alert)
+		} else {
+			err = alerts.Upsert(ctx, alert)
+		}
+		if err != nil || notifications == nil {
+			return err
+		}
+		return asm.outbox.enqueue(ctx, notifications, event)
+	}
+
+	var err error
+	if asm.outbox != nil && event != nil {
+		err = asm.outbox.transactor.WithinTx(ctx, write)
+	} else {
+		err = write(asm.alertRepo, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	if event != nil {
+		if asm.outbox != nil {
+			asm.outbox.wake()
+		}
+		asm.eventBus.Publish(event)
+	}
+	return nil
+}
+
+// suppress clears any previous suppression and asks each suppressor in turn whether the
+// alert should be held back. Suppressor errors are logged and ignored so a failing
+// lookup never hides an alert. Callers must hold asm.mu.
+func (asm *AlertStateManager) suppress(ctx context.Context, alert *models.Alert) bool {
+	alert.ClearSuppression()
+	for _, suppressor := range asm.suppressors {
+		suppressed, err := suppressor.Suppress(ctx, alert)
+		if err != nil {
+			logger.Error().Err(err).Str("fingerprint", alert.Fingerprint).Msg("Failed to evaluate alert suppression")
+			continue
+		}
+		if suppressed {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseInhibited re-evaluates the active alerts inhibited by a source alert that has resolved
+// and publishes those no longer suppressed, so they do not wait for their next observation.
+// Callers must hold asm.mu.
+func (asm *AlertStateManager) releaseInhibited(ctx context.Context, source *models.Alert) {
+	active, err := asm.alertRepo.GetActive(ctx)
+	if err != nil {
+		logger.Error().Err(err).Str("alert_id", source.ID.String()).Msg("Failed to load alerts inhibited by resolved alert")
+		return
+	}
+
+	for _, alert := range active {
+		if !alert.Inhibited || alert.InhibitedBy == nil || *alert.InhibitedBy != source.ID {
+			continue
+		}
+		var event *AlertEvent
+		if !asm.suppress(ctx, alert) {
+			event = &AlertEvent{Type: AlertEventFiring, Alert: alert, Timestamp: time.Now()}
+		}
+		if err := asm.save(ctx, alert, false, event); err != nil {
+			logger.Error().Err(err).Str("fingerprint", alert.Fingerprint).Msg("Failed to update inhibited alert")
+			continue
+		}
+		if event == nil {
+			continue
+		}
+
+		logger.Info().
+			Str("fingerprint", alert.Fingerprint).
+			Str("message", alert.Message).
+			Msg("Alert no longer inhibited, published")
+	}
+}
@@ -2,30 +2,60 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/monitoring-engine/monitoring-tool/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AlertRepo interface for alert storage
 type AlertRepo interface {
 	Create(ctx context.Context, alert *models.Alert) error
 	GetRecent(ctx context.Context, limit int) ([]*models.Alert, error)
+	// Query returns the page of alerts matching the query, in its sort order
+	Query(ctx context.Context, query *AlertQuery) (*AlertPage, error)
 	Count(ctx context.Context) (int64, error)
 	CountByStatus(ctx context.Context, status models.AlertStatus) (int64, error)
+	// CountActive counts the firing or acknowledged alerts
+	CountActive(ctx context.Context) (int64, error)
 	CountBySeverity(ctx context.Context, severity string) (int64, error)
+	// Stats counts the alerts triggered in the query's range per time bucket and group, ordered by bucket and group
+	Stats(ctx context.Context, query *AlertStatsQuery) ([]*AlertStatsBucket, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Alert, error)
+	// GetActiveByFingerprint returns the firing or acknowledged alert with the given fingerprint, or nil if none is active
+	GetActiveByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error)
+	// GetActive returns every firing or acknowledged alert
+	GetActive(ctx context.Context) ([]*models.Alert, error)
+	// GetActiveByLabels returns active alerts from source, or any source if empty, whose labels
+	// contain all the given pairs
+	GetActiveByLabels(ctx context.Context, source string, labels map[string]string) ([]*models.Alert, error)
+	// Upsert inserts the alert or updates the stored alert with the same ID
+	Upsert(ctx context.Context, alert *models.Alert) error
+	CreateAction(ctx context.Context, action *models.AlertAction) error
+	GetActions(ctx context.Context, alertID uuid.UUID) ([]*models.AlertAction, error)
 }
 
+// ErrAlertNotFound is returned when an alert does not exist
+var ErrAlertNotFound = errors.New("alert not found")
+
 // InMemoryAlertRepo stores alerts in memory
 type InMemoryAlertRepo struct {
-	alerts []*models.Alert
-	mu     sync.RWMutex
+	alerts  []*models.Alert
+	actions []*models.AlertAction
+	mu      sync.RWMutex
 }
 
 func NewInMemoryAlertRepo() AlertRepo {
 	return &InMemoryAlertRepo{
-		alerts: make([]*models.Alert, 0, 1000),
+		alerts:  make([]*models.Alert, 0, 1000),
+		actions: make([]*models.AlertAction, 0),
 	}
 }
 
@@ -47,6 +77,35 @@ func (r *InMemoryAlertRepo) GetRecent(ctx context.Context, limit int) ([]*models
 	return r.alerts[start:], nil
 }
 
+func (r *InMemoryAlertRepo) Query(ctx context.Context, query *AlertQuery) (*AlertPage, error) {
+	var cursor *alertCursor
+	if query.Cursor != "" {
+		var err error
+		if cursor, err = decodeAlertCursor(query.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var alerts []*models.Alert
+	for _, alert := range r.alerts {
+		if !query.matches(alert) {
+			continue
+		}
+		if cursor != nil && !query.beforePosition(cursor.at, cursor.id, query.sortValue(alert), alert.ID) {
+			continue
+		}
+		found := *alert
+		alerts = append(alerts, &found)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return query.before(alerts[i], alerts[j])
+	})
+	return newAlertPage(query, alerts), nil
+}
+
 func (r *InMemoryAlertRepo) Count(ctx context.Context) (int64, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -66,6 +125,19 @@ func (r *InMemoryAlertRepo) CountByStatus(ctx context.Context, status models.Ale
 	return count, nil
 }
 
+func (r *InMemoryAlertRepo) CountActive(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := int64(0)
+	for _, alert := range r.alerts {
+		if alert.IsActive() {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (r *InMemoryAlertRepo) CountBySeverity(ctx context.Context, severity string) (int64, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -79,6 +151,157 @@ func (r *InMemoryAlertRepo) CountBySeverity(ctx context.Context, severity string
 	return count, nil
 }
 
+func (r *InMemoryAlertRepo) Stats(ctx context.Context, query *AlertStatsQuery) ([]*AlertStatsBucket, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type bucketKey struct {
+		start time.Time
+		group string
+	}
+	counts := make(map[bucketKey]int64)
+	for _, alert := range r.alerts {
+		if alert.TriggeredAt.Before(query.From) || !alert.TriggeredAt.Before(query.To) {
+			continue
+		}
+		counts[bucketKey{start: query.truncate(alert.TriggeredAt), group: query.group(alert)}]++
+	}
+
+	buckets := make([]*AlertStatsBucket, 0, len(counts))
+	for key, count := range counts {
+		buckets = append(buckets, &AlertStatsBucket{Start: key.start, Group: key.group, Count: count})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if !buckets[i].Start.Equal(buckets[j].Start) {
+			return buckets[i].Start.Before(buckets[j].Start)
+		}
+		return buckets[i].Group < buckets[j].Group
+	})
+	return buckets, nil
+}
+
+func (r *InMemoryAlertRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, alert := range r.alerts {
+		if alert.ID == id {
+			found := *alert
+			return &found, nil
+		}
+	}
+	return nil, ErrAlertNotFound
+}
+
+func (r *InMemoryAlertRepo) GetActiveByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.alerts) - 1; i >= 0; i-- {
+		alert := r.alerts[i]
+		if alert.Fingerprint == fingerprint && alert.IsActive() {
+			// Return a copy so callers can mutate it without racing event observers
+			found := *alert
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *InMemoryAlertRepo) GetActive(ctx context.Context) ([]*models.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var alerts []*models.Alert
+	for _, alert := range r.alerts {
+		if alert.IsActive() {
+			found := *alert
+			alerts = append(alerts, &found)
+		}
+	}
+	return alerts, nil
+}
+
+func (r *InMemoryAlertRepo) GetActiveByLabels(ctx context.Context, source string, labels map[string]string) ([]*models.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var alerts []*models.Alert
+	for _, alert := range r.alerts {
+		if (source != "" && alert.Source != source) || !alert.IsActive() {
+			continue
+		}
+		if matchesLabels(alert.GetLabelsMap(), labels) {
+			found := *alert
+			alerts = append(alerts, &found)
+		}
+	}
+	return alerts, nil
+}
+
+func (r *InMemoryAlertRepo) Upsert(ctx context.Context, alert *models.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.alerts {
+		if existing.ID == alert.ID {
+			r.alerts[i] = alert
+			return nil
+		}
+	}
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func (r *InMemoryAlertRepo) CreateAction(ctx context.Context, action *models.AlertAction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.actions = append(r.actions, action)
+	return nil
+}
+
+func (r *InMemoryAlertRepo) GetActions(ctx context.Context, alertID uuid.UUID) ([]*models.AlertAction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	actions := make([]*models.AlertAction, 0)
+	for _, action := range r.actions {
+		if action.AlertID == alertID {
+			actions = append(actions, action)
+		}
+	}
+	return actions, nil
+}
+
+// newAlertPage returns the first query.Limit alerts, with a cursor to the next page if there are more.
+// Repositories fetch one alert more than the limit to tell.
+func newAlertPage(query *AlertQuery, alerts []*models.Alert) *AlertPage {
+	page := &AlertPage{Alerts: alerts}
+	if query.Limit > 0 && len(alerts) > query.Limit {
+		page.Alerts = alerts[:query.Limit]
+		last := page.Alerts[query.Limit-1]
+		page.NextCursor = encodeAlertCursor(query.sortValue(last), last.ID)
+	}
+	if page.Alerts == nil {
+		page.Alerts = []*models.Alert{}
+	}
+	return page
+}
+
+// matchesLabels returns true if labels contain every key/value pair in subset
+func matchesLabels(labels, subset map[string]string) bool {
+	for key, value := range subset {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
 // PostgresAlertRepo stores alerts in PostgreSQL
 type PostgresAlertRepo struct {
 	db *gorm.DB
@@ -101,6 +324,60 @@ func (r *PostgresAlertRepo) GetRecent(ctx context.Context, limit int) ([]*models
 	return alerts, err
 }
 
+func (r *PostgresAlertRepo) Query(ctx context.Context, query *AlertQuery) (*AlertPage, error) {
+	db := r.db.WithContext(ctx).Model(&models.Alert{})
+
+	if len(query.Statuses) > 0 {
+		db = db.Where("status IN ?", query.Statuses)
+	}
+	if len(query.Severities) > 0 {
+		db = db.Where("severity IN ?", query.Severities)
+	}
+	if len(query.Sources) > 0 {
+		db = db.Where("source IN ?", query.Sources)
+	}
+	if len(query.Labels) > 0 {
+		// Containment is served by the GIN index on labels
+		labelsJSON, err := json.Marshal(query.Labels)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("labels @> ?::jsonb", string(labelsJSON))
+	}
+	if query.TriggeredAfter != nil {
+		db = db.Where("triggered_at >= ?", *query.TriggeredAfter)
+	}
+	if query.TriggeredBefore != nil {
+		db = db.Where("triggered_at < ?", *query.TriggeredBefore)
+	}
+	if query.Search != "" {
+		db = db.Where("message ILIKE ?", "%"+escapeLike(query.Search)+"%")
+	}
+
+	column := query.sortColumn()
+	direction, comparison := "DESC", "<"
+	if query.Ascending {
+		direction, comparison = "ASC", ">"
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeAlertCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, comparison), cursor.at, cursor.id)
+	}
+
+	var alerts []*models.Alert
+	db = db.Order(fmt.Sprintf("%s %s, id %s", column, direction, direction))
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit + 1)
+	}
+	if err := db.Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return newAlertPage(query, alerts), nil
+}
+
 func (r *PostgresAlertRepo) Count(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
@@ -118,6 +395,15 @@ func (r *PostgresAlertRepo) CountByStatus(ctx context.Context, status models.Ale
 	return count, err
 }
 
+func (r *PostgresAlertRepo) CountActive(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Alert{}).
+		Where("status IN ?", models.ActiveAlertStatuses).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *PostgresAlertRepo) CountBySeverity(ctx context.Context, severity string) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
@@ -126,3 +412,111 @@ func (r *PostgresAlertRepo) CountBySeverity(ctx context.Context, severity string
 		Count(&count).Error
 	return count, err
 }
+
+func (r *PostgresAlertRepo) Stats(ctx context.Context, query *AlertStatsQuery) ([]*AlertStatsBucket, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	group := "''"
+	if query.GroupBy != "" {
+		group = statsGroupColumns[query.GroupBy]
+	}
+
+	var buckets []*AlertStatsBucket
+	err := r.db.WithContext(ctx).
+		Model(&models.Alert{}).
+		Select(fmt.Sprintf(`date_trunc('%s', triggered_at AT TIME ZONE 'UTC') AS start, %s AS "group", COUNT(*) AS count`, query.Bucket, group)).
+		Where("triggered_at >= ? AND triggered_at < ?", query.From, query.To).
+		Group("1, 2").
+		Order("1, 2").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets {
+		// date_trunc returns a timestamp without time zone in UTC
+		bucket.Start = time.Date(bucket.Start.Year(), bucket.Start.Month(), bucket.Start.Day(),
+			bucket.Start.Hour(), 0, 0, 0, time.UTC)
+	}
+	return buckets, nil
+}
+
+func (r *PostgresAlertRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Alert, error) {
+	var alert models.Alert
+	err := r.db.WithContext(ctx).First(&alert, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAlertNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (r *PostgresAlertRepo) GetActiveByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error) {
+	var alert models.Alert
+	err := r.db.WithContext(ctx).
+		Where("fingerprint = ? AND status IN ?", fingerprint, models.ActiveAlertStatuses).
+		Order("triggered_at DESC").
+		First(&alert).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (r *PostgresAlertRepo) GetActive(ctx context.Context) ([]*models.Alert, error) {
+	var alerts []*models.Alert
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", models.ActiveAlertStatuses).
+		Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *PostgresAlertRepo) GetActiveByLabels(ctx context.Context, source string, labels map[string]string) ([]*models.Alert, error) {
+	labelsJSON, err := json.Marshal(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.WithContext(ctx).
+		Where("status IN ? AND labels @> ?::jsonb", models.ActiveAlertStatuses, string(labelsJSON))
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+
+	var alerts []*models.Alert
+	err = query.Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *PostgresAlertRepo) Upsert(ctx context.Context, alert *models.Alert) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"status", "severity", "message", "labels", "value",
+				"occurrence_count", "last_seen_at", "resolved_at", "resolved_by",
+				"acknowledged_at", "acknowledged_by", "comment",
+				"suppressed", "silence_id", "maintenance", "maintenance_id",
+				"inhibited", "inhibited_by", "updated_at",
+			}),
+		}).
+		Create(alert).Error
+}
+
+func (r *PostgresAlertRepo) CreateAction(ctx context.Context, action *models.AlertAction) error {
+	return r.db.WithContext(ctx).Create(action).Error
+}
+
+func (r *PostgresAlertRepo) GetActions(ctx context.Context, alertID uuid.UUID) ([]*models.AlertAction, error) {
+	var actions []*models.AlertAction
+	err := r.db.WithContext(ctx).
+		Where("alert_id = ?", alertID).
+		Order("created_at ASC").
+		Find(&actions).Error
+	return actions, err
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Alert sort fields
+const (
+	AlertSortTriggeredAt = "triggered_at"
+	AlertSortLastSeenAt  = "last_seen_at"
+)
+
+// AlertQuery selects a page of alerts. Empty filters match every alert.
+type AlertQuery struct {
+	Statuses        []models.AlertStatus
+	Severities      []string
+	Sources         []string
+	Labels          map[string]string // alerts whose labels contain every pair
+	TriggeredAfter  *time.Time        // inclusive
+	TriggeredBefore *time.Time        // exclusive
+	Search          string            // case-insensitive substring of the message
+	SortBy          string            // triggered_at (default) or last_seen_at
+	Ascending       bool              // oldest first instead of newest first
+	Cursor          string            // NextCursor of the previous page
+	Limit           int
+}
+
+// AlertPage is one page of alerts. NextCursor is empty on the last page.
+type AlertPage struct {
+	Alerts     []*models.Alert
+	NextCursor string
+}
+
+// alertCursor is the position after the last alert of a page: its sort value, with the ID
+// breaking ties between alerts with the same value
+type alertCursor struct {
+	at time.Time
+	id uuid.UUID
+}
+
+func encodeAlertCursor(at time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(at.UnixNano(), 10) + "_" + id.String()))
+}
+
+func decodeAlertCursor(cursor string) (*alertCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	at, id, ok := strings.Cut(string(raw), "_")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(at, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &alertCursor{at: time.Unix(0, nanos), id: parsedID}, nil
+}
+
+// sortColumn returns the column alerts are ordered by
+func (q *AlertQuery) sortColumn() string {
+	if q.SortBy == AlertSortLastSeenAt {
+		return AlertSortLastSeenAt
+	}
+	return AlertSortTriggeredAt
+}
+
+// sortValue returns the value of the alert the query sorts by
+func (q *AlertQuery) sortValue(alert *models.Alert) time.Time {
+	if q.SortBy == AlertSortLastSeenAt {
+		return alert.LastSeenAt
+	}
+	return alert.TriggeredAt
+}
+
+// before reports whether alert a comes before alert b in the query's order
+func (q *AlertQuery) before(a, b *models.Alert) bool {
+	return q.beforePosition(q.sortValue(a), a.ID, q.sortValue(b), b.ID)
+}
+
+func (q *AlertQuery) beforePosition(aAt time.Time, aID uuid.UUID, bAt time.Time, bID uuid.UUID) bool {
+	if !aAt.Equal(bAt) {
+		return aAt.Before(bAt) == q.Ascending
+	}
+	return (bytes.Compare(aID[:], bID[:]) < 0) == q.Ascending
+}
+
+// matches reports whether the alert passes every filter of the query
+func (q *AlertQuery) matches(alert *models.Alert) bool {
+	if len(q.Statuses) > 0 && !containsValue(q.Statuses, alert.Status) {
+		return false
+	}
+	if len(q.Severities) > 0 && !containsValue(q.Severities, alert.Severity) {
+		return false
+	}
+	if len(q.Sources) > 0 && !containsValue(q.Sources, alert.Source) {
+		return false
+	}
+	if len(q.Labels) > 0 && !matchesLabels(alert.GetLabelsMap(), q.Labels) {
+		return false
+	}
+	if q.TriggeredAfter != nil && alert.TriggeredAt.Before(*q.TriggeredAfter) {
+		return false
+	}
+	if q.TriggeredBefore != nil && !alert.TriggeredAt.Before(*q.TriggeredBefore) {
+		return false
+	}
+	if q.Search != "" && !strings.Contains(strings.ToLower(alert.Message), strings.ToLower(q.Search)) {
+		return false
+	}
+	return true
+}
+
+func containsValue[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeLike escapes the LIKE wildcards of a search term
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
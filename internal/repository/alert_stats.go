@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+)
+
+// ErrInvalidStatsQuery is returned for stats queries with an unknown bucket or group or an invalid range
+var ErrInvalidStatsQuery = errors.New("invalid stats query")
+
+// maxStatsBuckets bounds the time range of a stats query
+const maxStatsBuckets = 2000
+
+// Alert stats bucket sizes
+const (
+	StatsBucketHour = "hour"
+	StatsBucketDay  = "day"
+)
+
+// statsGroupColumns are the fields alert stats can be grouped by, with their SQL expressions
+var statsGroupColumns = map[string]string{
+	"severity":   "severity",
+	"source":     "source",
+	"alert_type": "COALESCE(labels->>'alert_type', '')",
+	"namespace":  "COALESCE(labels->>'namespace', '')",
+	"node":       "COALESCE(labels->>'node', '')",
+}
+
+// AlertStatsQuery counts the alerts triggered in [From, To) per time bucket and, optionally, per group
+type AlertStatsQuery struct {
+	From    time.Time
+	To      time.Time
+	Bucket  string // hour or day, in UTC
+	GroupBy string // severity, source, alert_type, namespace or node; empty counts all alerts together
+}
+
+// AlertStatsBucket is the number of alerts of a group triggered in a time bucket
+type AlertStatsBucket struct {
+	Start time.Time `json:"start"`
+	Group string    `json:"group,omitempty"`
+	Count int64     `json:"count"`
+}
+
+// Validate checks the bucket, group and range of the query
+func (q *AlertStatsQuery) Validate() error {
+	if q.Bucket != StatsBucketHour && q.Bucket != StatsBucketDay {
+		return fmt.Errorf("%w: bucket must be hour or day", ErrInvalidStatsQuery)
+	}
+	if _, ok := statsGroupColumns[q.GroupBy]; q.GroupBy != "" && !ok {
+		return fmt.Errorf("%w: cannot group by %q", ErrInvalidStatsQuery, q.GroupBy)
+	}
+	if !q.To.After(q.From) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidStatsQuery)
+	}
+	if q.To.Sub(q.From) > maxStatsBuckets*q.bucketSize() {
+		return fmt.Errorf("%w: range spans more than %d buckets", ErrInvalidStatsQuery, maxStatsBuckets)
+	}
+	return nil
+}
+
+func (q *AlertStatsQuery) bucketSize() time.Duration {
+	if q.Bucket == StatsBucketDay {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// truncate returns the start of the bucket the time falls in
+func (q *AlertStatsQuery) truncate(t time.Time) time.Time {
+	t = t.UTC()
+	if q.Bucket == StatsBucketDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// group returns the value of the alert the query groups by
+func (q *AlertStatsQuery) group(alert *models.Alert) string {
+	switch q.GroupBy {
+	case "":
+		return ""
+	case "severity":
+		return alert.Severity
+	case "source":
+		return alert.Source
+	default:
+		return alert.GetLabelsMap()[q.GroupBy]
+	}
+}
@@ -201,6 +201,274 @@ func TestInMemoryAlertRepo_ConcurrentAccess(t *testing.T) {
 	})
 }
 
+func TestInMemoryAlertRepo_GetActiveByFingerprint(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should return nil when no alert is firing", func(t *testing.T) {
+		repo := repository.NewInMemoryAlertRepo()
+
+		alert, err := repo.GetActiveByFingerprint(ctx, "missing")
+		assert.NoError(t, err)
+		assert.Nil(t, alert)
+	})
+
+	t.Run("should find firing alert and ignore resolved ones", func(t *testing.T) {
+		repo := repository.NewInMemoryAlertRepo()
+		labels := map[string]string{"alert_type": "node_not_ready", "node": "node-1"}
+
+		resolved := models.NewAlert("critical", "Node not ready", "k8s_node", 1, labels)
+		resolved.Resolve()
+		require.NoError(t, repo.Create(ctx, resolved))
+
+		firing := models.NewAlert("critical", "Node not ready", "k8s_node", 1, labels)
+		require.NoError(t, repo.Create(ctx, firing))
+
+		found, err := repo.GetActiveByFingerprint(ctx, firing.Fingerprint)
+		assert.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, firing.ID, found.ID)
+	})
+}
+
+func TestInMemoryAlertRepo_GetActive(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryAlertRepo()
+
+	firing := models.NewAlert("critical", "Node not ready", "k8s_node", 1, map[string]string{"node": "worker-1", "alert_type": "node_not_ready"})
+	acknowledged := models.NewAlert("high", "CPU high", "k8s_pod_metrics", 90, map[string]string{"namespace": "default", "pod": "web", "alert_type": "pod_cpu_high"})
+	require.NoError(t, acknowledged.Acknowledge("alice", ""))
+	resolved := models.NewAlert("high", "Pod failed", "k8s_pod", 1, map[string]string{"namespace": "default", "pod": "web", "alert_type": "pod_failed"})
+	resolved.Resolve()
+	for _, alert := range []*models.Alert{firing, acknowledged, resolved} {
+		require.NoError(t, repo.Create(ctx, alert))
+	}
+
+	alerts, err := repo.GetActive(ctx)
+	assert.NoError(t, err)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, firing.ID, alerts[0].ID)
+	assert.Equal(t, acknowledged.ID, alerts[1].ID)
+}
+
+func TestInMemoryAlertRepo_CountActive(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryAlertRepo()
+
+	firing := models.NewAlert("critical", "Node not ready", "k8s_node", 1, map[string]string{"node": "worker-1", "alert_type": "node_not_ready"})
+	acknowledged := models.NewAlert("high", "CPU high", "k8s_pod_metrics", 90, map[string]string{"namespace": "default", "pod": "web", "alert_type": "pod_cpu_high"})
+	require.NoError(t, acknowledged.Acknowledge("alice", ""))
+	resolved := models.NewAlert("high", "Pod failed", "k8s_pod", 1, map[string]string{"namespace": "default", "pod": "web", "alert_type": "pod_failed"})
+	resolved.Resolve()
+	for _, alert := range []*models.Alert{firing, acknowledged, resolved} {
+		require.NoError(t, repo.Create(ctx, alert))
+	}
+
+	count, err := repo.CountActive(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestInMemoryAlertRepo_GetActiveByLabels(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryAlertRepo()
+
+	web := models.NewAlert("high", "CPU high", "k8s_pod_metrics", 90, map[string]string{"namespace": "default", "pod": "web", "alert_type": "pod_cpu_high"})
+	api := models.NewAlert("high", "CPU high", "k8s_pod_metrics", 90, map[string]string{"namespace": "default", "pod": "api", "alert_type": "pod_cpu_high"})
+	other := models.NewAlert("high", "Pod failed", "k8s_pod", 1, map[string]string{"namespace": "default", "pod": "web", "alert_type": "pod_failed"})
+	resolved := models.NewAlert("high", "Memory high", "k8s_pod_metrics", 90, map[string]string{"namespace": "default", "pod": "web", "alert_type": "pod_memory_high"})
+	resolved.Resolve()
+	for _, alert := range []*models.Alert{web, api, other, resolved} {
+		require.NoError(t, repo.Create(ctx, alert))
+	}
+
+	t.Run("should match source and label subset", func(t *testing.T) {
+		alerts, err := repo.GetActiveByLabels(ctx, "k8s_pod_metrics", map[string]string{"namespace": "default", "pod": "web"})
+		assert.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, web.ID, alerts[0].ID)
+	})
+
+	t.Run("should match labels across sources without a source", func(t *testing.T) {
+		alerts, err := repo.GetActiveByLabels(ctx, "", map[string]string{"namespace": "default", "pod": "web"})
+		assert.NoError(t, err)
+		assert.Len(t, alerts, 2)
+	})
+
+	t.Run("should return all firing alerts for source with empty labels", func(t *testing.T) {
+		alerts, err := repo.GetActiveByLabels(ctx, "k8s_pod_metrics", map[string]string{})
+		assert.NoError(t, err)
+		assert.Len(t, alerts, 2)
+	})
+}
+
+func TestInMemoryAlertRepo_Upsert(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should insert new alert", func(t *testing.T) {
+		repo := repository.NewInMemoryAlertRepo()
+		alert := models.NewAlert("high", "Test", "test", 1, nil)
+
+		require.NoError(t, repo.Upsert(ctx, alert))
+
+		count, err := repo.Count(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("should update existing alert in place", func(t *testing.T) {
+		repo := repository.NewInMemoryAlertRepo()
+		alert := models.NewAlert("high", "Test", "test", 1, nil)
+		require.NoError(t, repo.Create(ctx, alert))
+
+		found, err := repo.GetActiveByFingerprint(ctx, alert.Fingerprint)
+		require.NoError(t, err)
+		found.Touch(42, "")
+		require.NoError(t, repo.Upsert(ctx, found))
+
+		alerts, err := repo.GetRecent(ctx, 10)
+		assert.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, 2, alerts[0].OccurrenceCount)
+		assert.Equal(t, 42.0, alerts[0].Value)
+	})
+}
+
+func TestInMemoryAlertRepo_Query(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryAlertRepo()
+	base := time.Now().Add(-time.Hour)
+
+	newAlert := func(minutes int, severity, source, message string, labels map[string]string) *models.Alert {
+		alert := models.NewAlert(severity, message, source, 1, labels)
+		alert.TriggeredAt = base.Add(time.Duration(minutes) * time.Minute)
+		alert.LastSeenAt = alert.TriggeredAt
+		require.NoError(t, repo.Create(ctx, alert))
+		return alert
+	}
+	crashLoop := newAlert(0, "critical", "k8s_pod", "Pod default/api-0 is in CrashLoopBackOff", map[string]string{"namespace": "default", "pod": "api-0"})
+	oomKilled := newAlert(10, "high", "k8s_pod", "Pod default/api-1 was OOMKilled", map[string]string{"namespace": "default", "pod": "api-1"})
+	nodeCPU := newAlert(20, "medium", "k8s_node", "Node worker-1 CPU at 95%", map[string]string{"node": "worker-1"})
+	stagingPod := newAlert(30, "high", "k8s_pod", "Pod staging/web-0 is in CrashLoopBackOff", map[string]string{"namespace": "staging", "pod": "web-0"})
+	stagingPod.Resolve()
+
+	ids := func(page *repository.AlertPage) []uuid.UUID {
+		var result []uuid.UUID
+		for _, alert := range page.Alerts {
+			result = append(result, alert.ID)
+		}
+		return result
+	}
+
+	t.Run("should return every alert newest first", func(t *testing.T) {
+		page, err := repo.Query(ctx, &repository.AlertQuery{Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{stagingPod.ID, nodeCPU.ID, oomKilled.ID, crashLoop.ID}, ids(page))
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("should filter by status, severity, source, labels, time range and message", func(t *testing.T) {
+		page, err := repo.Query(ctx, &repository.AlertQuery{Statuses: []models.AlertStatus{models.AlertStatusFiring}, Severities: []string{"critical", "high"}, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{oomKilled.ID, crashLoop.ID}, ids(page))
+
+		page, err = repo.Query(ctx, &repository.AlertQuery{Sources: []string{"k8s_node"}, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{nodeCPU.ID}, ids(page))
+
+		page, err = repo.Query(ctx, &repository.AlertQuery{Labels: map[string]string{"namespace": "default", "pod": "api-1"}, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{oomKilled.ID}, ids(page))
+
+		after, before := base.Add(10*time.Minute), base.Add(30*time.Minute)
+		page, err = repo.Query(ctx, &repository.AlertQuery{TriggeredAfter: &after, TriggeredBefore: &before, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{nodeCPU.ID, oomKilled.ID}, ids(page))
+
+		page, err = repo.Query(ctx, &repository.AlertQuery{Search: "crashloop", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{stagingPod.ID, crashLoop.ID}, ids(page))
+	})
+
+	t.Run("should page through the alerts with cursors", func(t *testing.T) {
+		query := &repository.AlertQuery{Ascending: true, Limit: 3}
+		page, err := repo.Query(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{crashLoop.ID, oomKilled.ID, nodeCPU.ID}, ids(page))
+		require.NotEmpty(t, page.NextCursor)
+
+		query.Cursor = page.NextCursor
+		page, err = repo.Query(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{stagingPod.ID}, ids(page))
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("should reject invalid cursors", func(t *testing.T) {
+		_, err := repo.Query(ctx, &repository.AlertQuery{Cursor: "not-a-cursor", Limit: 10})
+		assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+	})
+}
+
+func TestInMemoryAlertRepo_Stats(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryAlertRepo()
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	for _, a := range []struct {
+		at       time.Time
+		severity string
+		node     string
+	}{
+		{day.Add(9*time.Hour + 5*time.Minute), "critical", "worker-1"},
+		{day.Add(9*time.Hour + 50*time.Minute), "high", "worker-1"},
+		{day.Add(10*time.Hour + 15*time.Minute), "critical", "worker-2"},
+		{day.Add(26 * time.Hour), "low", "worker-1"},
+		{day.Add(-time.Hour), "critical", "worker-1"}, // before the range
+	} {
+		alert := models.NewAlert(a.severity, "test", "k8s_node", 1, map[string]string{"node": a.node})
+		alert.TriggeredAt = a.at
+		require.NoError(t, repo.Create(ctx, alert))
+	}
+
+	t.Run("should count alerts per hour", func(t *testing.T) {
+		buckets, err := repo.Stats(ctx, &repository.AlertStatsQuery{From: day, To: day.Add(48 * time.Hour), Bucket: repository.StatsBucketHour})
+		require.NoError(t, err)
+		require.Len(t, buckets, 3)
+		assert.Equal(t, &repository.AlertStatsBucket{Start: day.Add(9 * time.Hour), Count: 2}, buckets[0])
+		assert.Equal(t, &repository.AlertStatsBucket{Start: day.Add(10 * time.Hour), Count: 1}, buckets[1])
+		assert.Equal(t, &repository.AlertStatsBucket{Start: day.Add(26 * time.Hour), Count: 1}, buckets[2])
+	})
+
+	t.Run("should count alerts per day and group", func(t *testing.T) {
+		buckets, err := repo.Stats(ctx, &repository.AlertStatsQuery{From: day, To: day.Add(48 * time.Hour), Bucket: repository.StatsBucketDay, GroupBy: "node"})
+		require.NoError(t, err)
+		assert.Equal(t, []*repository.AlertStatsBucket{
+			{Start: day, Group: "worker-1", Count: 2},
+			{Start: day, Group: "worker-2", Count: 1},
+			{Start: day.Add(24 * time.Hour), Group: "worker-1", Count: 1},
+		}, buckets)
+
+		buckets, err = repo.Stats(ctx, &repository.AlertStatsQuery{From: day, To: day.Add(24 * time.Hour), Bucket: repository.StatsBucketDay, GroupBy: "severity"})
+		require.NoError(t, err)
+		assert.Equal(t, []*repository.AlertStatsBucket{
+			{Start: day, Group: "critical", Count: 2},
+			{Start: day, Group: "high", Count: 1},
+		}, buckets)
+	})
+
+	t.Run("should reject invalid queries", func(t *testing.T) {
+		for _, query := range []*repository.AlertStatsQuery{
+			{From: day, To: day.Add(time.Hour), Bucket: "week"},
+			{From: day, To: day.Add(time.Hour), Bucket: repository.StatsBucketHour, GroupBy: "pod"},
+			{From: day, To: day, Bucket: repository.StatsBucketHour},
+			{From: day, To: day.AddDate(1, 0, 0), Bucket: repository.StatsBucketHour},
+		} {
+			_, err := repo.Stats(ctx, query)
+			assert.ErrorIs(t, err, repository.ErrInvalidStatsQuery)
+		}
+	})
+}
+
 func TestNewInMemoryAlertRepo(t *testing.T) {
 	repo := repository.NewInMemoryAlertRepo()
 	assert.NotNil(t, repo)
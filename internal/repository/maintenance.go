@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"gorm.io/gorm"
+)
+
+// MaintenanceRepo interface for maintenance window storage
+type MaintenanceRepo interface {
+	Create(ctx context.Context, window *models.MaintenanceWindow) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.MaintenanceWindow, error)
+	// List returns all maintenance windows, newest first
+	List(ctx context.Context) ([]*models.MaintenanceWindow, error)
+	Update(ctx context.Context, window *models.MaintenanceWindow) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// GetInEffect returns windows whose start/end bounds include now; recurring windows
+	// still need IsActive to check the schedule
+	GetInEffect(ctx context.Context, now time.Time) ([]*models.MaintenanceWindow, error)
+}
+
+// ErrMaintenanceWindowNotFound is returned when a maintenance window does not exist
+var ErrMaintenanceWindowNotFound = errors.New("maintenance window not found")
+
+// InMemoryMaintenanceRepo stores maintenance windows in memory
+type InMemoryMaintenanceRepo struct {
+	windows map[uuid.UUID]*models.MaintenanceWindow
+	mu      sync.RWMutex
+}
+
+func NewInMemoryMaintenanceRepo() MaintenanceRepo {
+	return &InMemoryMaintenanceRepo{
+		windows: make(map[uuid.UUID]*models.MaintenanceWindow),
+	}
+}
+
+func (r *InMemoryMaintenanceRepo) Create(ctx context.Context, window *models.MaintenanceWindow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	window.CreatedAt = now
+	window.UpdatedAt = now
+	r.windows[window.ID] = window
+	return nil
+}
+
+func (r *InMemoryMaintenanceRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.MaintenanceWindow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	window, ok := r.windows[id]
+	if !ok {
+		return nil, ErrMaintenanceWindowNotFound
+	}
+	found := *window
+	return &found, nil
+}
+
+func (r *InMemoryMaintenanceRepo) List(ctx context.Context) ([]*models.MaintenanceWindow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	windows := make([]*models.MaintenanceWindow, 0, len(r.windows))
+	for _, window := range r.windows {
+		found := *window
+		windows = append(windows, &found)
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].CreatedAt.After(windows[j].CreatedAt)
+	})
+	return windows, nil
+}
+
+func (r *InMemoryMaintenanceRepo) Update(ctx context.Context, window *models.MaintenanceWindow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.windows[window.ID]
+	if !ok {
+		return ErrMaintenanceWindowNotFound
+	}
+	window.CreatedAt = existing.CreatedAt
+	window.UpdatedAt = time.Now()
+	r.windows[window.ID] = window
+	return nil
+}
+
+func (r *InMemoryMaintenanceRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.windows[id]; !ok {
+		return ErrMaintenanceWindowNotFound
+	}
+	delete(r.windows, id)
+	return nil
+}
+
+func (r *InMemoryMaintenanceRepo) GetInEffect(ctx context.Context, now time.Time) ([]*models.MaintenanceWindow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var windows []*models.MaintenanceWindow
+	for _, window := range r.windows {
+		if now.Before(window.StartsAt) || (window.EndsAt != nil && !now.Before(*window.EndsAt)) {
+			continue
+		}
+		found := *window
+		windows = append(windows, &found)
+	}
+	return windows, nil
+}
+
+// PostgresMaintenanceRepo stores maintenance windows in PostgreSQL
+type PostgresMaintenanceRepo struct {
+	db *gorm.DB
+}
+
+func NewPostgresMaintenanceRepo(db *gorm.DB) MaintenanceRepo {
+	return &PostgresMaintenanceRepo{db: db}
+}
+
+func (r *PostgresMaintenanceRepo) Create(ctx context.Context, window *models.MaintenanceWindow) error {
+	return r.db.WithContext(ctx).Create(window).Error
+}
+
+func (r *PostgresMaintenanceRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.MaintenanceWindow, error) {
+	var window models.MaintenanceWindow
+	err := r.db.WithContext(ctx).First(&window, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMaintenanceWindowNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+func (r *PostgresMaintenanceRepo) List(ctx context.Context) ([]*models.MaintenanceWindow, error) {
+	var windows []*models.MaintenanceWindow
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Find(&windows).Error
+	return windows, err
+}
+
+func (r *PostgresMaintenanceRepo) Update(ctx context.Context, window *models.MaintenanceWindow) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.MaintenanceWindow{}).
+		Where("id = ?", window.ID).
+		Updates(map[string]interface{}{
+			"name":             window.Name,
+			"nodes":            window.Nodes,
+			"namespaces":       window.Namespaces,
+			"matchers":         window.Matchers,
+			"starts_at":        window.StartsAt,
+			"ends_at":          window.EndsAt,
+			"schedule":         window.Schedule,
+			"duration_minutes": window.DurationMinutes,
+			"timezone":         window.Timezone,
+			"created_by":       window.CreatedBy,
+			"comment":          window.Comment,
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMaintenanceWindowNotFound
+	}
+	return nil
+}
+
+func (r *PostgresMaintenanceRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&models.MaintenanceWindow{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMaintenanceWindowNotFound
+	}
+	return nil
+}
+
+func (r *PostgresMaintenanceRepo) GetInEffect(ctx context.Context, now time.Time) ([]*models.MaintenanceWindow, error) {
+	var windows []*models.MaintenanceWindow
+	err := r.db.WithContext(ctx).
+		Where("starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", now, now).
+		Find(&windows).Error
+	return windows, err
+}
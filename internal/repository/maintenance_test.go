@@ -0,0 +1,72 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func newTestWindow(startsAt time.Time, endsAt *time.Time) *models.MaintenanceWindow {
+	return &models.MaintenanceWindow{
+		ID:        uuid.New(),
+		Name:      "node upgrade",
+		Nodes:     datatypes.NewJSONSlice([]string{"node-1"}),
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		CreatedBy: "alice",
+	}
+}
+
+func TestInMemoryMaintenanceRepo_CRUD(t *testing.T) {
+	repo := repository.NewInMemoryMaintenanceRepo()
+	ctx := context.Background()
+
+	endsAt := time.Now().Add(time.Hour)
+	window := newTestWindow(time.Now(), &endsAt)
+	require.NoError(t, repo.Create(ctx, window))
+
+	found, err := repo.GetByID(ctx, window.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "node upgrade", found.Name)
+
+	updated := newTestWindow(window.StartsAt, &endsAt)
+	updated.ID = window.ID
+	updated.Name = "node upgrade (extended)"
+	require.NoError(t, repo.Update(ctx, updated))
+
+	windows, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	assert.Equal(t, "node upgrade (extended)", windows[0].Name)
+
+	require.NoError(t, repo.Delete(ctx, window.ID))
+	_, err = repo.GetByID(ctx, window.ID)
+	assert.ErrorIs(t, err, repository.ErrMaintenanceWindowNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, window.ID), repository.ErrMaintenanceWindowNotFound)
+}
+
+func TestInMemoryMaintenanceRepo_GetInEffect(t *testing.T) {
+	repo := repository.NewInMemoryMaintenanceRepo()
+	ctx := context.Background()
+	now := time.Now()
+
+	pastEnd := now.Add(-time.Hour)
+	current := newTestWindow(now.Add(-time.Minute), nil) // recurring windows may have no end
+	expired := newTestWindow(now.Add(-2*time.Hour), &pastEnd)
+	future := newTestWindow(now.Add(time.Hour), nil)
+	for _, w := range []*models.MaintenanceWindow{current, expired, future} {
+		require.NoError(t, repo.Create(ctx, w))
+	}
+
+	windows, err := repo.GetInEffect(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	assert.Equal(t, current.ID, windows[0].ID)
+}
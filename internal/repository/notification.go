@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRepo interface for notification log storage
+type NotificationRepo interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	// Update stores the delivery state, error and attempts of a notification
+	Update(ctx context.Context, notification *models.Notification) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Notification, error)
+	// GetByAlert returns the notifications of an alert, oldest first
+	GetByAlert(ctx context.Context, alertID uuid.UUID) ([]*models.Notification, error)
+	// List returns up to limit notifications in the given state, or in any state if empty, newest first
+	List(ctx context.Context, state models.NotificationState, limit int) ([]*models.Notification, error)
+	// ClaimDue returns up to limit queued notifications whose next attempt is due and defers their
+	// next attempt to leaseUntil, so a crashed delivery is retried but no one else picks them up
+	// meanwhile. A notification waits while an earlier one of its alert to the same channel and
+	// receiver is still queued, so each target receives an alert's events in order, unless both
+	// are due in the same group and so are delivered together.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*models.Notification, error)
+}
+
+// queuedStates are the states of notifications the outbox still has to deliver
+var queuedStates = []models.NotificationState{models.NotificationStatePending, models.NotificationStateFailed}
+
+// ErrNotificationNotFound is returned when a notification does not exist
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// InMemoryNotificationRepo stores notifications in memory
+type InMemoryNotificationRepo struct {
+	notifications map[uuid.UUID]*models.Notification
+	mu            sync.RWMutex
+}
+
+func NewInMemoryNotificationRepo() NotificationRepo {
+	return &InMemoryNotificationRepo{
+		notifications: make(map[uuid.UUID]*models.Notification),
+	}
+}
+
+func (r *InMemoryNotificationRepo) Create(ctx context.Context, notification *models.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *notification
+	r.notifications[notification.ID] = &stored
+	return nil
+}
+
+func (r *InMemoryNotificationRepo) Update(ctx context.Context, notification *models.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.notifications[notification.ID]; !ok {
+		return ErrNotificationNotFound
+	}
+	stored := *notification
+	stored.UpdatedAt = time.Now()
+	r.notifications[notification.ID] = &stored
+	return nil
+}
+
+func (r *InMemoryNotificationRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notification, ok := r.notifications[id]
+	if !ok {
+		return nil, ErrNotificationNotFound
+	}
+	found := *notification
+	return &found, nil
+}
+
+func (r *InMemoryNotificationRepo) GetByAlert(ctx context.Context, alertID uuid.UUID) ([]*models.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notifications := make([]*models.Notification, 0)
+	for _, notification := range r.notifications {
+		if notification.AlertID == alertID {
+			found := *notification
+			notifications = append(notifications, &found)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.Before(notifications[j].CreatedAt)
+	})
+	return notifications, nil
+}
+
+func (r *InMemoryNotificationRepo) List(ctx context.Context, state models.NotificationState, limit int) ([]*models.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notifications := make([]*models.Notification, 0)
+	for _, notification := range r.notifications {
+		if state == "" || notification.State == state {
+			found := *notification
+			notifications = append(notifications, &found)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	})
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
+
+func (r *InMemoryNotificationRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*models.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*models.Notification
+	for _, notification := range r.notifications {
+		if notification.IsQueued() && !notification.NextAttemptAt.After(now) && !r.hasEarlierQueued(notification, now) {
+			due = append(due, notification)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(*due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+		}
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*models.Notification, 0, len(due))
+	for _, notification := range due {
+		notification.ScheduleAttempt(leaseUntil)
+		found := *notification
+		claimed = append(claimed, &found)
+	}
+	return claimed, nil
+}
+
+// hasEarlierQueued reports whether an older notification of the same alert to the same target is
+// still queued, other than one due in the same group. Callers must hold r.mu.
+func (r *InMemoryNotificationRepo) hasEarlierQueued(notification *models.Notification, now time.Time) bool {
+	for _, other := range r.notifications {
+		if other.AlertID != notification.AlertID || other.Channel != notification.Channel ||
+			other.Receiver != notification.Receiver || !other.IsQueued() || !other.CreatedAt.Before(notification.CreatedAt) {
+			continue
+		}
+		if notification.GroupKey == "" || other.GroupKey != notification.GroupKey || other.NextAttemptAt.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// PostgresNotificationRepo stores notifications in PostgreSQL
+type PostgresNotificationRepo struct {
+	db *gorm.DB
+}
+
+func NewPostgresNotificationRepo(db *gorm.DB) NotificationRepo {
+	return &PostgresNotificationRepo{db: db}
+}
+
+func (r *PostgresNotificationRepo) Create(ctx context.Context, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+func (r *PostgresNotificationRepo) Update(ctx context.Context, notification *models.Notification) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ?", notification.ID).
+		Updates(map[string]interface{}{
+			"state":           notification.State,
+			"error":           notification.Error,
+			"attempts":        notification.Attempts,
+			"last_attempt_at": notification.LastAttemptAt,
+			"next_attempt_at": notification.NextAttemptAt,
+			"delivered_at":    notification.DeliveredAt,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.WithContext(ctx).First(&notification, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotificationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *PostgresNotificationRepo) GetByAlert(ctx context.Context, alertID uuid.UUID) ([]*models.Notification, error) {
+	var notifications []*models.Notification
+	err := r.db.WithContext(ctx).
+		Where("alert_id = ?", alertID).
+		Order("created_at ASC").
+		Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *PostgresNotificationRepo) List(ctx context.Context, state models.NotificationState, limit int) ([]*models.Notification, error) {
+	query := r.db.WithContext(ctx)
+	if state != "" {
+		query = query.Where("state = ?", state)
+	}
+
+	var notifications []*models.Notification
+	err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *PostgresNotificationRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*models.Notification, error) {
+	var notifications []*models.Notification
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// SKIP LOCKED lets several instances poll the outbox without delivering a notification twice
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("state IN ? AND next_attempt_at <= ?", queuedStates, now).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM notifications earlier
+				WHERE earlier.alert_id = notifications.alert_id
+				  AND earlier.channel = notifications.channel
+				  AND earlier.receiver = notifications.receiver
+				  AND earlier.state IN ?
+				  AND earlier.next_attempt_at IS NOT NULL
+				  AND earlier.created_at < notifications.created_at
+				  AND (notifications.group_key = '' OR earlier.group_key <> notifications.group_key OR earlier.next_attempt_at > ?))`,
+				queuedStates, now).
+			Order("next_attempt_at, created_at").
+			Limit(limit).
+			Find(&notifications).Error
+		if err != nil || len(notifications) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(notifications))
+		for _, notification := range notifications {
+			ids = append(ids, notification.ID)
+			notification.ScheduleAttempt(leaseUntil)
+		}
+		return tx.Model(&models.Notification{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
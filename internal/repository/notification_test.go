@@ -0,0 +1,141 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryNotificationRepo(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryNotificationRepo()
+	alertID := uuid.New()
+
+	first := models.NewNotification(alertID, models.ChannelTypeEmail, "", "firing")
+	require.NoError(t, repo.Create(ctx, first))
+	second := models.NewNotification(alertID, models.ChannelTypePagerDuty, "oncall", "firing")
+	second.CreatedAt = first.CreatedAt.Add(1)
+	require.NoError(t, repo.Create(ctx, second))
+	require.NoError(t, repo.Create(ctx, models.NewNotification(uuid.New(), models.ChannelTypeEmail, "", "firing")))
+
+	t.Run("should record the outcome of an attempt", func(t *testing.T) {
+		first.RecordAttempt(errors.New("smtp timeout"))
+		require.NoError(t, repo.Update(ctx, first))
+
+		notifications, err := repo.GetByAlert(ctx, alertID)
+		require.NoError(t, err)
+		require.Len(t, notifications, 2)
+		assert.Equal(t, first.ID, notifications[0].ID)
+		assert.Equal(t, models.NotificationStateFailed, notifications[0].State)
+		assert.Equal(t, "smtp timeout", notifications[0].Error)
+		assert.Equal(t, 1, notifications[0].Attempts)
+		assert.Equal(t, models.NotificationStatePending, notifications[1].State)
+	})
+
+	t.Run("should not update unknown notifications", func(t *testing.T) {
+		err := repo.Update(ctx, models.NewNotification(alertID, models.ChannelTypeEmail, "", "firing"))
+		assert.ErrorIs(t, err, repository.ErrNotificationNotFound)
+	})
+}
+
+func TestInMemoryNotificationRepo_ClaimDue(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryNotificationRepo()
+	now := time.Now()
+	alertID := uuid.New()
+
+	firing := models.NewNotification(alertID, models.ChannelTypeSlack, "", "firing")
+	firing.ScheduleAttempt(now.Add(-time.Minute))
+	require.NoError(t, repo.Create(ctx, firing))
+	resolved := models.NewNotification(alertID, models.ChannelTypeSlack, "", "resolved")
+	resolved.CreatedAt = firing.CreatedAt.Add(time.Second)
+	resolved.ScheduleAttempt(now.Add(-time.Minute))
+	require.NoError(t, repo.Create(ctx, resolved))
+	later := models.NewNotification(uuid.New(), models.ChannelTypeEmail, "", "firing")
+	later.ScheduleAttempt(now.Add(time.Hour))
+	require.NoError(t, repo.Create(ctx, later))
+	logged := models.NewNotification(uuid.New(), models.ChannelTypeEmail, "team-a", "firing")
+	require.NoError(t, repo.Create(ctx, logged))
+
+	t.Run("should claim due notifications in order per target", func(t *testing.T) {
+		claimed, err := repo.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, firing.ID, claimed[0].ID)
+
+		again, err := repo.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+		require.NoError(t, err)
+		assert.Empty(t, again, "claimed notifications are leased")
+	})
+
+	t.Run("should claim the next notification once the earlier one is delivered", func(t *testing.T) {
+		firing.RecordAttempt(nil)
+		require.NoError(t, repo.Update(ctx, firing))
+
+		claimed, err := repo.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, resolved.ID, claimed[0].ID)
+	})
+
+	t.Run("should list notifications by state", func(t *testing.T) {
+		resolved.RecordAttempt(errors.New("rate limited"))
+		resolved.GiveUp()
+		require.NoError(t, repo.Update(ctx, resolved))
+
+		dead, err := repo.List(ctx, models.NotificationStateDead, 10)
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.Equal(t, resolved.ID, dead[0].ID)
+
+		all, err := repo.List(ctx, "", 2)
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+	})
+}
+
+func TestInMemoryNotificationRepo_ClaimDueGroups(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryNotificationRepo()
+	now := time.Now()
+	alertID := uuid.New()
+
+	queue := func(event, groupKey string, at time.Time, offset time.Duration) *models.Notification {
+		notification := models.NewNotification(alertID, models.ChannelTypeEmail, "team-a", event)
+		notification.GroupKey = groupKey
+		notification.CreatedAt = notification.CreatedAt.Add(offset)
+		notification.ScheduleAttempt(at)
+		require.NoError(t, repo.Create(ctx, notification))
+		return notification
+	}
+	firing := queue("firing", "root|node=worker-1", now.Add(-time.Second), 0)
+	resolved := queue("resolved", "root|node=worker-1", now.Add(-time.Second), time.Second)
+	queue("acknowledged", "", now.Add(-time.Second), 2*time.Second)
+
+	t.Run("should claim the due notifications of an alert's group together", func(t *testing.T) {
+		claimed, err := repo.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 2)
+		assert.Equal(t, firing.ID, claimed[0].ID)
+		assert.Equal(t, resolved.ID, claimed[1].ID)
+	})
+
+	t.Run("should hold back later notifications of the group while an earlier one is retried", func(t *testing.T) {
+		firing.RecordAttempt(errors.New("smtp timeout"))
+		firing.ScheduleAttempt(now.Add(time.Minute))
+		require.NoError(t, repo.Update(ctx, firing))
+		resolved.ScheduleAttempt(now.Add(-time.Second))
+		require.NoError(t, repo.Update(ctx, resolved))
+
+		claimed, err := repo.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+		require.NoError(t, err)
+		assert.Empty(t, claimed, "neither the resolution nor the acknowledgement overtakes the firing notification")
+	})
+}
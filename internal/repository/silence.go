@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"gorm.io/gorm"
+)
+
+// SilenceRepo interface for silence storage
+type SilenceRepo interface {
+	Create(ctx context.Context, silence *models.Silence) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Silence, error)
+	// List returns all silences, newest first
+	List(ctx context.Context) ([]*models.Silence, error)
+	Update(ctx context.Context, silence *models.Silence) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// GetActive returns the silences in effect at the given time
+	GetActive(ctx context.Context, now time.Time) ([]*models.Silence, error)
+}
+
+// ErrSilenceNotFound is returned when a silence does not exist
+var ErrSilenceNotFound = errors.New("silence not found")
+
+// InMemorySilenceRepo stores silences in memory
+type InMemorySilenceRepo struct {
+	silences map[uuid.UUID]*models.Silence
+	mu       sync.RWMutex
+}
+
+func NewInMemorySilenceRepo() SilenceRepo {
+	return &InMemorySilenceRepo{
+		silences: make(map[uuid.UUID]*models.Silence),
+	}
+}
+
+func (r *InMemorySilenceRepo) Create(ctx context.Context, silence *models.Silence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	silence.CreatedAt = now
+	silence.UpdatedAt = now
+	r.silences[silence.ID] = silence
+	return nil
+}
+
+func (r *InMemorySilenceRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Silence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	silence, ok := r.silences[id]
+	if !ok {
+		return nil, ErrSilenceNotFound
+	}
+	found := *silence
+	return &found, nil
+}
+
+func (r *InMemorySilenceRepo) List(ctx context.Context) ([]*models.Silence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	silences := make([]*models.Silence, 0, len(r.silences))
+	for _, silence := range r.silences {
+		found := *silence
+		silences = append(silences, &found)
+	}
+	sort.Slice(silences, func(i, j int) bool {
+		return silences[i].CreatedAt.After(silences[j].CreatedAt)
+	})
+	return silences, nil
+}
+
+func (r *InMemorySilenceRepo) Update(ctx context.Context, silence *models.Silence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.silences[silence.ID]
+	if !ok {
+		return ErrSilenceNotFound
+	}
+	silence.CreatedAt = existing.CreatedAt
+	silence.UpdatedAt = time.Now()
+	r.silences[silence.ID] = silence
+	return nil
+}
+
+func (r *InMemorySilenceRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.silences[id]; !ok {
+		return ErrSilenceNotFound
+	}
+	delete(r.silences, id)
+	return nil
+}
+
+func (r *InMemorySilenceRepo) GetActive(ctx context.Context, now time.Time) ([]*models.Silence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var silences []*models.Silence
+	for _, silence := range r.silences {
+		if silence.IsActive(now) {
+			found := *silence
+			silences = append(silences, &found)
+		}
+	}
+	return silences, nil
+}
+
+// PostgresSilenceRepo stores silences in PostgreSQL
+type PostgresSilenceRepo struct {
+	db *gorm.DB
+}
+
+func NewPostgresSilenceRepo(db *gorm.DB) SilenceRepo {
+	return &PostgresSilenceRepo{db: db}
+}
+
+func (r *PostgresSilenceRepo) Create(ctx context.Context, silence *models.Silence) error {
+	return r.db.WithContext(ctx).Create(silence).Error
+}
+
+func (r *PostgresSilenceRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Silence, error) {
+	var silence models.Silence
+	err := r.db.WithContext(ctx).First(&silence, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSilenceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &silence, nil
+}
+
+func (r *PostgresSilenceRepo) List(ctx context.Context) ([]*models.Silence, error) {
+	var silences []*models.Silence
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Find(&silences).Error
+	return silences, err
+}
+
+func (r *PostgresSilenceRepo) Update(ctx context.Context, silence *models.Silence) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Silence{}).
+		Where("id = ?", silence.ID).
+		Updates(map[string]interface{}{
+			"matchers":   silence.Matchers,
+			"starts_at":  silence.StartsAt,
+			"ends_at":    silence.EndsAt,
+			"created_by": silence.CreatedBy,
+			"comment":    silence.Comment,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSilenceNotFound
+	}
+	return nil
+}
+
+func (r *PostgresSilenceRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&models.Silence{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSilenceNotFound
+	}
+	return nil
+}
+
+func (r *PostgresSilenceRepo) GetActive(ctx context.Context, now time.Time) ([]*models.Silence, error) {
+	var silences []*models.Silence
+	err := r.db.WithContext(ctx).
+		Where("starts_at <= ? AND ends_at > ?", now, now).
+		Find(&silences).Error
+	return silences, err
+}
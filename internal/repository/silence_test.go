@@ -0,0 +1,83 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSilence(startsAt, endsAt time.Time) *models.Silence {
+	return models.NewSilence(
+		[]models.Matcher{{Name: "namespace", Operator: models.MatchEqual, Value: "staging"}},
+		startsAt, endsAt, "alice", "deploy",
+	)
+}
+
+func TestInMemorySilenceRepo_CRUD(t *testing.T) {
+	repo := repository.NewInMemorySilenceRepo()
+	ctx := context.Background()
+
+	silence := newTestSilence(time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, repo.Create(ctx, silence))
+
+	t.Run("should get silence by id", func(t *testing.T) {
+		found, err := repo.GetByID(ctx, silence.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", found.CreatedBy)
+		assert.Len(t, found.Matchers, 1)
+	})
+
+	t.Run("should update silence and keep creation time", func(t *testing.T) {
+		updated := newTestSilence(silence.StartsAt, silence.EndsAt.Add(time.Hour))
+		updated.ID = silence.ID
+		updated.Comment = "extended"
+		require.NoError(t, repo.Update(ctx, updated))
+
+		found, err := repo.GetByID(ctx, silence.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "extended", found.Comment)
+		assert.Equal(t, silence.CreatedAt, found.CreatedAt)
+	})
+
+	t.Run("should list silences", func(t *testing.T) {
+		silences, err := repo.List(ctx)
+		require.NoError(t, err)
+		assert.Len(t, silences, 1)
+	})
+
+	t.Run("should delete silence", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, silence.ID))
+
+		_, err := repo.GetByID(ctx, silence.ID)
+		assert.ErrorIs(t, err, repository.ErrSilenceNotFound)
+	})
+
+	t.Run("should return not found for unknown silence", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Delete(ctx, uuid.New()), repository.ErrSilenceNotFound)
+		assert.ErrorIs(t, repo.Update(ctx, newTestSilence(time.Now(), time.Now().Add(time.Hour))), repository.ErrSilenceNotFound)
+	})
+}
+
+func TestInMemorySilenceRepo_GetActive(t *testing.T) {
+	repo := repository.NewInMemorySilenceRepo()
+	ctx := context.Background()
+	now := time.Now()
+
+	active := newTestSilence(now.Add(-time.Minute), now.Add(time.Hour))
+	expired := newTestSilence(now.Add(-2*time.Hour), now.Add(-time.Hour))
+	future := newTestSilence(now.Add(time.Hour), now.Add(2*time.Hour))
+	for _, s := range []*models.Silence{active, expired, future} {
+		require.NoError(t, repo.Create(ctx, s))
+	}
+
+	silences, err := repo.GetActive(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, silences, 1)
+	assert.Equal(t, active.ID, silences[0].ID)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Transactor runs writes to several repositories as one unit
+type Transactor interface {
+	// WithinTx calls fn with repositories bound to a single transaction, committed if fn returns nil
+	WithinTx(ctx context.Context, fn func(alerts AlertRepo, notifications NotificationRepo) error) error
+}
+
+// InMemoryTransactor passes the in-memory repositories through; writes are not rolled back on error
+type InMemoryTransactor struct {
+	alerts        AlertRepo
+	notifications NotificationRepo
+}
+
+func NewInMemoryTransactor(alerts AlertRepo, notifications NotificationRepo) Transactor {
+	return &InMemoryTransactor{alerts: alerts, notifications: notifications}
+}
+
+func (t *InMemoryTransactor) WithinTx(ctx context.Context, fn func(alerts AlertRepo, notifications NotificationRepo) error) error {
+	return fn(t.alerts, t.notifications)
+}
+
+// PostgresTransactor runs the writes in a PostgreSQL transaction
+type PostgresTransactor struct {
+	db *gorm.DB
+}
+
+func NewPostgresTransactor(db *gorm.DB) Transactor {
+	return &PostgresTransactor{db: db}
+}
+
+func (t *PostgresTransactor) WithinTx(ctx context.Context, fn func(alerts AlertRepo, notifications NotificationRepo) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewPostgresAlertRepo(tx), NewPostgresNotificationRepo(tx))
+	})
+}
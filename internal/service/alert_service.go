@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
 	"github.com/monitoring-engine/monitoring-tool/internal/repository"
 )
 
@@ -19,19 +22,30 @@ type SeverityCounts struct {
 type AlertService interface {
 	CreateAlert(ctx context.Context, alert *models.Alert) error
 	GetRecentAlerts(ctx context.Context, limit int) ([]*models.Alert, error)
+	QueryAlerts(ctx context.Context, query *repository.AlertQuery) (*repository.AlertPage, error)
+	GetAlert(ctx context.Context, id uuid.UUID) (*models.Alert, error)
 	GetTotalAlertsCount(ctx context.Context) (int64, error)
-	GetFiringAlertsCount(ctx context.Context) (int64, error)
+	GetActiveAlertsCount(ctx context.Context) (int64, error)
 	GetSeverityCounts(ctx context.Context) (*SeverityCounts, error)
+	GetAlertStats(ctx context.Context, query *repository.AlertStatsQuery) ([]*repository.AlertStatsBucket, error)
+	AcknowledgeAlert(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error)
+	UnacknowledgeAlert(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error)
+	ResolveAlert(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error)
+	GetAlertActions(ctx context.Context, id uuid.UUID) ([]*models.AlertAction, error)
 }
 
 type alertService struct {
-	repo repository.AlertRepo
+	repo         repository.AlertRepo
+	stateManager *processor.AlertStateManager
 }
 
-// NewAlertService creates a new alert service
-func NewAlertService(repo repository.AlertRepo) AlertService {
+// NewAlertService creates a new alert service.
+// Operator state transitions go through the state manager so they are serialized
+// with watcher updates and broadcast on the event bus.
+func NewAlertService(repo repository.AlertRepo, stateManager *processor.AlertStateManager) AlertService {
 	return &alertService{
-		repo: repo,
+		repo:         repo,
+		stateManager: stateManager,
 	}
 }
 
@@ -46,12 +60,42 @@ func (s *alertService) GetRecentAlerts(ctx context.Context, limit int) ([]*model
 	return s.repo.GetRecent(ctx, limit)
 }
 
+func (s *alertService) QueryAlerts(ctx context.Context, query *repository.AlertQuery) (*repository.AlertPage, error) {
+	if query.Limit <= 0 || query.Limit > 1000 {
+		query.Limit = 50 // default page size
+	}
+	return s.repo.Query(ctx, query)
+}
+
+func (s *alertService) GetAlert(ctx context.Context, id uuid.UUID) (*models.Alert, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
 func (s *alertService) GetTotalAlertsCount(ctx context.Context) (int64, error) {
 	return s.repo.Count(ctx)
 }
 
-func (s *alertService) GetFiringAlertsCount(ctx context.Context) (int64, error) {
-	return s.repo.CountByStatus(ctx, models.AlertStatusFiring)
+func (s *alertService) GetActiveAlertsCount(ctx context.Context) (int64, error) {
+	return s.repo.CountActive(ctx)
+}
+
+// GetAlertStats counts alerts per time bucket. Without a range it covers the last 24 hours by
+// hour, or the last 30 days by day.
+func (s *alertService) GetAlertStats(ctx context.Context, query *repository.AlertStatsQuery) ([]*repository.AlertStatsBucket, error) {
+	if query.Bucket == "" {
+		query.Bucket = repository.StatsBucketHour
+	}
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.IsZero() {
+		if query.Bucket == repository.StatsBucketDay {
+			query.From = query.To.AddDate(0, 0, -30)
+		} else {
+			query.From = query.To.Add(-24 * time.Hour)
+		}
+	}
+	return s.repo.Stats(ctx, query)
 }
 
 func (s *alertService) GetSeverityCounts(ctx context.Context) (*SeverityCounts, error) {
@@ -78,3 +122,22 @@ func (s *alertService) GetSeverityCounts(ctx context.Context) (*SeverityCounts,
 		Low:      low,
 	}, nil
 }
+
+func (s *alertService) AcknowledgeAlert(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error) {
+	return s.stateManager.Acknowledge(ctx, id, actor, comment)
+}
+
+func (s *alertService) UnacknowledgeAlert(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error) {
+	return s.stateManager.Unacknowledge(ctx, id, actor, comment)
+}
+
+func (s *alertService) ResolveAlert(ctx context.Context, id uuid.UUID, actor, comment string) (*models.Alert, error) {
+	return s.stateManager.Resolve(ctx, id, actor, comment)
+}
+
+func (s *alertService) GetAlertActions(ctx context.Context, id uuid.UUID) ([]*models.AlertAction, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetActions(ctx, id)
+}
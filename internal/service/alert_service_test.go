@@ -10,17 +10,29 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
 	"github.com/monitoring-engine/monitoring-tool/internal/service"
 	"gorm.io/datatypes"
 )
 
 // MockAlertRepo is a mock implementation of AlertRepo for testing
 type MockAlertRepo struct {
-	CreateFunc          func(ctx context.Context, alert *models.Alert) error
-	GetRecentFunc       func(ctx context.Context, limit int) ([]*models.Alert, error)
-	CountFunc           func(ctx context.Context) (int64, error)
-	CountByStatusFunc   func(ctx context.Context, status models.AlertStatus) (int64, error)
-	CountBySeverityFunc func(ctx context.Context, severity string) (int64, error)
+	CreateFunc                 func(ctx context.Context, alert *models.Alert) error
+	GetRecentFunc              func(ctx context.Context, limit int) ([]*models.Alert, error)
+	QueryFunc                  func(ctx context.Context, query *repository.AlertQuery) (*repository.AlertPage, error)
+	CountFunc                  func(ctx context.Context) (int64, error)
+	CountByStatusFunc          func(ctx context.Context, status models.AlertStatus) (int64, error)
+	CountActiveFunc            func(ctx context.Context) (int64, error)
+	CountBySeverityFunc        func(ctx context.Context, severity string) (int64, error)
+	StatsFunc                  func(ctx context.Context, query *repository.AlertStatsQuery) ([]*repository.AlertStatsBucket, error)
+	GetByIDFunc                func(ctx context.Context, id uuid.UUID) (*models.Alert, error)
+	GetActiveByFingerprintFunc func(ctx context.Context, fingerprint string) (*models.Alert, error)
+	GetActiveFunc              func(ctx context.Context) ([]*models.Alert, error)
+	GetActiveByLabelsFunc      func(ctx context.Context, source string, labels map[string]string) ([]*models.Alert, error)
+	UpsertFunc                 func(ctx context.Context, alert *models.Alert) error
+	CreateActionFunc           func(ctx context.Context, action *models.AlertAction) error
+	GetActionsFunc             func(ctx context.Context, alertID uuid.UUID) ([]*models.AlertAction, error)
 }
 
 func (m *MockAlertRepo) Create(ctx context.Context, alert *models.Alert) error {
@@ -37,6 +49,13 @@ func (m *MockAlertRepo) GetRecent(ctx context.Context, limit int) ([]*models.Ale
 	return []*models.Alert{}, nil
 }
 
+func (m *MockAlertRepo) Query(ctx context.Context, query *repository.AlertQuery) (*repository.AlertPage, error) {
+	if m.QueryFunc != nil {
+		return m.QueryFunc(ctx, query)
+	}
+	return &repository.AlertPage{Alerts: []*models.Alert{}}, nil
+}
+
 func (m *MockAlertRepo) Count(ctx context.Context) (int64, error) {
 	if m.CountFunc != nil {
 		return m.CountFunc(ctx)
@@ -51,6 +70,13 @@ func (m *MockAlertRepo) CountByStatus(ctx context.Context, status models.AlertSt
 	return 0, nil
 }
 
+func (m *MockAlertRepo) CountActive(ctx context.Context) (int64, error) {
+	if m.CountActiveFunc != nil {
+		return m.CountActiveFunc(ctx)
+	}
+	return 0, nil
+}
+
 func (m *MockAlertRepo) CountBySeverity(ctx context.Context, severity string) (int64, error) {
 	if m.CountBySeverityFunc != nil {
 		return m.CountBySeverityFunc(ctx, severity)
@@ -58,6 +84,62 @@ func (m *MockAlertRepo) CountBySeverity(ctx context.Context, severity string) (i
 	return 0, nil
 }
 
+func (m *MockAlertRepo) Stats(ctx context.Context, query *repository.AlertStatsQuery) ([]*repository.AlertStatsBucket, error) {
+	if m.StatsFunc != nil {
+		return m.StatsFunc(ctx, query)
+	}
+	return []*repository.AlertStatsBucket{}, nil
+}
+
+func (m *MockAlertRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Alert, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return nil, repository.ErrAlertNotFound
+}
+
+func (m *MockAlertRepo) GetActiveByFingerprint(ctx context.Context, fingerprint string) (*models.Alert, error) {
+	if m.GetActiveByFingerprintFunc != nil {
+		return m.GetActiveByFingerprintFunc(ctx, fingerprint)
+	}
+	return nil, nil
+}
+
+func (m *MockAlertRepo) GetActive(ctx context.Context) ([]*models.Alert, error) {
+	if m.GetActiveFunc != nil {
+		return m.GetActiveFunc(ctx)
+	}
+	return []*models.Alert{}, nil
+}
+
+func (m *MockAlertRepo) GetActiveByLabels(ctx context.Context, source string, labels map[string]string) ([]*models.Alert, error) {
+	if m.GetActiveByLabelsFunc != nil {
+		return m.GetActiveByLabelsFunc(ctx, source, labels)
+	}
+	return []*models.Alert{}, nil
+}
+
+func (m *MockAlertRepo) Upsert(ctx context.Context, alert *models.Alert) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, alert)
+	}
+	return nil
+}
+
+func (m *MockAlertRepo) CreateAction(ctx context.Context, action *models.AlertAction) error {
+	if m.CreateActionFunc != nil {
+		return m.CreateActionFunc(ctx, action)
+	}
+	return nil
+}
+
+func (m *MockAlertRepo) GetActions(ctx context.Context, alertID uuid.UUID) ([]*models.AlertAction, error) {
+	if m.GetActionsFunc != nil {
+		return m.GetActionsFunc(ctx, alertID)
+	}
+	return []*models.AlertAction{}, nil
+}
+
 var _ = Describe("AlertService", func() {
 	var (
 		mockRepo     *MockAlertRepo
@@ -67,7 +149,7 @@ var _ = Describe("AlertService", func() {
 
 	BeforeEach(func() {
 		mockRepo = &MockAlertRepo{}
-		alertService = service.NewAlertService(mockRepo)
+		alertService = service.NewAlertService(mockRepo, processor.NewAlertStateManager(mockRepo, processor.NewEventBus()))
 		ctx = context.Background()
 	})
 
@@ -127,6 +209,22 @@ var _ = Describe("AlertService", func() {
 		})
 	})
 
+	Describe("GetActiveAlertsCount", func() {
+		It("should count the firing and acknowledged alerts", func() {
+			mockRepo.CountActiveFunc = func(ctx context.Context) (int64, error) {
+				return 3, nil
+			}
+			mockRepo.CountByStatusFunc = func(ctx context.Context, status models.AlertStatus) (int64, error) {
+				Fail("active alerts must not be counted by a single status")
+				return 0, nil
+			}
+
+			count, err := alertService.GetActiveAlertsCount(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(3)))
+		})
+	})
+
 	Describe("GetRecentAlerts", func() {
 		Context("with valid limit", func() {
 			It("should return alerts successfully", func() {
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+)
+
+// MaintenanceService handles maintenance window business logic
+type MaintenanceService interface {
+	CreateWindow(ctx context.Context, window *models.MaintenanceWindow) error
+	GetWindow(ctx context.Context, id uuid.UUID) (*models.MaintenanceWindow, error)
+	// ListWindows returns all windows, or only those open right now when activeOnly is set
+	ListWindows(ctx context.Context, activeOnly bool) ([]*models.MaintenanceWindow, error)
+	UpdateWindow(ctx context.Context, window *models.MaintenanceWindow) error
+	DeleteWindow(ctx context.Context, id uuid.UUID) error
+}
+
+type maintenanceService struct {
+	repo repository.MaintenanceRepo
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(repo repository.MaintenanceRepo) MaintenanceService {
+	return &maintenanceService{repo: repo}
+}
+
+func (s *maintenanceService) CreateWindow(ctx context.Context, window *models.MaintenanceWindow) error {
+	if window.ID == uuid.Nil {
+		window.ID = uuid.New()
+	}
+	if err := window.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, window)
+}
+
+func (s *maintenanceService) GetWindow(ctx context.Context, id uuid.UUID) (*models.MaintenanceWindow, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *maintenanceService) ListWindows(ctx context.Context, activeOnly bool) ([]*models.MaintenanceWindow, error) {
+	if !activeOnly {
+		return s.repo.List(ctx)
+	}
+
+	now := time.Now()
+	windows, err := s.repo.GetInEffect(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]*models.MaintenanceWindow, 0, len(windows))
+	for _, window := range windows {
+		if window.IsActive(now) {
+			active = append(active, window)
+		}
+	}
+	return active, nil
+}
+
+func (s *maintenanceService) UpdateWindow(ctx context.Context, window *models.MaintenanceWindow) error {
+	if err := window.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, window)
+}
+
+func (s *maintenanceService) DeleteWindow(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
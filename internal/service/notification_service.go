@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+)
+
+// NotificationService handles notification log business logic
+type NotificationService interface {
+	GetAlertNotifications(ctx context.Context, alertID uuid.UUID) ([]*models.Notification, error)
+	// ListNotifications returns the latest notifications in a state, e.g. dead for the dead letters
+	ListNotifications(ctx context.Context, state models.NotificationState, limit int) ([]*models.Notification, error)
+	// RetryNotification queues a failed or dead notification for delivery again
+	RetryNotification(ctx context.Context, id uuid.UUID) (*models.Notification, error)
+}
+
+type notificationService struct {
+	repo      repository.NotificationRepo
+	alertRepo repository.AlertRepo
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(repo repository.NotificationRepo, alertRepo repository.AlertRepo) NotificationService {
+	return &notificationService{
+		repo:      repo,
+		alertRepo: alertRepo,
+	}
+}
+
+func (s *notificationService) GetAlertNotifications(ctx context.Context, alertID uuid.UUID) ([]*models.Notification, error) {
+	if _, err := s.alertRepo.GetByID(ctx, alertID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByAlert(ctx, alertID)
+}
+
+func (s *notificationService) ListNotifications(ctx context.Context, state models.NotificationState, limit int) ([]*models.Notification, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100 // default limit
+	}
+	return s.repo.List(ctx, state, limit)
+}
+
+func (s *notificationService) RetryNotification(ctx context.Context, id uuid.UUID) (*models.Notification, error) {
+	notification, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := notification.Requeue(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, notification); err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
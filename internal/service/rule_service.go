@@ -0,0 +1,28 @@
+package service
+
+import (
+	"github.com/monitoring-engine/monitoring-tool/internal/processor"
+)
+
+// RuleService exposes the loaded alert rules and the alerts pending on them
+type RuleService interface {
+	ListRules() []processor.Rule
+	ListPendingAlerts() []*processor.PendingAlert
+}
+
+type ruleService struct {
+	ruleEngine *processor.RuleEngine
+}
+
+// NewRuleService creates a new rule service backed by the watchers' rule engine
+func NewRuleService(ruleEngine *processor.RuleEngine) RuleService {
+	return &ruleService{ruleEngine: ruleEngine}
+}
+
+func (s *ruleService) ListRules() []processor.Rule {
+	return s.ruleEngine.Rules()
+}
+
+func (s *ruleService) ListPendingAlerts() []*processor.PendingAlert {
+	return s.ruleEngine.Pending()
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/monitoring-engine/monitoring-tool/internal/models"
+	"github.com/monitoring-engine/monitoring-tool/internal/repository"
+)
+
+// SilenceService handles silence business logic
+type SilenceService interface {
+	CreateSilence(ctx context.Context, silence *models.Silence) error
+	GetSilence(ctx context.Context, id uuid.UUID) (*models.Silence, error)
+	ListSilences(ctx context.Context) ([]*models.Silence, error)
+	UpdateSilence(ctx context.Context, silence *models.Silence) error
+	DeleteSilence(ctx context.Context, id uuid.UUID) error
+}
+
+type silenceService struct {
+	repo repository.SilenceRepo
+}
+
+// NewSilenceService creates a new silence service
+func NewSilenceService(repo repository.SilenceRepo) SilenceService {
+	return &silenceService{repo: repo}
+}
+
+func (s *silenceService) CreateSilence(ctx context.Context, silence *models.Silence) error {
+	if silence.ID == uuid.Nil {
+		silence.ID = uuid.New()
+	}
+	if err := silence.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, silence)
+}
+
+func (s *silenceService) GetSilence(ctx context.Context, id uuid.UUID) (*models.Silence, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *silenceService) ListSilences(ctx context.Context) ([]*models.Silence, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *silenceService) UpdateSilence(ctx context.Context, silence *models.Silence) error {
+	if err := silence.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, silence)
+}
+
+func (s *silenceService) DeleteSilence(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
@@ -137,6 +137,20 @@ func (h *Hub) OnAlert(ctx context.Context, event *processor.AlertEvent) error {
 	return nil
 }
 
+// BroadcastJSON sends a payload of the given message type, e.g. a Kubernetes event, to all clients
+func (h *Hub) BroadcastJSON(msgType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	h.Broadcast(&Message{
+		Type:      msgType,
+		Payload:   data,
+		Timestamp: time.Now(),
+	})
+	return nil
+}
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
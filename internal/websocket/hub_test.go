@@ -172,6 +172,32 @@ func TestHub_OnAlert(t *testing.T) {
 	})
 }
 
+func TestHub_BroadcastJSON(t *testing.T) {
+	t.Run("should send typed payload to connected clients", func(t *testing.T) {
+		hub := websocket.NewHub()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go hub.Run(ctx)
+
+		server := httptest.NewServer(http.HandlerFunc(hub.ServeWS))
+		defer server.Close()
+
+		conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		time.Sleep(50 * time.Millisecond)
+
+		require.NoError(t, hub.BroadcastJSON("k8s_event", map[string]string{"reason": "FailedMount"}))
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		var msg websocket.Message
+		require.NoError(t, conn.ReadJSON(&msg))
+		assert.Equal(t, "k8s_event", msg.Type)
+		assert.JSONEq(t, `{"reason":"FailedMount"}`, string(msg.Payload))
+	})
+}
+
 func TestHub_ServeWS(t *testing.T) {
 	t.Run("should upgrade HTTP connection to WebSocket", func(t *testing.T) {
 		hub := websocket.NewHub()
@@ -0,0 +1,7 @@
+-- Rollback fingerprint-based alert deduplication
+DROP INDEX IF EXISTS idx_alerts_fingerprint_firing;
+DROP INDEX IF EXISTS idx_alerts_fingerprint;
+
+ALTER TABLE alerts DROP COLUMN IF EXISTS last_seen_at;
+ALTER TABLE alerts DROP COLUMN IF EXISTS occurrence_count;
+ALTER TABLE alerts DROP COLUMN IF EXISTS fingerprint;
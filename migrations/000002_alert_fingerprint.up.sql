@@ -0,0 +1,15 @@
+-- Fingerprint-based alert deduplication
+-- Repeat observations of a firing condition update the existing row instead of inserting
+
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS fingerprint VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS occurrence_count INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE alerts SET last_seen_at = triggered_at WHERE last_seen_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_fingerprint ON alerts(fingerprint);
+
+-- At most one firing alert per fingerprint (legacy rows without a fingerprint are exempt)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_fingerprint_firing
+    ON alerts(fingerprint)
+    WHERE status = 'firing' AND fingerprint <> '';
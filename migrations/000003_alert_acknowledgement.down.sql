@@ -0,0 +1,14 @@
+-- Rollback acknowledge workflow
+DROP TABLE IF EXISTS alert_actions CASCADE;
+
+UPDATE alerts SET status = 'firing' WHERE status = 'acknowledged';
+
+DROP INDEX IF EXISTS idx_alerts_fingerprint_active;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_fingerprint_firing
+    ON alerts(fingerprint)
+    WHERE status = 'firing' AND fingerprint <> '';
+
+ALTER TABLE alerts DROP COLUMN IF EXISTS comment;
+ALTER TABLE alerts DROP COLUMN IF EXISTS resolved_by;
+ALTER TABLE alerts DROP COLUMN IF EXISTS acknowledged_by;
+ALTER TABLE alerts DROP COLUMN IF EXISTS acknowledged_at;
@@ -0,0 +1,24 @@
+-- Acknowledge / unacknowledge / manual resolve workflow
+
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS acknowledged_by VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS resolved_by VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS comment TEXT NOT NULL DEFAULT '';
+
+-- An acknowledged alert is still active, so it must keep deduplicating by fingerprint
+DROP INDEX IF EXISTS idx_alerts_fingerprint_firing;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_fingerprint_active
+    ON alerts(fingerprint)
+    WHERE status IN ('firing', 'acknowledged') AND fingerprint <> '';
+
+-- Audit trail of operator actions
+CREATE TABLE IF NOT EXISTS alert_actions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    alert_id UUID NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_alert_actions_alert_id ON alert_actions(alert_id);
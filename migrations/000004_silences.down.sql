@@ -0,0 +1,6 @@
+-- Rollback silences
+DROP INDEX IF EXISTS idx_alerts_suppressed;
+ALTER TABLE alerts DROP COLUMN IF EXISTS silence_id;
+ALTER TABLE alerts DROP COLUMN IF EXISTS suppressed;
+
+DROP TABLE IF EXISTS silences CASCADE;
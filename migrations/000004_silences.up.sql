@@ -0,0 +1,21 @@
+-- Silences: time-bounded label-matcher muting of alerts
+
+CREATE TABLE IF NOT EXISTS silences (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    matchers JSONB NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT silences_ends_after_starts CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_silences_active ON silences(starts_at, ends_at);
+
+-- Silenced alerts are stored but flagged so they are not notified
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS suppressed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS silence_id UUID REFERENCES silences(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_alerts_suppressed ON alerts(suppressed);
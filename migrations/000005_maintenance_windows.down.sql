@@ -0,0 +1,5 @@
+-- Rollback maintenance windows
+ALTER TABLE alerts DROP COLUMN IF EXISTS maintenance_id;
+ALTER TABLE alerts DROP COLUMN IF EXISTS maintenance;
+
+DROP TABLE IF EXISTS maintenance_windows CASCADE;
@@ -0,0 +1,24 @@
+-- Maintenance windows for nodes, namespaces and label selectors
+
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    nodes JSONB,
+    namespaces JSONB,
+    matchers JSONB,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    schedule VARCHAR(100) NOT NULL DEFAULT '',
+    duration_minutes INTEGER NOT NULL DEFAULT 0,
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL,
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_maintenance_windows_bounds ON maintenance_windows(starts_at, ends_at);
+
+-- Alerts raised during maintenance (or on a cordoned node) are stored but not notified
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS maintenance BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS maintenance_id UUID REFERENCES maintenance_windows(id) ON DELETE SET NULL;
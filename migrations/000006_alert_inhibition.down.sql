@@ -0,0 +1,3 @@
+-- Rollback alert inhibition
+ALTER TABLE alerts DROP COLUMN IF EXISTS inhibited_by;
+ALTER TABLE alerts DROP COLUMN IF EXISTS inhibited;
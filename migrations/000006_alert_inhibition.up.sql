@@ -0,0 +1,3 @@
+-- Alerts inhibited by a firing source alert are stored but not notified
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS inhibited BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS inhibited_by UUID REFERENCES alerts(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+-- Rollback notification log
+DROP TABLE IF EXISTS notifications CASCADE;
@@ -0,0 +1,19 @@
+-- Delivery log of alert notifications per channel
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    alert_id UUID NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+    channel VARCHAR(50) NOT NULL,
+    receiver VARCHAR(255) NOT NULL DEFAULT '',
+    event VARCHAR(20) NOT NULL,
+    state VARCHAR(20) NOT NULL DEFAULT 'pending',
+    error TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_alert_id ON notifications(alert_id);
+CREATE INDEX idx_notifications_state ON notifications(state);
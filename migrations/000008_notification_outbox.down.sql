@@ -0,0 +1,6 @@
+-- Rollback notification outbox
+DROP INDEX IF EXISTS idx_notifications_queued;
+
+UPDATE notifications SET state = 'failed' WHERE state = 'dead';
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS next_attempt_at;
@@ -0,0 +1,6 @@
+-- Notifications double as a transactional outbox: queued rows carry the time of their next delivery attempt
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_notifications_queued ON notifications(next_attempt_at)
+    WHERE state IN ('pending', 'failed') AND next_attempt_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Rollback notification payload
+ALTER TABLE notifications DROP COLUMN IF EXISTS payload;
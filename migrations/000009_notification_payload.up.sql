@@ -0,0 +1,3 @@
+-- Queued notifications deliver the alert as it was at the event, not as it is at delivery time
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS payload JSONB;
@@ -0,0 +1,4 @@
+-- Rollback routed notifications
+UPDATE notifications SET state = 'success' WHERE state = 'skipped';
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS group_key;
@@ -0,0 +1,3 @@
+-- Routed notifications are queued per receiver channel; those of one group are delivered together
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS group_key VARCHAR(255) NOT NULL DEFAULT '';
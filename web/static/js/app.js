@@ -151,8 +151,10 @@ function dashboardApp() {
             if (message.type === 'alert' && message.payload) {
                 this.addAlert(message.payload);
 
-                // Show toast for critical and high severity
-                if (message.payload.severity === 'critical' || message.payload.severity === 'high') {
+                // Show recovery notice for resolved alerts, toast for critical and high severity
+                if (message.payload.status === 'resolved') {
+                    this.showToast(`Resolved: ${message.payload.message}`, 'low');
+                } else if (message.payload.severity === 'critical' || message.payload.severity === 'high') {
                     this.showToast(message.payload.message, message.payload.severity);
                 }
             }